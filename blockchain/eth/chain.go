@@ -45,9 +45,34 @@ type Chain struct {
 
 	chainState blockRefChain
 
+	// updateHandler is notified when blocks are added to or replaced in the chain state, after chainLock is released.
+	// updates are the changes made while chainLock is held, updateLock keeps them notified in order
+	updateHandler func(ChainUpdate)
+	updates       []ChainUpdate
+	updateLock    sync.Mutex
+
 	clock utils.RealClock
 }
 
+// BlockRef identifies a block of the chain state
+type BlockRef struct {
+	Height uint64
+	Hash   ethcommon.Hash
+}
+
+// ChainUpdate describes a change of the chain state: the blocks that are no longer canonical and the new canonical
+// blocks, both in ascending height order. Dropped is empty when the chain state is only extended. Blocks that were
+// trimmed from the chain state because the fork was longer than the max reorg length are not part of Dropped
+type ChainUpdate struct {
+	Dropped []BlockRef
+	Added   []BlockRef
+}
+
+// IsReorg returns whether canonical blocks were replaced by the update
+func (u ChainUpdate) IsReorg() bool {
+	return len(u.Dropped) > 0
+}
+
 // BlockSource indicates the origin of a block message in the blockchain
 type BlockSource string
 
@@ -139,7 +164,7 @@ func (c *Chain) cleanBlockStorage(ctx context.Context, cleanInterval time.Durati
 // AddBlock adds the provided block from the source into storage, updating the chainstate if the block comes from a reliable source. AddBlock returns the number of new canonical hashes added to the head if a reorganization happened. TODO: consider computing difficulty in here?
 func (c *Chain) AddBlock(b *BlockInfo, source BlockSource) int {
 	c.chainLock.Lock()
-	defer c.chainLock.Unlock()
+	defer c.unlockAndNotifyUpdates()
 
 	height := b.Block.NumberU64()
 	hash := b.Block.Hash()
@@ -163,7 +188,7 @@ func (c *Chain) AddBlock(b *BlockInfo, source BlockSource) int {
 // ConfirmBlock marks a block as confirmed by a trustworthy source, updating the chain state if possible and returning the number of new canonical hashes added to the head if an update happened.
 func (c *Chain) ConfirmBlock(hash ethcommon.Hash) int {
 	c.chainLock.Lock()
-	defer c.chainLock.Unlock()
+	defer c.unlockAndNotifyUpdates()
 
 	// update metadata
 	bm, ok := c.getBlockMetadata(hash)
@@ -462,8 +487,87 @@ func (c *Chain) HeadHeight() uint64 {
 	return c.chainState.head().height
 }
 
+// OnUpdate sets the handler notified of the changes of the chain state. The handler is called once the chain state is
+// unlocked, in the order of the changes, so it must not add or confirm blocks
+func (c *Chain) OnUpdate(handler func(ChainUpdate)) {
+	c.chainLock.Lock()
+	defer c.chainLock.Unlock()
+	c.updateHandler = handler
+}
+
+// unlockAndNotifyUpdates releases chainLock, and then notifies the changes of the chain state made while it was held
+func (c *Chain) unlockAndNotifyUpdates() {
+	updates, handler := c.updates, c.updateHandler
+	c.updates = nil
+	if len(updates) == 0 || handler == nil {
+		c.chainLock.Unlock()
+		return
+	}
+
+	c.updateLock.Lock()
+	defer c.updateLock.Unlock()
+	c.chainLock.Unlock()
+
+	for _, update := range updates {
+		handler(update)
+	}
+}
+
+// Block returns the stored block with the provided hash
+func (c *Chain) Block(hash ethcommon.Hash) (*ethtypes.Block, bool) {
+	bm, ok := c.getBlockMetadata(hash)
+	if !ok {
+		return nil, false
+	}
+	header, ok := c.getBlockHeader(bm.height, hash)
+	if !ok {
+		return nil, false
+	}
+	body, ok := c.getBlockBody(hash)
+	if !ok {
+		return nil, false
+	}
+	return ethtypes.NewBlockWithHeader(header).WithBody(body.Transactions, body.Uncles), true
+}
+
 // should be called with c.chainLock held
 func (c *Chain) updateChainState(height uint64, hash ethcommon.Hash, parentHash ethcommon.Hash) int {
+	previousChainState := c.chainState
+	newHeads := c.reconcileChainState(height, hash, parentHash)
+	if newHeads > 0 && c.updateHandler != nil {
+		c.updates = append(c.updates, chainStateUpdate(previousChainState, c.chainState[:newHeads]))
+	}
+	return newHeads
+}
+
+// chainStateUpdate compares the previous chain state with the new heads added to it, and returns the entries that were
+// replaced and the new ones
+func chainStateUpdate(previous blockRefChain, newHeads blockRefChain) ChainUpdate {
+	lowest := newHeads[len(newHeads)-1].height
+	added := make(map[ethcommon.Hash]struct{}, len(newHeads))
+	for _, ref := range newHeads {
+		added[ref.hash] = struct{}{}
+	}
+
+	var update ChainUpdate
+	for i := len(previous) - 1; i >= 0; i-- {
+		ref := previous[i]
+		if ref.height < lowest {
+			continue
+		}
+		if _, ok := added[ref.hash]; !ok {
+			update.Dropped = append(update.Dropped, BlockRef{Height: ref.height, Hash: ref.hash})
+		}
+	}
+
+	for i := len(newHeads) - 1; i >= 0; i-- {
+		update.Added = append(update.Added, BlockRef{Height: newHeads[i].height, Hash: newHeads[i].hash})
+	}
+	return update
+}
+
+// should be called with c.chainLock held
+func (c *Chain) reconcileChainState(height uint64, hash ethcommon.Hash, parentHash ethcommon.Hash) int {
 	if len(c.chainState) == 0 {
 		c.chainState = append(c.chainState, blockRef{
 			height: height,
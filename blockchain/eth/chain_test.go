@@ -11,6 +11,7 @@ import (
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestChain_AddBlock(t *testing.T) {
@@ -482,6 +483,54 @@ func TestChain_cleanNoChainstate(t *testing.T) {
 	assert.Zero(t, c.heightToBlockHeaders.Size())
 }
 
+func TestChain_OnUpdate(t *testing.T) {
+	c := newChain(context.Background(), 10, 5, 5, time.Hour, 1000)
+
+	var updates []ChainUpdate
+	c.OnUpdate(func(update ChainUpdate) {
+		// the chain state is unlocked when the update is notified
+		require.True(t, c.chainLock.TryLock())
+		c.chainLock.Unlock()
+		updates = append(updates, update)
+	})
+
+	block1 := bxmock.NewEthBlock(1, common.Hash{})
+	block2a := bxmock.NewEthBlock(2, block1.Hash())
+	block2b := bxmock.NewEthBlock(2, block1.Hash())
+	block3a := bxmock.NewEthBlock(3, block2a.Hash())
+	block3b := bxmock.NewEthBlock(3, block2b.Hash())
+	block4b := bxmock.NewEthBlock(4, block3b.Hash())
+
+	addBlockWithTD(c, block1, block1.Difficulty())
+	addBlock(c, block2a)
+	addBlock(c, block2b)
+	addBlock(c, block3a)
+	addBlock(c, block3b)
+	require.Len(t, updates, 3)
+	for i, block := range []*ethtypes.Block{block1, block2a, block3a} {
+		assert.False(t, updates[i].IsReorg())
+		assert.Equal(t, []BlockRef{{Height: block.NumberU64(), Hash: block.Hash()}}, updates[i].Added)
+	}
+
+	newHeads := addBlock(c, block4b)
+	assert.Equal(t, 3, newHeads)
+	require.Len(t, updates, 4)
+	assert.True(t, updates[3].IsReorg())
+	assert.Equal(t, []BlockRef{{Height: 2, Hash: block2a.Hash()}, {Height: 3, Hash: block3a.Hash()}}, updates[3].Dropped)
+	assert.Equal(t, []BlockRef{{Height: 2, Hash: block2b.Hash()}, {Height: 3, Hash: block3b.Hash()}, {Height: 4, Hash: block4b.Hash()}}, updates[3].Added)
+
+	block, ok := c.Block(block3a.Hash())
+	assert.True(t, ok)
+	assert.Equal(t, block3a.Hash(), block.Hash())
+
+	// extending the canonical chain is not a reorg
+	block5b := bxmock.NewEthBlock(5, block4b.Hash())
+	addBlock(c, block5b)
+	require.Len(t, updates, 5)
+	assert.False(t, updates[4].IsReorg())
+	assert.Equal(t, []BlockRef{{Height: 5, Hash: block5b.Hash()}}, updates[4].Added)
+}
+
 func addBDNBlock(c *Chain, block *ethtypes.Block) int {
 	bi := NewBlockInfo(block, nil)
 	_ = c.SetTotalDifficulty(bi)
@@ -329,6 +329,19 @@ func (bs *BdnPerformanceStats) NodeStats() map[string]*BdnPerformanceStatsData {
 	return bs.nodeStats
 }
 
+// ConnectedNodes returns the number of blockchain nodes currently connected
+func (bs *BdnPerformanceStats) ConnectedNodes() int {
+	bs.lock.Lock()
+	defer bs.lock.Unlock()
+	connected := 0
+	for _, stats := range bs.nodeStats {
+		if stats.IsConnected {
+			connected++
+		}
+	}
+	return connected
+}
+
 func (bs *BdnPerformanceStats) getNodeStats(node types.NodeEndpoint) (*BdnPerformanceStatsData, error) {
 	stats, ok := bs.nodeStats[node.IPPort()]
 	if ok {
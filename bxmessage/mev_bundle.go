@@ -61,6 +61,10 @@ type MEVBundle struct {
 	// From protocol version 41
 	OriginalSenderAccountTier sdnmessage.AccountTier
 	SentFromCloudAPI          bool
+
+	// MaxBlockNumber is the last block the gateway the bundle was submitted to resubmits it for, it is not sent over
+	// the BDN
+	MaxBlockNumber uint64 `json:"-"`
 }
 
 // NewMEVBundle creates a new MEVBundle
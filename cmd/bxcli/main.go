@@ -139,6 +139,17 @@ func main() {
 				Before: beforeBxCli,
 				Action: cmdTxReceipts,
 			},
+			{
+				Name:  "transactionStatus",
+				Usage: "provides a stream of the status updates of the transactions submitted by the account",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name: "auth-header",
+					},
+				},
+				Before: beforeBxCli,
+				Action: cmdTransactionStatus,
+			},
 			{
 				Name:  "blxrtx",
 				Usage: "send paid transaction",
@@ -162,6 +173,15 @@ func main() {
 					&cli.BoolFlag{
 						Name: "front-running-protection",
 					},
+					&cli.StringFlag{
+						Name: "condition",
+					},
+					&cli.Uint64Flag{
+						Name: "condition-expiry-block",
+					},
+					&cli.BoolFlag{
+						Name: "simulate",
+					},
 					&cli.StringFlag{
 						Name: "auth-header",
 					},
@@ -192,6 +212,12 @@ func main() {
 					&cli.BoolFlag{
 						Name: "front-running-protection",
 					},
+					&cli.BoolFlag{
+						Name: "simulate",
+					},
+					&cli.BoolFlag{
+						Name: "atomic",
+					},
 					&cli.StringFlag{
 						Name: "auth-header",
 					},
@@ -312,6 +338,122 @@ func main() {
 				Before: beforeBxCli,
 				Action: cmdShortIDs,
 			},
+			{
+				Name:  "pendingnonces",
+				Usage: "return the pending transactions of an address by nonce and the nonce gaps",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "address",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name: "auth-header",
+					},
+				},
+				Before: beforeBxCli,
+				Action: cmdPendingNonces,
+			},
+			{
+				Name:  "mempoolsnapshot",
+				Usage: "return a page of the pending transactions of the gateway sorted by effective tip",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name: "filters",
+					},
+					&cli.StringFlag{
+						Name: "cursor",
+					},
+					&cli.UintFlag{
+						Name: "limit",
+					},
+					&cli.StringFlag{
+						Name: "auth-header",
+					},
+				},
+				Before: beforeBxCli,
+				Action: cmdMempoolSnapshot,
+			},
+			{
+				Name:  "gasestimate",
+				Usage: "return the fees recommended by the gateway for each inclusion probability",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name: "auth-header",
+					},
+				},
+				Before: beforeBxCli,
+				Action: cmdGasEstimate,
+			},
+			{
+				Name:  "builderhealth",
+				Usage: "return the health of the MEV builder endpoints the gateway sent bundles to",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name: "auth-header",
+					},
+				},
+				Before: beforeBxCli,
+				Action: cmdBuilderHealth,
+			},
+			{
+				Name:  "bundlestatus",
+				Usage: "return whether the transactions of a bundle submitted to the gateway were included by its target block",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "bundle-hash",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name: "auth-header",
+					},
+				},
+				Before: beforeBxCli,
+				Action: cmdBundleStatus,
+			},
+			{
+				Name:  "feebumptxstatus",
+				Usage: "return the progress of a transaction replacement submitted to the gateway",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "replacement-id",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name: "auth-header",
+					},
+				},
+				Before: beforeBxCli,
+				Action: cmdFeeBumpTxStatus,
+			},
+			{
+				Name:  "conditionaltxs",
+				Usage: "return the transactions held by the gateway until their condition matches a block",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name: "tx-hash",
+					},
+					&cli.StringFlag{
+						Name: "auth-header",
+					},
+				},
+				Before: beforeBxCli,
+				Action: cmdConditionalTxs,
+			},
+			{
+				Name:  "cancelconditionaltx",
+				Usage: "drop a transaction held by the gateway until its condition matches a block",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "tx-hash",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name: "auth-header",
+					},
+				},
+				Before: beforeBxCli,
+				Action: cmdCancelConditionalTx,
+			},
 		},
 		Flags: []cli.Flag{
 			utils.GRPCHostFlag,
@@ -778,6 +920,36 @@ func cmdTxReceipts(ctx *cli.Context) error {
 	return nil
 }
 
+func cmdTransactionStatus(ctx *cli.Context) error {
+	err := rpc.GatewayConsoleCall(
+		config.NewGRPCFromCLI(ctx),
+		func(callCtx context.Context, client pb.GatewayClient) (interface{}, error) {
+			stream, err := client.TransactionStatus(callCtx, &pb.TransactionStatusRequest{})
+			if err != nil {
+				return nil, err
+			}
+			for {
+				txStatus, err := stream.Recv()
+				if err == io.EOF {
+					fmt.Println("transactionStatus error EOF: ", err)
+					break
+				}
+				if err != nil {
+					fmt.Println("transactionStatus error in recv: ", err)
+					break
+				}
+				fmt.Println(txStatus)
+			}
+			return nil, nil
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("err subscribing to transactionStatus: %v", err)
+	}
+
+	return nil
+}
+
 func cmdBlxrTX(ctx *cli.Context) error {
 	err := rpc.GatewayConsoleCall(
 		config.NewGRPCFromCLI(ctx),
@@ -790,6 +962,9 @@ func cmdBlxrTX(ctx *cli.Context) error {
 					Fallback:               int32(ctx.Int("fallback")),
 					NodeValidation:         ctx.Bool("node-validation"),
 					FrontrunningProtection: ctx.Bool("front-running-protection"),
+					Condition:              ctx.String("condition"),
+					ConditionExpiryBlock:   ctx.Uint64("condition-expiry-block"),
+					Simulate:               ctx.Bool("simulate"),
 				},
 			)
 		},
@@ -850,6 +1025,8 @@ func cmdBlxrBatchTX(ctx *cli.Context) error {
 				NodeValidation:         ctx.Bool("node-validation"),
 				FrontrunningProtection: ctx.Bool("front-running-protection"),
 				SendingTime:            time.Now().UnixNano(),
+				Simulate:               ctx.Bool("simulate"),
+				Atomic:                 ctx.Bool("atomic"),
 			})
 		},
 	)
@@ -933,3 +1110,111 @@ func cmdShortIDs(ctx *cli.Context) error {
 
 	return nil
 }
+
+func cmdPendingNonces(ctx *cli.Context) error {
+	err := rpc.GatewayConsoleCall(
+		config.NewGRPCFromCLI(ctx),
+		func(callCtx context.Context, client pb.GatewayClient) (interface{}, error) {
+			return client.PendingNonces(callCtx, &pb.PendingNoncesRequest{Address: ctx.String("address")})
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("could not get pending nonces: %v", err)
+	}
+	return nil
+}
+
+func cmdMempoolSnapshot(ctx *cli.Context) error {
+	err := rpc.GatewayConsoleCall(
+		config.NewGRPCFromCLI(ctx),
+		func(callCtx context.Context, client pb.GatewayClient) (interface{}, error) {
+			return client.MempoolSnapshot(callCtx, &pb.MempoolSnapshotRequest{
+				Filters: ctx.String("filters"),
+				Cursor:  ctx.String("cursor"),
+				Limit:   uint32(ctx.Uint("limit")),
+			})
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("could not get mempool snapshot: %v", err)
+	}
+	return nil
+}
+
+func cmdGasEstimate(ctx *cli.Context) error {
+	err := rpc.GatewayConsoleCall(
+		config.NewGRPCFromCLI(ctx),
+		func(callCtx context.Context, client pb.GatewayClient) (interface{}, error) {
+			return client.GasEstimate(callCtx, &pb.GasEstimateRequest{})
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("could not get gas estimate: %v", err)
+	}
+	return nil
+}
+
+func cmdBuilderHealth(ctx *cli.Context) error {
+	err := rpc.GatewayConsoleCall(
+		config.NewGRPCFromCLI(ctx),
+		func(callCtx context.Context, client pb.GatewayClient) (interface{}, error) {
+			return client.BuilderHealth(callCtx, &pb.BuilderHealthRequest{})
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("could not get builder health: %v", err)
+	}
+	return nil
+}
+
+func cmdBundleStatus(ctx *cli.Context) error {
+	err := rpc.GatewayConsoleCall(
+		config.NewGRPCFromCLI(ctx),
+		func(callCtx context.Context, client pb.GatewayClient) (interface{}, error) {
+			return client.BundleStatus(callCtx, &pb.BundleStatusRequest{BundleHash: ctx.String("bundle-hash")})
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("could not get bundle status: %v", err)
+	}
+	return nil
+}
+
+func cmdFeeBumpTxStatus(ctx *cli.Context) error {
+	err := rpc.GatewayConsoleCall(
+		config.NewGRPCFromCLI(ctx),
+		func(callCtx context.Context, client pb.GatewayClient) (interface{}, error) {
+			return client.FeeBumpTxStatus(callCtx, &pb.FeeBumpTxStatusRequest{ReplacementId: ctx.String("replacement-id")})
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("could not get fee bump status: %v", err)
+	}
+	return nil
+}
+
+func cmdConditionalTxs(ctx *cli.Context) error {
+	err := rpc.GatewayConsoleCall(
+		config.NewGRPCFromCLI(ctx),
+		func(callCtx context.Context, client pb.GatewayClient) (interface{}, error) {
+			return client.ConditionalTxs(callCtx, &pb.ConditionalTxsRequest{TxHash: ctx.String("tx-hash")})
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("could not get conditional transactions: %v", err)
+	}
+	return nil
+}
+
+func cmdCancelConditionalTx(ctx *cli.Context) error {
+	err := rpc.GatewayConsoleCall(
+		config.NewGRPCFromCLI(ctx),
+		func(callCtx context.Context, client pb.GatewayClient) (interface{}, error) {
+			return client.CancelConditionalTx(callCtx, &pb.CancelConditionalTxRequest{TxHash: ctx.String("tx-hash")})
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("could not cancel conditional transaction: %v", err)
+	}
+	return nil
+}
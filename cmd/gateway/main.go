@@ -23,6 +23,7 @@ import (
 	"github.com/bloXroute-Labs/gateway/v2/config"
 	log "github.com/bloXroute-Labs/gateway/v2/logger"
 	"github.com/bloXroute-Labs/gateway/v2/nodes"
+	"github.com/bloXroute-Labs/gateway/v2/services/metrics"
 	"github.com/bloXroute-Labs/gateway/v2/types"
 	"github.com/bloXroute-Labs/gateway/v2/utils"
 	"github.com/bloXroute-Labs/gateway/v2/utils/httpclient"
@@ -43,6 +44,7 @@ func main() {
 			utils.WSPortFlag,
 			utils.WSHostFlag,
 			utils.HTTPPortFlag,
+			utils.MetricsPortFlag,
 			utils.EnvFlag,
 			utils.LogLevelFlag,
 			utils.LogFileLevelFlag,
@@ -52,6 +54,7 @@ func main() {
 			utils.TxTraceEnabledFlag,
 			utils.TxTraceMaxFileSizeFlag,
 			utils.TxTraceMaxBackupFilesFlag,
+			utils.TxTraceOTLPEndpointFlag,
 			utils.AvoidPrioritySendingFlag,
 			utils.RelayHostsFlag,
 			utils.DataDirFlag,
@@ -77,10 +80,17 @@ func main() {
 			utils.DisableProfilingFlag,
 			utils.FluentDFlag,
 			utils.FluentdHostFlag,
+			utils.StatsBackendFlag,
+			utils.StatsFileFlag,
+			utils.StatsFileMaxSizeFlag,
+			utils.StatsFileMaxBackupsFlag,
+			utils.StatsNATSURLFlag,
+			utils.StatsKafkaURLFlag,
 			utils.ManageWSServer,
 			utils.LogNetworkContentFlag,
 			utils.WSTLSFlag,
 			utils.MEVBuildersFilePathFlag,
+			utils.MEVBuildersReloadIntervalFlag,
 			utils.MEVMaxProfitBuilder,
 			utils.MEVBundleMethodNameFlag,
 			utils.SendBlockConfirmation,
@@ -102,6 +112,13 @@ func main() {
 			utils.BlocksToCacheWhileProposing,
 			utils.ProposingInterval,
 			utils.TxIncludeSenderInFeed,
+			utils.ReorgReemitFeeds,
+			utils.FeedHistorySize,
+			utils.FeedHistoryFeeds,
+			utils.PersistTxStore,
+			utils.SanctionsListFlag,
+			utils.SanctionsListReloadIntervalFlag,
+			utils.BlockSanctionedTxsFlag,
 		},
 		Action: runGateway,
 	}
@@ -131,6 +148,20 @@ func runGateway(c *cli.Context) error {
 		})
 	}
 
+	var metricsServer *http.Server
+	if metricsPort := c.Int(utils.MetricsPortFlag.Name); metricsPort != 0 {
+		metricsServer = &http.Server{Addr: fmt.Sprintf(":%v", metricsPort), Handler: metrics.Handler()}
+		group.Go(func() error {
+			log.Infof("metrics http server is running on %v", metricsServer.Addr)
+
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return fmt.Errorf("failed to start metrics http server: %v", err)
+			}
+
+			return nil
+		})
+	}
+
 	bxConfig, err := config.NewBxFromCLI(c)
 	if err != nil {
 		return err
@@ -214,6 +245,9 @@ func runGateway(c *cli.Context) error {
 		return fmt.Errorf("if blockchan rpc is enabled, a valid websocket address must be provided")
 	}
 
+	// Required for beacon node and prysm to sync, the gateway follows the canonical chain with it as well
+	ethChain := eth.NewChain(ctx, ethConfig.IgnoreBlockTimeout)
+
 	gateway, err := nodes.NewGateway(
 		ctx,
 		bxConfig,
@@ -233,6 +267,7 @@ func runGateway(c *cli.Context) error {
 		c.Int(utils.BlocksToCacheWhileProposing.Name),
 		c.Duration(utils.ProposingInterval.Name),
 		c.Bool(utils.TxIncludeSenderInFeed.Name),
+		ethChain,
 	)
 	if err != nil {
 		return err
@@ -242,9 +277,6 @@ func runGateway(c *cli.Context) error {
 		return gateway.Run()
 	})
 
-	// Required for beacon node and prysm to sync
-	ethChain := eth.NewChain(ctx, ethConfig.IgnoreBlockTimeout)
-
 	var blockchainServer *eth.Server
 	if startupBlockchainClient {
 		log.Infof("starting blockchain client with config for network ID: %v", ethConfig.Network)
@@ -341,6 +373,12 @@ func runGateway(c *cli.Context) error {
 		}
 	}
 
+	if metricsServer != nil {
+		if err = metricsServer.Shutdown(shutdownCtx); err != nil {
+			log.Errorf("error shutting down metrics server: %v", err)
+		}
+	}
+
 	if blockchainServer != nil {
 		log.Infof("stopping blockchain client...")
 		blockchainServer.Stop()
@@ -1,13 +1,12 @@
 package config
 
 import (
-	"encoding/json"
 	"errors"
-	"fmt"
-	"os"
+	"strings"
 	"time"
 
 	"github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/services/statistics"
 	"github.com/bloXroute-Labs/gateway/v2/utils"
 	"github.com/bloXroute-Labs/gateway/v2/utils/bundle"
 	"github.com/urfave/cli/v2"
@@ -34,6 +33,7 @@ type Bx struct {
 	LogNetworkContent  bool
 	FluentDEnabled     bool
 	FluentDHost        string
+	Stats              statistics.SinkConfig
 
 	Relays string
 
@@ -49,6 +49,9 @@ type Bx struct {
 	SendConfirmation    bool
 	MEVMaxProfitBuilder bool
 	MEVBuilders         map[string]*bundle.Builder
+	// MEVBuildersFilePath is watched for changes and reloaded every MEVBuildersReloadInterval
+	MEVBuildersFilePath       string
+	MEVBuildersReloadInterval time.Duration
 
 	ProcessMegaBundle            bool
 	MevMinerSendBundleMethodName string
@@ -60,6 +63,13 @@ type Bx struct {
 	NoTxsToBlockchain            bool
 	NoBlocks                     bool
 	NoStats                      bool
+	ReorgReemitFeeds             bool
+	FeedHistorySize              int
+	FeedHistoryFeeds             string
+	PersistTxStore               bool
+	SanctionsList                string
+	SanctionsListReloadInterval  time.Duration
+	BlockSanctionedTxs           bool
 
 	*GRPC
 	*Env
@@ -94,15 +104,20 @@ func NewBxFromCLI(ctx *cli.Context) (*Bx, error) {
 		}
 	}
 
+	statsBackends, err := statistics.ParseBackends(ctx.String(utils.StatsBackendFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+	// --fluentd keeps sending the stats to fluentd without listing it as a backend
+	if ctx.Bool(utils.FluentDFlag.Name) {
+		statsBackends, _ = statistics.ParseBackends(statistics.FluentdBackend + "," + strings.Join(statsBackends, ","))
+	}
+
 	var mevBuilders map[string]*bundle.Builder
 	if ctx.IsSet(utils.MEVBuildersFilePathFlag.Name) {
-		contents, err := os.ReadFile(ctx.String(utils.MEVBuildersFilePathFlag.Name))
+		mevBuilders, err = bundle.LoadBuilders(ctx.String(utils.MEVBuildersFilePathFlag.Name))
 		if err != nil {
-			return nil, fmt.Errorf("failed to open mev builders file: %s", err)
-		}
-
-		if err := json.Unmarshal(contents, &mevBuilders); err != nil {
-			return nil, fmt.Errorf("failed to decode mev builders file: %s", err)
+			return nil, err
 		}
 	}
 
@@ -119,6 +134,15 @@ func NewBxFromCLI(ctx *cli.Context) (*Bx, error) {
 		LogNetworkContent:  ctx.Bool(utils.LogNetworkContentFlag.Name),
 		FluentDEnabled:     ctx.Bool(utils.FluentDFlag.Name),
 		FluentDHost:        ctx.String(utils.FluentdHostFlag.Name),
+		Stats: statistics.SinkConfig{
+			Backends:       statsBackends,
+			FluentDHost:    ctx.String(utils.FluentdHostFlag.Name),
+			FilePath:       ctx.String(utils.StatsFileFlag.Name),
+			FileMaxSizeMB:  ctx.Int(utils.StatsFileMaxSizeFlag.Name),
+			FileMaxBackups: ctx.Int(utils.StatsFileMaxBackupsFlag.Name),
+			NATSURL:        ctx.String(utils.StatsNATSURLFlag.Name),
+			KafkaURL:       ctx.String(utils.StatsKafkaURLFlag.Name),
+		},
 
 		WebsocketEnabled:    ctx.Bool(utils.WSFlag.Name),
 		WebsocketTLSEnabled: ctx.Bool(utils.WSTLSFlag.Name),
@@ -132,18 +156,27 @@ func NewBxFromCLI(ctx *cli.Context) (*Bx, error) {
 		SendConfirmation: ctx.Bool(utils.SendBlockConfirmation.Name),
 		AllTransactions:  ctx.Bool(utils.AllTransactionsFlag.Name),
 
-		MEVBuilders:         mevBuilders,
-		MEVMaxProfitBuilder: ctx.Bool(utils.MEVMaxProfitBuilder.Name),
-
-		ProcessMegaBundle:          ctx.Bool(utils.MegaBundleProcessing.Name),
-		ForwardTransactionEndpoint: ctx.String(utils.ForwardTransactionEndpoint.Name),
-		ForwardTransactionMethod:   ctx.String(utils.ForwardTransactionMethod.Name),
-		EnableDynamicPeers:         ctx.Bool(utils.EnableDynamicPeers.Name),
-		EnableBlockchainRPC:        ctx.Bool(utils.EnableBlockchainRPCMethodSupport.Name),
-		PendingTxsSourceFromNode:   ctx.Bool(utils.PendingTxsSourceFromNode.Name),
-		NoTxsToBlockchain:          ctx.Bool(utils.NoTxsToBlockchain.Name),
-		NoBlocks:                   ctx.Bool(utils.NoBlocks.Name),
-		NoStats:                    ctx.Bool(utils.NoStats.Name),
+		MEVBuilders:               mevBuilders,
+		MEVBuildersReloadInterval: ctx.Duration(utils.MEVBuildersReloadIntervalFlag.Name),
+		MEVMaxProfitBuilder:       ctx.Bool(utils.MEVMaxProfitBuilder.Name),
+
+		ProcessMegaBundle:            ctx.Bool(utils.MegaBundleProcessing.Name),
+		MevMinerSendBundleMethodName: ctx.String(utils.MEVBundleMethodNameFlag.Name),
+		ForwardTransactionEndpoint:   ctx.String(utils.ForwardTransactionEndpoint.Name),
+		ForwardTransactionMethod:     ctx.String(utils.ForwardTransactionMethod.Name),
+		EnableDynamicPeers:           ctx.Bool(utils.EnableDynamicPeers.Name),
+		EnableBlockchainRPC:          ctx.Bool(utils.EnableBlockchainRPCMethodSupport.Name),
+		PendingTxsSourceFromNode:     ctx.Bool(utils.PendingTxsSourceFromNode.Name),
+		NoTxsToBlockchain:            ctx.Bool(utils.NoTxsToBlockchain.Name),
+		NoBlocks:                     ctx.Bool(utils.NoBlocks.Name),
+		NoStats:                      ctx.Bool(utils.NoStats.Name),
+		ReorgReemitFeeds:             ctx.Bool(utils.ReorgReemitFeeds.Name),
+		FeedHistorySize:              ctx.Int(utils.FeedHistorySize.Name),
+		FeedHistoryFeeds:             ctx.String(utils.FeedHistoryFeeds.Name),
+		PersistTxStore:               ctx.Bool(utils.PersistTxStore.Name),
+		SanctionsList:                ctx.String(utils.SanctionsListFlag.Name),
+		SanctionsListReloadInterval:  ctx.Duration(utils.SanctionsListReloadIntervalFlag.Name),
+		BlockSanctionedTxs:           ctx.Bool(utils.BlockSanctionedTxsFlag.Name),
 
 		GRPC:       grpcConfig,
 		Env:        env,
@@ -151,6 +184,10 @@ func NewBxFromCLI(ctx *cli.Context) (*Bx, error) {
 		TxTraceLog: txTraceLog,
 	}
 
+	if ctx.IsSet(utils.MEVBuildersFilePathFlag.Name) {
+		bxConfig.MEVBuildersFilePath = ctx.String(utils.MEVBuildersFilePathFlag.Name)
+	}
+
 	if bxConfig.BlocksOnly && bxConfig.AllTransactions {
 		return bxConfig, errors.New("cannot set both --blocks-only and --all-txs")
 	}
@@ -13,6 +13,7 @@ type TxTraceLog struct {
 	Enabled        bool
 	MaxFileSize    int
 	MaxBackupFiles int
+	OTLPEndpoint   string
 }
 
 // NewLogFromCLI builds new log configuration from the CLI context
@@ -40,6 +41,7 @@ func NewLogFromCLI(ctx *cli.Context) (*log.Config, *TxTraceLog, error) {
 		Enabled:        ctx.Bool(utils.TxTraceEnabledFlag.Name),
 		MaxFileSize:    ctx.Int(utils.TxTraceMaxFileSizeFlag.Name),
 		MaxBackupFiles: ctx.Int(utils.TxTraceMaxBackupFilesFlag.Name),
+		OTLPEndpoint:   ctx.String(utils.TxTraceOTLPEndpointFlag.Name),
 	}
 
 	return &logConfig, &txTraceConfig, nil
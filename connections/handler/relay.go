@@ -7,6 +7,7 @@ import (
 	"github.com/bloXroute-Labs/gateway/v2/connections"
 	log "github.com/bloXroute-Labs/gateway/v2/logger"
 	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
+	"github.com/bloXroute-Labs/gateway/v2/services/metrics"
 	"github.com/bloXroute-Labs/gateway/v2/types"
 	"github.com/bloXroute-Labs/gateway/v2/utils"
 )
@@ -17,6 +18,7 @@ type Relay struct {
 	networks      *sdnmessage.BlockchainNetworks
 	sendSyncReq   bool
 	syncDoneCount uint32
+	synced        uint32
 	endpoint      types.NodeEndpoint
 }
 
@@ -66,9 +68,28 @@ func NewRelay(node connections.BxListener,
 	}
 	r.BxConn = NewBxConn(node, connect, r, sslCerts, relayIP, relayPort, nodeID, relayType,
 		usePQ, true, localGEO, privateNetwork, localPort, clock, sameRegion)
+	metrics.RelayConnectionState.SetSource(r.endpoint.IPPort(), r.connectionState)
 	return r
 }
 
+// Close closes the relay connection for good and stops reporting its state
+func (r *Relay) Close(reason string) error {
+	metrics.RelayConnectionState.Delete(r.endpoint.IPPort())
+	return r.BxConn.Close(reason)
+}
+
+// connectionState reports whether the relay is connected and done syncing the tx store
+func (r *Relay) connectionState() float64 {
+	switch {
+	case !r.IsOpen():
+		return metrics.RelayDisconnected
+	case atomic.LoadUint32(&r.synced) == 1:
+		return metrics.RelaySynced
+	default:
+		return metrics.RelayConnected
+	}
+}
+
 // NodeEndpoint return the blockchain connection endpoint
 func (r *Relay) NodeEndpoint() types.NodeEndpoint {
 	return r.endpoint
@@ -94,6 +115,7 @@ func (r *Relay) ProcessMessage(msgBytes bxmessage.MessageBytes) {
 	case bxmessage.HelloType:
 		r.BxConn.ProcessMessage(msgBytes)
 		r.syncDoneCount = 0
+		atomic.StoreUint32(&r.synced, 0)
 		if !r.sendSyncReq {
 			break
 		}
@@ -121,6 +143,7 @@ func (r *Relay) ProcessMessage(msgBytes bxmessage.MessageBytes) {
 		atomic.AddUint32(&r.syncDoneCount, 1)
 		if atomic.CompareAndSwapUint32(&r.syncDoneCount, uint32(len(*r.networks)), 0) {
 			r.Log().Debugf("TxStore sync: done for %v networks", len(*r.networks))
+			atomic.StoreUint32(&r.synced, 1)
 			_ = r.Node.HandleMsg(syncDone, r, connections.RunBackground)
 		}
 	case bxmessage.RefreshBlockchainNetworkType:
@@ -25,6 +25,12 @@ const (
 
 	// Blocked - blocked
 	Blocked RPCErrorCode = -32001
+
+	// Sanctioned - transaction involves sanctioned addresses
+	Sanctioned RPCErrorCode = -32005
+
+	// Reverted - transaction reverted when simulated
+	Reverted RPCErrorCode = -32006
 )
 
 // ErrorMsg is a mapping of codes to error messages
@@ -34,4 +40,6 @@ var ErrorMsg = map[RPCErrorCode]string{
 	AccountIDError: "Invalid account ID",
 	InternalError:  "Internal error",
 	Blocked:        "Insufficient quota",
+	Sanctioned:     "Sanctioned address",
+	Reverted:       "Transaction reverted",
 }
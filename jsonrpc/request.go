@@ -29,10 +29,17 @@ const (
 	RPCStartMonitoringTx          RPCRequestType = "start_monitor_transaction"
 	RPCStopMonitoringTx           RPCRequestType = "stop_monitor_transaction"
 	RPCFeeBumpTx                  RPCRequestType = "blxr_tx_fee_bump"
+	RPCFeeBumpTxStatus            RPCRequestType = "blxr_tx_fee_bump_status"
 	RPCChangeNewPendingTxFromNode RPCRequestType = "new_pending_txs_source_from_node"
 	RPCEthSubscribe               RPCRequestType = "eth_subscribe"
 	RPCEthSendRawTransaction      RPCRequestType = "eth_sendRawTransaction"
 	RPCEthUnsubscribe             RPCRequestType = "eth_unsubscribe"
+	RPCPendingNonces              RPCRequestType = "blxr_pending_nonces"
+	RPCConditionalTxs             RPCRequestType = "blxr_conditional_txs"
+	RPCCancelConditionalTx        RPCRequestType = "blxr_cancel_conditional_tx"
+	RPCMempoolSnapshot            RPCRequestType = "blxr_mempool_snapshot"
+	RPCGasEstimate                RPCRequestType = "blxr_gas_estimate"
+	RPCBundleStatus               RPCRequestType = "blxr_bundle_status"
 )
 
 // External RPCRequestType enumeration
@@ -60,6 +67,11 @@ type RPCTxPayload struct {
 	OriginalRPCMethod       RPCRequestType `json:"original_rpc_method"`
 	NodeValidation          bool           `json:"node_validation"`
 	FrontRunningProtection  bool           `json:"front_running_protection"`
+	// Condition holds the transaction in the gateway until a block matches it, or until ConditionExpiryBlock
+	Condition            string `json:"condition"`
+	ConditionExpiryBlock uint64 `json:"condition_expiry_block"`
+	// Simulate rejects the transaction if it reverts with eth_call at the latest block of the node
+	Simulate bool `json:"simulate"`
 }
 
 // RPCBatchTxPayload is the payload of blxr_batch_tx request
@@ -68,6 +80,10 @@ type RPCBatchTxPayload struct {
 	ValidatorsOnly          bool     `json:"validators_only"`
 	BlockchainNetwork       string   `json:"blockchain_network"`
 	OriginalSenderAccountID string   `json:"original_sender_account_id"`
+	// Simulate rejects the transactions which revert with eth_call at the latest block of the node, each of them is simulated on its own
+	Simulate bool `json:"simulate"`
+	// Atomic sends none of the transactions if one of them fails validation, and stops at the first one which fails to be sent
+	Atomic bool `json:"atomic"`
 }
 
 type rpcTxJSON struct {
@@ -81,6 +97,9 @@ type rpcTxJSON struct {
 	OriginalRPCMethod       RPCRequestType `json:"original_rpc_method"`
 	NodeValidation          bool           `json:"node_validation"`
 	FrontRunningProtection  bool           `json:"front_running_protection"`
+	Condition               string         `json:"condition"`
+	ConditionExpiryBlock    uint64         `json:"condition_expiry_block"`
+	Simulate                bool           `json:"simulate"`
 }
 
 // UnmarshalJSON provides a compatibility layer for go-ethereum style RPC calls, which are [object], instead of just object.
@@ -112,10 +131,64 @@ func (p *RPCTxPayload) UnmarshalJSON(b []byte) error {
 	p.NodeValidation = payload.NodeValidation
 	p.FrontRunningProtection = payload.FrontRunningProtection
 	p.MevBundleTx = payload.MevBundleTx
+	p.Condition = payload.Condition
+	p.ConditionExpiryBlock = payload.ConditionExpiryBlock
+	p.Simulate = payload.Simulate
 
 	return nil
 }
 
+// RPCFeeBumpTxPayload is the payload of blxr_tx_fee_bump request. Transactions are signed replacements of each other,
+// ordered by increasing fees, and each of them waits BlockInterval blocks, or its value in Schedule, before the next one is sent
+type RPCFeeBumpTxPayload struct {
+	Transactions            []string `json:"transactions"`
+	BlockInterval           uint64   `json:"block_interval"`
+	Schedule                []uint64 `json:"schedule"`
+	BlockchainNetwork       string   `json:"blockchain_network"`
+	OriginalSenderAccountID string   `json:"original_sender_account_id"`
+}
+
+// RPCFeeBumpTxStatusPayload is the payload of blxr_tx_fee_bump_status request
+type RPCFeeBumpTxStatusPayload struct {
+	ReplacementID string `json:"replacementId"`
+}
+
+// RPCPendingNoncesPayload is the payload of blxr_pending_nonces request
+type RPCPendingNoncesPayload struct {
+	Address string `json:"address"`
+}
+
+// RPCConditionalTxsPayload is the payload of blxr_conditional_txs request, all the held transactions are returned if TxHash is empty
+type RPCConditionalTxsPayload struct {
+	TxHash string `json:"tx_hash"`
+}
+
+// RPCCancelConditionalTxPayload is the payload of blxr_cancel_conditional_tx request
+type RPCCancelConditionalTxPayload struct {
+	TxHash string `json:"tx_hash"`
+}
+
+// RPCMempoolSnapshotPayload is the payload of blxr_mempool_snapshot request, a new snapshot is taken if Cursor is empty
+type RPCMempoolSnapshotPayload struct {
+	Filters string   `json:"filters"`
+	Include []string `json:"include"`
+	Cursor  string   `json:"cursor"`
+	Limit   int      `json:"limit"`
+}
+
+// RPCBundleStatusPayload is the payload of blxr_bundle_status request
+type RPCBundleStatusPayload struct {
+	BundleHash string `json:"bundle_hash"`
+}
+
+// RPCMonitorTxPayload is the payload of start_monitor_transaction and stop_monitor_transaction requests
+type RPCMonitorTxPayload struct {
+	TransactionHashes []string `json:"transaction_hashes"`
+}
+
+// MaxBundleTargetBlocks bounds the blocks a bundle is submitted for, with targetBlocks or maxBlockNumber
+const MaxBundleTargetBlocks = 25
+
 // RPCBundleSubmissionPayload is the payload of blxr_submit_bundle request
 type RPCBundleSubmissionPayload struct {
 	BlockchainNetwork       string            `json:"blockchain_network"`
@@ -130,6 +203,12 @@ type RPCBundleSubmissionPayload struct {
 	BundlePrice             int64             `json:"bundlePrice,omitempty"` // in wei
 	EnforcePayout           bool              `json:"enforcePayout,omitempty"`
 	OriginalSenderAccountID string            `json:"original_sender_account_id"`
+	// TargetBlocks is the number of consecutive blocks from BlockNumber the gateway submits the bundle for, until its
+	// transactions are included
+	TargetBlocks int `json:"targetBlocks,omitempty"`
+	// MaxBlockNumber is the last block the gateway submits the bundle for, until its transactions are included. It is
+	// an alternative to TargetBlocks
+	MaxBlockNumber string `json:"maxBlockNumber,omitempty"`
 }
 
 // Validate doing validation for blxr_submit_bundle payload
@@ -148,6 +227,9 @@ func (p RPCBundleSubmissionPayload) Validate() error {
 	if p.MaxTimestamp < 0 {
 		return errors.New("max timestamp must be greater than or equal to 0")
 	}
+	if p.TargetBlocks < 0 || p.TargetBlocks > MaxBundleTargetBlocks {
+		return fmt.Errorf("targetBlocks must be between 0 and %v", MaxBundleTargetBlocks)
+	}
 
 	if p.UUID != "" {
 		_, err := uuid.FromString(p.UUID)
@@ -156,15 +238,62 @@ func (p RPCBundleSubmissionPayload) Validate() error {
 		}
 	}
 
-	_, err := hexutil.DecodeUint64(p.BlockNumber)
+	blockNumber, err := hexutil.DecodeUint64(p.BlockNumber)
 	if err != nil {
 		return fmt.Errorf("blockNumber must be hex, %v", err)
 	}
 
+	if p.MaxBlockNumber != "" {
+		if p.TargetBlocks != 0 {
+			return errors.New("targetBlocks and maxBlockNumber cannot be both set")
+		}
+		maxBlockNumber, err := hexutil.DecodeUint64(p.MaxBlockNumber)
+		if err != nil {
+			return fmt.Errorf("maxBlockNumber must be hex, %v", err)
+		}
+		if maxBlockNumber < blockNumber || maxBlockNumber-blockNumber >= MaxBundleTargetBlocks {
+			return fmt.Errorf("maxBlockNumber must be between blockNumber and %v blocks after it", MaxBundleTargetBlocks-1)
+		}
+	}
+
 	return nil
 
 }
 
+// MaxBundleSimulationTxs bounds the transactions of a simulated bundle, each of them is executed by the blockchain node
+const MaxBundleSimulationTxs = 100
+
+// RPCBundleSimulationPayload is the payload of blxr_simulate_bundle request. The bundle is simulated on the state of
+// StateBlockNumber, the latest block if empty
+type RPCBundleSimulationPayload struct {
+	Transaction      []string `json:"transaction"`
+	BlockNumber      string   `json:"block_number"`
+	StateBlockNumber string   `json:"state_block_number"`
+	Timestamp        int64    `json:"timestamp"`
+}
+
+// Validate doing validation for blxr_simulate_bundle payload
+func (p RPCBundleSimulationPayload) Validate() error {
+	if len(p.Transaction) == 0 {
+		return errors.New("bundle missing txs")
+	}
+	if len(p.Transaction) > MaxBundleSimulationTxs {
+		return fmt.Errorf("bundle has more than %v txs", MaxBundleSimulationTxs)
+	}
+	if _, err := hexutil.DecodeUint64(p.BlockNumber); err != nil {
+		return fmt.Errorf("block_number must be hex, %v", err)
+	}
+	if p.StateBlockNumber != "" && p.StateBlockNumber != "latest" {
+		if _, err := hexutil.DecodeUint64(p.StateBlockNumber); err != nil {
+			return fmt.Errorf("state_block_number must be hex or latest, %v", err)
+		}
+	}
+	if p.Timestamp < 0 {
+		return errors.New("timestamp must be greater than or equal to 0")
+	}
+	return nil
+}
+
 // RPCMEVSearcherPayload is the payload of blxr_searcher request
 // Depreceted: use RPCBundleSubmissionPayload instead. Will be removed in the future.
 type RPCMEVSearcherPayload struct {
@@ -186,6 +315,8 @@ type RPCSendBundle struct {
 	Frontrunning      bool     `json:"frontrunning,omitempty"`
 	BundlePrice       int64    `json:"bundlePrice,omitempty"` // in wei
 	EnforcePayout     bool     `json:"enforcePayout,omitempty"`
+	ReplacementUUID   string   `json:"replacementUuid,omitempty"`
+	RefundPercent     *int     `json:"refundPercent,omitempty"`
 }
 
 // RPCCancelBundlePayload custom json-rpc required to cancel flashbots bundle
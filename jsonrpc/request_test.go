@@ -48,3 +48,26 @@ func TestRPCBatchTxPayload_UnmarshalJSON(t *testing.T) {
 	assert.NoError(t, err)
 
 }
+
+func TestRPCBundleSubmissionPayload_Validate(t *testing.T) {
+	payload := RPCBundleSubmissionPayload{Transaction: []string{"0x01"}, BlockNumber: "0x64", MaxBlockNumber: "0x70"}
+	assert.NoError(t, payload.Validate())
+
+	payload.TargetBlocks = 2
+	assert.Error(t, payload.Validate())
+
+	payload.TargetBlocks = 0
+	payload.MaxBlockNumber = "0x63"
+	assert.Error(t, payload.Validate())
+
+	payload.MaxBlockNumber = "0x7d"
+	assert.Error(t, payload.Validate())
+}
+
+func TestRPCBundleSimulationPayload_Validate(t *testing.T) {
+	payload := RPCBundleSimulationPayload{Transaction: make([]string, MaxBundleSimulationTxs), BlockNumber: "0x64"}
+	assert.NoError(t, payload.Validate())
+
+	payload.Transaction = append(payload.Transaction, "0x01")
+	assert.Error(t, payload.Validate())
+}
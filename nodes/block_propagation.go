@@ -0,0 +1,62 @@
+package nodes
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/services/metrics"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+)
+
+// blockPropagationTimeout is how long a block waits for the other source before it is forgotten
+const blockPropagationTimeout = time.Minute
+
+type blockFirstSeen struct {
+	source   string
+	time     time.Time
+	reported bool
+}
+
+// blockPropagationTracker measures the delay between the BDN and the blockchain node delivering the same block
+type blockPropagationTracker struct {
+	clock utils.Clock
+	lock  sync.Mutex
+	seen  map[types.SHA256Hash]blockFirstSeen
+}
+
+func newBlockPropagationTracker(clock utils.Clock) *blockPropagationTracker {
+	return &blockPropagationTracker{
+		clock: clock,
+		seen:  make(map[types.SHA256Hash]blockFirstSeen),
+	}
+}
+
+// observe records a block received from a source, once the other source delivers the block the delay between
+// both is reported to the block propagation metric labeled with the first source
+func (t *blockPropagationTracker) observe(hash types.SHA256Hash, source string) (time.Duration, bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := t.clock.Now()
+	for h, first := range t.seen {
+		if now.Sub(first.time) > blockPropagationTimeout {
+			delete(t.seen, h)
+		}
+	}
+
+	first, ok := t.seen[hash]
+	if !ok {
+		t.seen[hash] = blockFirstSeen{source: source, time: now}
+		return 0, false
+	}
+	if first.source == source || first.reported {
+		return 0, false
+	}
+
+	first.reported = true
+	t.seen[hash] = first
+	delay := now.Sub(first.time)
+	metrics.BlockPropagationDelay.WithLabelValues(first.source).Observe(delay.Seconds())
+	return delay, true
+}
@@ -0,0 +1,42 @@
+package nodes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/services/metrics"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockPropagationTracker(t *testing.T) {
+	clock := utils.NewMockClock()
+	tracker := newBlockPropagationTracker(clock)
+
+	first := types.SHA256Hash{1}
+	_, ok := tracker.observe(first, metrics.SourceBDN)
+	assert.False(t, ok)
+
+	// the same source delivering the block again is not a propagation
+	clock.IncTime(10 * time.Millisecond)
+	_, ok = tracker.observe(first, metrics.SourceBDN)
+	assert.False(t, ok)
+
+	clock.IncTime(40 * time.Millisecond)
+	delay, ok := tracker.observe(first, metrics.SourceNode)
+	assert.True(t, ok)
+	assert.Equal(t, 50*time.Millisecond, delay)
+
+	// the delay is reported once per block
+	_, ok = tracker.observe(first, metrics.SourceNode)
+	assert.False(t, ok)
+
+	// blocks which the other source never delivers are forgotten
+	second := types.SHA256Hash{2}
+	tracker.observe(second, metrics.SourceNode)
+	clock.IncTime(2 * blockPropagationTimeout)
+	_, ok = tracker.observe(second, metrics.SourceBDN)
+	assert.False(t, ok)
+	assert.Len(t, tracker.seen, 1)
+}
@@ -32,11 +32,14 @@ import (
 	"github.com/prysmaticlabs/prysm/v4/consensus-types/interfaces"
 	"github.com/sirupsen/logrus"
 	"github.com/sourcegraph/jsonrpc2"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/atomic"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/bloXroute-Labs/gateway/v2"
 	"github.com/bloXroute-Labs/gateway/v2/blockchain"
@@ -55,10 +58,13 @@ import (
 	"github.com/bloXroute-Labs/gateway/v2/servers"
 	"github.com/bloXroute-Labs/gateway/v2/services"
 	"github.com/bloXroute-Labs/gateway/v2/services/loggers"
+	"github.com/bloXroute-Labs/gateway/v2/services/metrics"
 	"github.com/bloXroute-Labs/gateway/v2/services/statistics"
+	"github.com/bloXroute-Labs/gateway/v2/services/tracing"
 	"github.com/bloXroute-Labs/gateway/v2/types"
 	"github.com/bloXroute-Labs/gateway/v2/utils"
 	"github.com/bloXroute-Labs/gateway/v2/utils/bundle"
+	"github.com/bloXroute-Labs/gateway/v2/utils/ofac"
 	"github.com/bloXroute-Labs/gateway/v2/utils/orderedmap"
 	"github.com/bloXroute-Labs/gateway/v2/utils/syncmap"
 	"github.com/bloXroute-Labs/gateway/v2/version"
@@ -71,10 +77,14 @@ const (
 	mainnetBloomCap        = 85e5
 	polygonMainnetBloomCap = 225e5
 
-	bloomStoreInterval = time.Hour
+	bloomStoreInterval   = time.Hour
+	txStoreStoreInterval = 5 * time.Minute
 
 	accountsCacheManagerExpDur   = 5 * time.Minute
 	accountsCacheManagerCleanDur = 30 * time.Minute
+
+	// chainUpdatesChannelSize bounds the changes of the canonical chain waiting to be processed by the gateway
+	chainUpdatesChannelSize = 100
 )
 
 var (
@@ -98,9 +108,11 @@ type gateway struct {
 	feedManager        *servers.FeedManager
 	feedManagerChan    chan types.Notification
 	asyncMsgChannel    chan services.MsgInfo
+	chainUpdates       chan eth.ChainUpdate
 	isBDN              bool
 	bdnStats           *bxmessage.BdnPerformanceStats
 	blockProcessor     services.BlockProcessor
+	pendingNonces      *services.PendingNonceTracker
 	pendingTxs         services.HashHistory
 	possiblePendingTxs services.HashHistory
 	txTrace            loggers.TxTrace
@@ -108,11 +120,22 @@ type gateway struct {
 	stats              statistics.Stats
 	bdnBlocks          services.HashHistory
 	newBlocks          services.HashHistory
+	canonicalChain     *eth.Chain
+	canonicalLogs      *services.CanonicalLogs
 	wsManager          blockchain.WSManager
 	syncedWithRelay    atomic.Bool
+	txStoreStarted     atomic.Bool
 	clock              utils.Clock
 	timeStarted        time.Time
 	burstLimiter       services.AccountBurstLimiter
+	blockPropagation   *blockPropagationTracker
+	txReplacements     *services.TxReplacementManager
+	conditionalTxs     *services.ConditionalTxStore
+	txStatuses         *services.TxStatusTracker
+	mempoolSnapshots   *servers.MempoolSnapshots
+	gasOracle          *services.GasOracle
+	bundleTracker      *services.BundleTracker
+	bundleResubmitter  *services.BundleResubmitter
 
 	bestBlockHeight       int
 	bdnBlocksSkipCount    int
@@ -197,6 +220,7 @@ func NewGateway(parent context.Context,
 	blocksToCacheWhileProposing int,
 	proposingInterval time.Duration,
 	txIncludeSenderInFeed bool,
+	chain *eth.Chain,
 ) (Node, error) {
 	clock := utils.RealClock{}
 	blockTime, _ := bxgateway.NetworkToBlockDuration[bxConfig.BlockchainNetwork]
@@ -212,6 +236,7 @@ func NewGateway(parent context.Context,
 		possiblePendingTxs:           services.NewHashHistory("possiblePendingTxs", 15*time.Minute),
 		bdnBlocks:                    services.NewHashHistory("bdnBlocks", 15*time.Minute),
 		newBlocks:                    services.NewHashHistory("newBlocks", 15*time.Minute),
+		canonicalChain:               chain,
 		seenMEVBundles:               services.NewHashHistory("mevBundle", 30*time.Minute),
 		seenMEVMinerBundles:          services.NewHashHistory("mevMinerBundle", 30*time.Minute),
 		seenMEVSearchers:             services.NewHashHistory("mevSearcher", 30*time.Minute),
@@ -233,6 +258,9 @@ func NewGateway(parent context.Context,
 		intentsManager: newIntentsManager(),
 	}
 	g.chainID = int64(bxgateway.NetworkNumToChainID[sdn.NetworkNum()])
+	g.canonicalLogs = services.NewCanonicalLogs(g.notify)
+	g.chainUpdates = make(chan eth.ChainUpdate, chainUpdatesChannelSize)
+	g.canonicalChain.OnUpdate(g.queueChainUpdate)
 
 	g.blockProposer = services.NewNoopBlockProposer(&g.TxStore, log.WithField("service", "noop-block-proposer"))
 
@@ -268,16 +296,26 @@ func NewGateway(parent context.Context,
 	// create tx store service pass to eth client
 	g.bdnStats = bxmessage.NewBDNStats(blockchainPeers, recommendedPeers)
 	g.burstLimiter = services.NewAccountBurstLimiter(g.clock)
+	g.blockPropagation = newBlockPropagationTracker(g.clock)
+	g.txReplacements = services.NewTxReplacementManager(g.notify)
+	g.conditionalTxs = services.NewConditionalTxStore(g.clock, g.notify)
+	g.txStatuses = services.NewTxStatusTracker(g.clock, g.txStatusUpdate, g.notify)
+	g.bundleTracker = services.NewBundleTracker(g.clock, g.notify)
+	g.bundleResubmitter = services.NewBundleResubmitter(g.handleMEVBundleMessage)
 
 	if g.BxConfig.NoStats {
 		g.stats = statistics.NoStats{}
 	} else {
-		g.stats = statistics.NewStats(g.BxConfig.FluentDEnabled, g.BxConfig.FluentDHost, g.sdn.NodeID(), g.sdn.Networks(), g.BxConfig.LogNetworkContent)
+		stats, err := statistics.NewStats(g.BxConfig.Stats, g.sdn.NodeID(), g.sdn.Networks(), g.BxConfig.LogNetworkContent)
+		if err != nil {
+			return nil, err
+		}
+		g.stats = stats
 	}
 
-	g.mevBundleDispatcher = bundle.NewDispatcher(g.stats, bxConfig.MEVBuilders, bxConfig.MEVMaxProfitBuilder, bxConfig.ProcessMegaBundle)
-	g.txsQueue = services.NewMsgQueue(runtime.NumCPU()*2, bxgateway.ParallelQueueChannelSize, g.msgAdapter)
-	g.txsOrderQueue = services.NewMsgQueue(1, bxgateway.ParallelQueueChannelSize, g.msgAdapter)
+	g.mevBundleDispatcher = bundle.NewDispatcher(g.stats, bxConfig.MEVBuilders, bxConfig.MevMinerSendBundleMethodName, bxConfig.MEVMaxProfitBuilder, bxConfig.ProcessMegaBundle)
+	g.txsQueue = services.NewMsgQueue(metrics.QueueTxs, runtime.NumCPU()*2, bxgateway.ParallelQueueChannelSize, g.msgAdapter)
+	g.txsOrderQueue = services.NewMsgQueue(metrics.QueueTxsOrder, 1, bxgateway.ParallelQueueChannelSize, g.msgAdapter)
 
 	if enableBloomFilter {
 		var bloomCap uint32
@@ -354,8 +392,13 @@ func (g *gateway) setSyncWithRelay() {
 
 func (g *gateway) setupTxStore() {
 	assigner := services.NewEmptyShortIDAssigner()
-	g.TxStore = services.NewEthTxStore(g.clock, 30*time.Minute, 3*24*time.Hour, 10*time.Minute,
+	txStore := services.NewEthTxStore(g.clock, 30*time.Minute, 3*24*time.Hour, 10*time.Minute,
 		assigner, services.NewHashHistory("seenTxs", 30*time.Minute), nil, *g.sdn.Networks(), g.bloomFilter)
+	if g.BxConfig.PersistTxStore {
+		txStore.EnablePersistence(g.BxConfig.DataDir, txStoreStoreInterval)
+	}
+	g.TxStore = txStore
+	g.pendingNonces = txStore.PendingNonceTracker()
 	g.blockProcessor = services.NewBlockProcessor(g.TxStore)
 }
 
@@ -407,16 +450,9 @@ func InitSDN(bxConfig *config.Bx, blockchainPeers []types.NodeEndpoint, gatewayP
 
 	accountModel := sdn.AccountModel()
 
-	accountBuilders := make(map[string]bool)
-	for _, builder := range accountModel.MEVBuilders {
-		accountBuilders[builder] = true
-	}
-
 	// Check if the account is allowed to run the mev builder
-	for builder := range bxConfig.MEVBuilders {
-		if !accountBuilders[builder] {
-			return nil, nil, fmt.Errorf("account %v is not allowed to run %v mev builder, closing the gateway. Please contact support@bloxroute.com to enable running this mev builder", accountModel.AccountID, builder)
-		}
+	if err = checkAccountBuilders(accountModel, bxConfig.MEVBuilders); err != nil {
+		return nil, nil, fmt.Errorf("%v, closing the gateway. Please contact support@bloxroute.com to enable running this mev builder", err)
 	}
 
 	if uint64(staticEnodesCount) < uint64(accountModel.MinAllowedNodes.MsgQuota.Limit) {
@@ -445,6 +481,21 @@ func InitSDN(bxConfig *config.Bx, blockchainPeers []types.NodeEndpoint, gatewayP
 	return &sslCerts, sdn, nil
 }
 
+// checkAccountBuilders returns an error if the account is not allowed to run one of the mev builders
+func checkAccountBuilders(accountModel sdnmessage.Account, builders map[string]*bundle.Builder) error {
+	accountBuilders := make(map[string]bool)
+	for _, builder := range accountModel.MEVBuilders {
+		accountBuilders[builder] = true
+	}
+
+	for builder := range builders {
+		if !accountBuilders[builder] {
+			return fmt.Errorf("account %v is not allowed to run %v mev builder", accountModel.AccountID, builder)
+		}
+	}
+	return nil
+}
+
 func (g *gateway) Run() error {
 	group, ctx := errgroup.WithContext(g.context)
 
@@ -469,6 +520,7 @@ func (g *gateway) Run() error {
 	g.setupTxStore()
 
 	g.burstLimiter.Register(&accountModel)
+	g.setMetricsSources()
 	var err error
 	var txTraceLogger *log.Logger = nil
 	if g.BxConfig.TxTraceLog.Enabled {
@@ -486,6 +538,37 @@ func (g *gateway) Run() error {
 		}
 	}
 	g.txTrace = loggers.NewTxTrace(txTraceLogger)
+	if g.BxConfig.TxTraceLog.Enabled && g.BxConfig.TxTraceLog.OTLPEndpoint != "" {
+		shutdownTracing, err := tracing.Init(g.context, g.BxConfig.TxTraceLog.OTLPEndpoint, nodeID)
+		if err != nil {
+			return fmt.Errorf("failed to start transaction tracing: %v", err)
+		}
+		go func() {
+			<-g.context.Done()
+			if err := shutdownTracing(context.Background()); err != nil {
+				g.log.Errorf("failed to flush transaction traces: %v", err)
+			}
+		}()
+	}
+
+	if g.BxConfig.MEVBuildersFilePath != "" {
+		err = bundle.WatchBuilders(g.context, g.BxConfig.MEVBuildersFilePath, g.BxConfig.MEVBuildersReloadInterval, func(builders map[string]*bundle.Builder) error {
+			if err := checkAccountBuilders(g.sdn.AccountModel(), builders); err != nil {
+				return err
+			}
+			g.mevBundleDispatcher.SetBuilders(builders)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if g.BxConfig.SanctionsList != "" {
+		if err = ofac.Watch(g.context, ofac.NewProvider(g.BxConfig.SanctionsList), g.BxConfig.SanctionsListReloadInterval); err != nil {
+			return err
+		}
+	}
 
 	networkNum := g.sdn.NetworkNum()
 
@@ -503,6 +586,12 @@ func (g *gateway) Run() error {
 		})
 	}
 
+	group.Go(func() error {
+		g.handleChainUpdates(ctx)
+		return nil
+	})
+
+	g.txStoreStarted.Store(true)
 	go g.TxStore.Start()
 	go g.updateValidatorStateMap()
 
@@ -519,9 +608,20 @@ func (g *gateway) Run() error {
 		g.wsManager, accountModel, g.sdn.FetchCustomerAccountModel,
 		sslCert.PrivateCertFile(), sslCert.PrivateKeyFile(), *g.BxConfig, g.stats, g.nextValidatorMap, g.validatorStatusMap,
 	)
+	g.feedManager.SetPendingNonceTracker(g.pendingNonces)
+	g.feedManager.SetTxReplacementManager(g.txReplacements)
+	g.feedManager.SetConditionalTxStore(g.conditionalTxs)
+	g.feedManager.SetTxStatusTracker(g.txStatuses)
+	g.feedManager.SetBundleTracker(g.bundleTracker)
 
 	txFromFieldIncludable := blockchainNetwork.EnableCheckSenderNonce || g.txIncludeSenderInFeed
 
+	g.mempoolSnapshots = servers.NewMempoolSnapshots(g.clock, g.TxStore, g.pendingNonces, txFromFieldIncludable)
+	g.feedManager.SetMempoolSnapshots(g.mempoolSnapshots)
+
+	g.gasOracle = services.NewGasOracle(g.clock, g.TxStore, g.pendingNonces)
+	g.feedManager.SetGasOracle(g.gasOracle)
+
 	g.grpcHandler = servers.NewGrpcHandler(g.feedManager, g.wsManager, txFromFieldIncludable)
 
 	// start feed manager if websocket or gRPC is enabled
@@ -597,11 +697,24 @@ func (g *gateway) Close() error {
 		g.grpcServer.Stop()
 	}
 
+	// stopping the tx store stores its transactions when the persistence is enabled
+	if g.txStoreStarted.Load() {
+		g.TxStore.Stop()
+	}
+
+	var err error
 	if g.clientHandler != nil {
-		return g.clientHandler.Stop()
+		err = g.clientHandler.Stop()
 	}
 
-	return nil
+	// the stats backends are closed last, once nothing records stats anymore
+	if g.stats != nil {
+		if statsErr := g.stats.Close(); statsErr != nil {
+			g.log.Errorf("failed to close the stats backends: %v", statsErr)
+		}
+	}
+
+	return err
 }
 
 func (g *gateway) updateRelayConnections(relayInstructions chan connections.RelayInstruction, sslCerts utils.SSLCerts, networkNum types.NetworkNum) {
@@ -993,6 +1106,11 @@ func (g *gateway) notifyBlockFeeds(bxBlock *types.BxBlock, nodeSource *connectio
 			// Waits response from node WS provider
 			// Because it is in goroutine time will not be present in handleDuration
 			go g.notifyTxReceiptsAndOnBlockFeeds(nodeSource, ethNotification)
+
+			if g.gasOracle != nil {
+				g.gasOracle.OnBlock(block)
+				go g.notifyGasEstimate()
+			}
 		} else {
 			l.Trace("duplicate ETH block for bdnBlocks")
 		}
@@ -1052,6 +1170,137 @@ func (g *gateway) notifyBlockFeeds(bxBlock *types.BxBlock, nodeSource *connectio
 	return nil
 }
 
+// addCanonicalBlock stores a block of the BDN in the canonical chain, which does not make it canonical. The blocks of
+// the blockchain node are added by the blockchain backend
+func (g *gateway) addCanonicalBlock(bxBlock *types.BxBlock) {
+	if g.canonicalChain.HasBlock(common.BytesToHash(bxBlock.Hash().Bytes())) {
+		return
+	}
+
+	blockSrc, err := g.bridge.BlockBDNtoBlockchain(bxBlock)
+	if err != nil {
+		g.log.Debugf("can't add block %v to the canonical chain: %v", bxBlock, err)
+		return
+	}
+
+	var info *eth.BlockInfo
+	switch b := blockSrc.(type) {
+	case interfaces.ReadOnlySignedBeaconBlock:
+		block, err := eth.BeaconBlockToEthBlock(b)
+		if err != nil {
+			g.log.Debugf("can't add beacon block %v to the canonical chain: %v", bxBlock, err)
+			return
+		}
+		info = eth.NewBlockInfo(block, bxBlock.TotalDifficulty)
+	case *eth.BlockInfo:
+		info = b
+	default:
+		return
+	}
+
+	g.canonicalChain.AddBlock(info, eth.BSBDN)
+}
+
+// queueChainUpdate passes a change of the canonical chain to handleChainUpdates. It is called by the canonical chain on
+// the path adding the blocks of the blockchain node, so it does not wait for the gateway to process the change
+func (g *gateway) queueChainUpdate(update eth.ChainUpdate) {
+	select {
+	case g.chainUpdates <- update:
+	default:
+		g.log.Errorf("canonical chain update channel is full, ignoring the update to block %v", update.Added[len(update.Added)-1].Hash)
+	}
+}
+
+// handleChainUpdates processes the changes of the canonical chain in their order until ctx is done
+func (g *gateway) handleChainUpdates(ctx context.Context) {
+	for {
+		select {
+		case update := <-g.chainUpdates:
+			g.handleChainUpdate(update)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// handleChainUpdate follows the changes of the canonical chain: the logs of the new canonical blocks are published, and
+// the ones of the dropped blocks published again as removed
+func (g *gateway) handleChainUpdate(update eth.ChainUpdate) {
+	if update.IsReorg() {
+		g.handleReorg(update)
+	}
+	g.canonicalLogs.OnChainUpdate(reorgBlocks(update.Dropped), reorgBlocks(update.Added))
+
+	for _, ref := range update.Added {
+		block, ok := g.canonicalChain.Block(ref.Hash)
+		if !ok {
+			g.log.Debugf("can't process canonical block %v (height %v), block is not stored", ref.Hash, ref.Height)
+			continue
+		}
+		g.onCanonicalBlock(block)
+	}
+}
+
+// onCanonicalBlock settles the transactions tracked by the gateway with the blocks of the canonical chain
+func (g *gateway) onCanonicalBlock(block *ethtypes.Block) {
+	g.bundleTracker.OnBlock(block)
+	g.bundleResubmitter.OnBlock(block)
+	g.conditionalTxs.OnBlock(block)
+	g.txStatuses.OnBlock(block)
+	g.txReplacements.OnBlock(block)
+}
+
+// handleReorg notifies the reorg feed of a reorganization of the canonical chain
+func (g *gateway) handleReorg(reorg eth.ChainUpdate) {
+	head := reorg.Added[len(reorg.Added)-1]
+	g.log.Infof("chain reorganization to block %v (height %v), %v blocks dropped, %v blocks added", head.Hash, head.Height, len(reorg.Dropped), len(reorg.Added))
+
+	g.notify(types.NewReorgNotification(reorgBlocks(reorg.Dropped), reorgBlocks(reorg.Added)))
+
+	if g.BxConfig.ReorgReemitFeeds {
+		// the new head is being published, only the blocks that were published while they were not canonical are sent again
+		go g.reemitCanonicalBlocks(reorg.Added[:len(reorg.Added)-1])
+	}
+}
+
+func (g *gateway) reemitCanonicalBlocks(refs []eth.BlockRef) {
+	for _, ref := range refs {
+		block, ok := g.canonicalChain.Block(ref.Hash)
+		if !ok {
+			g.log.Debugf("can't send txReceipts and ethOnBlock again for block %v (height %v), block is not stored", ref.Hash, ref.Height)
+			continue
+		}
+		ethNotification, err := types.NewEthBlockNotification(ref.Hash, block, nil, g.txIncludeSenderInFeed)
+		if err != nil {
+			g.log.Errorf("failed to create notification for block %v (height %v): %v", ref.Hash, ref.Height, err)
+			continue
+		}
+		g.notifyTxReceiptsAndOnBlockFeeds(nil, ethNotification)
+	}
+}
+
+func reorgBlocks(refs []eth.BlockRef) []types.ReorgBlock {
+	blocks := make([]types.ReorgBlock, 0, len(refs))
+	for _, ref := range refs {
+		blocks = append(blocks, types.ReorgBlock{Hash: ref.Hash.String(), Height: ref.Height})
+	}
+	return blocks
+}
+
+// notifyGasEstimate publishes the fees recommended after a block to the gas estimate feed, the pending transactions are
+// only read if the feed has subscribers
+func (g *gateway) notifyGasEstimate() {
+	if !g.feedManager.SubscriptionTypeExists(types.GasEstimateFeed) {
+		return
+	}
+	estimate, err := g.gasOracle.Estimate()
+	if err != nil {
+		g.log.Debugf("gas estimate is not available: %v", err)
+		return
+	}
+	g.notify(estimate)
+}
+
 func (g *gateway) notifyTxReceiptsAndOnBlockFeeds(nodeSource *connections.Blockchain, ethNotification *types.EthBlockNotification) {
 	var nodeEndpoint *types.NodeEndpoint
 	if nodeSource != nil { // from blockchain node
@@ -1074,13 +1323,18 @@ func (g *gateway) notifyTxReceiptsAndOnBlockFeeds(nodeSource *connections.Blockc
 	notification = ethNotification.Clone()
 	notification.SetSource(&sourceEndpoint)
 
-	if g.feedManager.SubscriptionTypeExists(types.TxReceiptsFeed) {
+	receiptsFeed := g.feedManager.SubscriptionTypeExists(types.TxReceiptsFeed) || g.feedManager.HistoryEnabled(types.TxReceiptsFeed)
+	logsFeed := g.feedManager.SubscriptionTypeExists(types.EthLogsFeed)
+	if receiptsFeed || logsFeed {
 		receipts, err := servers.HandleTxReceipts(g.feedManager, notification.(*types.EthBlockNotification))
 		if err != nil {
 			log.Printf("failed to handle tx receipts: %v", err)
 			return
 		}
-		if len(receipts) > 0 {
+		if logsFeed {
+			g.canonicalLogs.OnReceipts(ethNotification.BlockHash.String(), ethNotification.Header.GetNumber(), receipts)
+		}
+		if receiptsFeed && len(receipts) > 0 {
 			g.notify(types.NewTxReceiptsNotification(receipts))
 		}
 	}
@@ -1228,7 +1482,7 @@ func (g *gateway) handleBridgeMessages(ctx context.Context) error {
 func (g *gateway) NodeStatus() connections.NodeStatus {
 	var capabilities types.CapabilityFlags
 
-	if len(g.BxConfig.MEVBuilders) > 0 {
+	if g.mevBundleDispatcher.HasBuilders() {
 		capabilities |= types.CapabilityMEVBuilder
 	}
 
@@ -1467,6 +1721,23 @@ func (g *gateway) processValidatorUpdate(msg *bxmessage.ValidatorUpdates, source
 	}
 }
 
+// sendTransactionsToNodes passes transactions to the bridge for the delivery to the blockchain nodes
+func (g *gateway) sendTransactionsToNodes(txs blockchain.Transactions) error {
+	spans := make([]trace.Span, 0, len(txs.Transactions))
+	for _, tx := range txs.Transactions {
+		spans = append(spans, tracing.StartSpan(tx.Hash(), "send_to_nodes"))
+	}
+
+	err := g.bridge.SendTransactionsFromBDN(txs)
+	for _, span := range spans {
+		if err != nil {
+			span.SetStatus(otelcodes.Error, err.Error())
+		}
+		span.End()
+	}
+	return err
+}
+
 func (g *gateway) processTransaction(tx *bxmessage.Tx, source connections.Conn) {
 	var (
 		sentToBDN            bool
@@ -1480,6 +1751,10 @@ func (g *gateway) processTransaction(tx *bxmessage.Tx, source connections.Conn)
 	)
 
 	startTime := time.Now()
+	tracing.StartSpan(tx.Hash(), "message_queue", trace.WithTimestamp(startTime.Add(-tx.WaitDuration()))).End(trace.WithTimestamp(startTime))
+	span := tracing.StartSpan(tx.Hash(), "process_transaction", trace.WithTimestamp(startTime))
+	defer span.End()
+
 	peerIP := source.GetPeerIP()
 	peerPort := source.GetPeerPort()
 	sourceEndpoint := types.NodeEndpoint{IP: peerIP, Port: int(peerPort), PublicKey: source.GetPeerEnode()}
@@ -1563,7 +1838,10 @@ func (g *gateway) processTransaction(tx *bxmessage.Tx, source connections.Conn)
 					tx.SetSender(txResult.Transaction.Sender())
 					// set timestamp so relay can analyze communication delay
 					tx.SetTimestamp(g.clock.Now())
+					broadcastSpan := tracing.StartSpan(tx.Hash(), "broadcast")
 					broadcastRes = g.broadcast(tx, source, utils.RelayTransaction)
+					broadcastSpan.SetAttributes(tracing.PeersKey.Int(broadcastRes.SentPeers))
+					broadcastSpan.End()
 					sentToBDN = true
 				}
 			}
@@ -1624,7 +1902,7 @@ func (g *gateway) processTransaction(tx *bxmessage.Tx, source connections.Conn)
 							return
 						}
 
-						err := g.bridge.SendTransactionsFromBDN(txsToDeliverToNodes)
+						err := g.sendTransactionsToNodes(txsToDeliverToNodes)
 						if err != nil {
 							l.Errorf("failed to send transaction from BDN to bridge: %v", err)
 						}
@@ -1639,7 +1917,7 @@ func (g *gateway) processTransaction(tx *bxmessage.Tx, source connections.Conn)
 						}).Debug("tx sent to blockchain with front run protection delay")
 					})
 				} else {
-					err := g.bridge.SendTransactionsFromBDN(txsToDeliverToNodes)
+					err := g.sendTransactionsToNodes(txsToDeliverToNodes)
 					if err != nil {
 						l.Errorf("failed to send transaction from BDN to bridge: %v", err)
 					}
@@ -1674,6 +1952,17 @@ func (g *gateway) processTransaction(tx *bxmessage.Tx, source connections.Conn)
 		}
 	}
 
+	if !txResult.FailedValidation {
+		g.txStatuses.OnTransaction(services.TxStatusUpdate{
+			Hash:       tx.Hash(),
+			Sender:     txResult.Transaction.Sender(),
+			Nonce:      txResult.Nonce,
+			FromNode:   connectionType == utils.Blockchain,
+			SentToBDN:  sentToBDN,
+			SentToNode: sentToBlockchainNode,
+		})
+	}
+
 	statsStart := time.Now()
 	g.stats.AddTxsByShortIDsEvent(eventName, source, txResult.Transaction, tx.ShortID(), nodeID, broadcastRes.RelevantPeers, broadcastRes.SentGatewayPeers, startTime, tx.GetPriority(), txResult.DebugData)
 	statsDuration := time.Since(statsStart)
@@ -1755,6 +2044,9 @@ func (g *gateway) handleBlockFromBlockchain(blockchainBlock blockchain.BlockFrom
 	startTime := time.Now()
 
 	bxBlock := blockchainBlock.Block
+	g.blockPropagation.observe(bxBlock.Hash(), metrics.SourceNode)
+	tracing.Included(bxBlock, metrics.SourceNode)
+	g.pendingNonces.OnBlock(bxBlock)
 
 	blockInfo, err := g.bxBlockToBlockInfo(bxBlock)
 	if err != nil && err != errUnsupportedBlockType {
@@ -1795,6 +2087,16 @@ func (g *gateway) handleBlockFromBlockchain(blockchainBlock blockchain.BlockFrom
 	}
 }
 
+// setMetricsSources reports the peers and burst limiter state of the gateway to the metrics endpoint
+func (g *gateway) setMetricsSources() {
+	metrics.BlockchainPeers.SetSource(func() float64 { return float64(g.bdnStats.ConnectedNodes()) })
+
+	excess := g.burstLimiter.TotalExcess()
+	metrics.BurstLimiterExcess.SetSource("5m", func() float64 { return float64(excess.FiveMinute()) })
+	metrics.BurstLimiterExcess.SetSource("1h", func() float64 { return float64(excess.OneHour()) })
+	metrics.BurstLimiterExcess.SetSource("1d", func() float64 { return float64(excess.OneDay()) })
+}
+
 func gatewayBlockEventName(nodeName string, isBeaconBlock bool) string {
 	eventName := "GatewayReceivedBlockFromBlockchainNode"
 	if isBeaconBlock {
@@ -1804,6 +2106,10 @@ func gatewayBlockEventName(nodeName string, isBeaconBlock bool) string {
 }
 
 func (g *gateway) processBlockFromBDN(bxBlock *types.BxBlock) {
+	g.blockPropagation.observe(bxBlock.Hash(), metrics.SourceBDN)
+	tracing.Included(bxBlock, metrics.SourceBDN)
+	g.pendingNonces.OnBlock(bxBlock)
+	g.addCanonicalBlock(bxBlock)
 	blockInfo, err := g.bxBlockToBlockInfo(bxBlock)
 	if err != nil && err != errUnsupportedBlockType {
 		g.log.Errorf("failed to convert bx block %v to block info: %v", bxBlock, err)
@@ -1868,6 +2174,15 @@ func (g *gateway) handleMEVBundleMessage(mevBundle bxmessage.MEVBundle, source c
 		mevBundle.PerformanceTimestamp = time.Now()
 		broadcastRes := g.broadcast(&mevBundle, source, utils.RelayTransaction|utils.RelayProxy)
 
+		if err := g.bundleTracker.Track(&mevBundle, source.GetAccountID()); err != nil {
+			g.log.Debugf("not tracking the status of bundle %v: %v", mevBundle.BundleHash, err)
+		}
+		if mevBundle.MaxBlockNumber > 0 {
+			if err := g.bundleResubmitter.Add(mevBundle, source); err != nil {
+				g.log.Errorf("failed to resubmit bundle %v for the next blocks: %v", mevBundle.BundleHash, err)
+			}
+		}
+
 		source.Log().Tracef("broadcasting %s %s duration: %v ms, time in network: %v ms", mevBundle, broadcastRes, time.Since(start).Milliseconds(), start.Sub(mevBundle.PerformanceTimestamp).Milliseconds())
 	}
 
@@ -2252,6 +2567,8 @@ func (g *gateway) BlxrSubmitBundle(ctx context.Context, req *pb.BlxrSubmitBundle
 		UUID:            req.Uuid,
 		BundlePrice:     req.BundlePrice,
 		EnforcePayout:   req.EnforcePayout,
+		TargetBlocks:    int(req.TargetBlocks),
+		MaxBlockNumber:  req.MaxBlockNumber,
 	}
 
 	grpc := connections.NewRPCConn(*accountID, servers.GetPeerAddr(ctx), g.sdn.NetworkNum(), utils.GRPC)
@@ -2265,6 +2582,52 @@ func (g *gateway) BlxrSubmitBundle(ctx context.Context, req *pb.BlxrSubmitBundle
 	return &pb.BlxrSubmitBundleReply{BundleHash: bundleSubmitResult.BundleHash}, nil
 }
 
+// BlxrSimulateBundle simulates the bundle with the blockchain node of the gateway
+func (g *gateway) BlxrSimulateBundle(ctx context.Context, req *pb.BlxrSimulateBundleRequest) (*pb.BlxrSimulateBundleReply, error) {
+	accountModel, err := g.validateAuthHeaderWithContext(ctx, "", false, false)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	result, err := servers.SimulateBundle(g.feedManager, *accountModel, &jsonrpc.RPCBundleSimulationPayload{
+		Transaction:      req.GetTransactions(),
+		BlockNumber:      req.GetBlockNumber(),
+		StateBlockNumber: req.GetStateBlockNumber(),
+		Timestamp:        req.GetTimestamp(),
+	})
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return result.Protobuf(), nil
+}
+
+// PendingNonces returns the pending transactions of an address seen by the gateway, ordered by nonce
+func (g *gateway) PendingNonces(ctx context.Context, req *pb.PendingNoncesRequest) (*pb.PendingNoncesReply, error) {
+	if _, err := g.validateAuthHeaderWithContext(ctx, "", false, false); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	if !common.IsHexAddress(req.Address) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid address %v", req.Address)
+	}
+
+	pending, _ := g.pendingNonces.PendingNonces(common.HexToAddress(req.Address))
+	reply := &pb.PendingNoncesReply{
+		Address:           pending.Address.Hex(),
+		ConfirmedNonce:    pending.ConfirmedNonce,
+		HasConfirmedNonce: pending.HasConfirmedNonce,
+		LowestNonce:       pending.LowestNonce,
+		HighestNonce:      pending.HighestNonce,
+		NextNonce:         pending.NextNonce,
+		Txs:               make([]*pb.PendingNonceTx, 0, len(pending.Txs)),
+		Gaps:              pending.Gaps,
+	}
+	for _, tx := range pending.Txs {
+		reply.Txs = append(reply.Txs, &pb.PendingNonceTx{Nonce: tx.Nonce, TxHash: tx.Hash.Format(true), SeenTime: timestamppb.New(tx.SeenTime)})
+	}
+	return reply, nil
+}
+
 func (g *gateway) BlxrTx(ctx context.Context, req *pb.BlxrTxRequest) (*pb.BlxrTxReply, error) {
 	authHeader := retrieveAuthHeader(ctx, req.AuthHeader)
 
@@ -2279,10 +2642,34 @@ func (g *gateway) BlxrTx(ctx context.Context, req *pb.BlxrTxRequest) (*pb.BlxrTx
 	}
 
 	grpc := connections.NewRPCConn(*accountID, servers.GetPeerAddr(ctx), g.sdn.NetworkNum(), utils.GRPC)
+	if req.Condition != "" {
+		txHash, err := servers.HandleConditionalTransaction(g.feedManager, req.Transaction, req.Condition, req.ConditionExpiryBlock, grpc,
+			req.ValidatorsOnly, req.NextValidator, req.NodeValidation, req.FrontrunningProtection, uint16(req.Fallback), req.Simulate)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		log.Infof("grpc blxr_tx: Hash - 0x%v held until %v", txHash, req.Condition)
+		return &pb.BlxrTxReply{TxHash: txHash}, nil
+	}
+
+	if req.Simulate {
+		if err = servers.SimulateTransaction(g.feedManager, req.Transaction); err != nil {
+			var revertedErr *servers.TxRevertedError
+			if errors.As(err, &revertedErr) {
+				return nil, status.Error(codes.FailedPrecondition, err.Error())
+			}
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
 	txHash, ok, err := servers.HandleSingleTransaction(g.feedManager, req.Transaction, nil, grpc,
 		req.ValidatorsOnly, req.NextValidator, req.NodeValidation, req.FrontrunningProtection, uint16(req.Fallback),
 		g.feedManager.GetNextValidatorMap(), g.feedManager.GetValidatorStatusMap())
 	if err != nil {
+		var blockedErr *ofac.BlockedError
+		if errors.As(err, &blockedErr) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 	if !ok {
@@ -2293,6 +2680,172 @@ func (g *gateway) BlxrTx(ctx context.Context, req *pb.BlxrTxRequest) (*pb.BlxrTx
 	return &pb.BlxrTxReply{TxHash: txHash}, nil
 }
 
+// ConditionalTxs returns the transactions held for the account until their condition matches a block
+func (g *gateway) ConditionalTxs(ctx context.Context, req *pb.ConditionalTxsRequest) (*pb.ConditionalTxsReply, error) {
+	accountID, err := g.conditionalTxsAccountID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var txs []services.ConditionalTxInfo
+	if req.TxHash == "" {
+		txs = g.conditionalTxs.List(accountID)
+	} else {
+		hash, err := types.NewSHA256HashFromString(req.TxHash)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid tx_hash %v", req.TxHash)
+		}
+		if info, ok := g.conditionalTxs.Get(hash, accountID); ok {
+			txs = append(txs, info)
+		}
+	}
+
+	reply := &pb.ConditionalTxsReply{Txs: make([]*pb.ConditionalTx, 0, len(txs))}
+	for _, tx := range txs {
+		reply.Txs = append(reply.Txs, &pb.ConditionalTx{
+			TxHash:      tx.Hash.Format(true),
+			Condition:   tx.Condition,
+			ExpiryBlock: tx.ExpiryBlock,
+			AddedTime:   timestamppb.New(tx.AddedTime),
+		})
+	}
+	return reply, nil
+}
+
+// CancelConditionalTx drops a transaction held for the account
+func (g *gateway) CancelConditionalTx(ctx context.Context, req *pb.CancelConditionalTxRequest) (*pb.CancelConditionalTxReply, error) {
+	accountID, err := g.conditionalTxsAccountID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := types.NewSHA256HashFromString(req.TxHash)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid tx_hash %v", req.TxHash)
+	}
+	if err = g.conditionalTxs.Cancel(hash, accountID); err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	log.Infof("grpc cancel conditional tx: Hash - 0x%v", hash)
+	return &pb.CancelConditionalTxReply{TxHash: hash.Format(true)}, nil
+}
+
+// MempoolSnapshot returns a page of the pending transactions of the gateway matching the filters, sorted by effective tip
+func (g *gateway) MempoolSnapshot(ctx context.Context, req *pb.MempoolSnapshotRequest) (*pb.MempoolSnapshotReply, error) {
+	accountModel, err := g.validateAuthHeaderWithContext(ctx, "", false, false)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err = servers.ValidateMempoolSnapshotAccount(accountModel, req.GetFilters()); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if g.mempoolSnapshots == nil {
+		return nil, status.Error(codes.Unavailable, "mempool snapshots are not available yet")
+	}
+
+	reply, err := g.mempoolSnapshots.PageProtobuf(accountModel.AccountID, req.GetFilters(), req.GetCursor(), int(req.GetLimit()))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return reply, nil
+}
+
+// GasEstimate returns the fees recommended for each inclusion probability tier by the gas oracle of the gateway
+func (g *gateway) GasEstimate(ctx context.Context, _ *pb.GasEstimateRequest) (*pb.GasEstimateReply, error) {
+	if _, err := g.validateAuthHeaderWithContext(ctx, "", false, false); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if g.gasOracle == nil {
+		return nil, status.Error(codes.Unavailable, "gas estimate is not available yet")
+	}
+
+	estimate, err := g.gasOracle.Estimate()
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return estimate.Protobuf(), nil
+}
+
+// BundleStatus returns whether the transactions of a bundle submitted to the gateway were included
+func (g *gateway) BundleStatus(ctx context.Context, req *pb.BundleStatusRequest) (*pb.BundleStatusReply, error) {
+	accountModel, err := g.validateAuthHeaderWithContext(ctx, "", false, false)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	bundleStatus, ok := g.bundleTracker.Status(req.GetBundleHash(), accountModel.AccountID)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "bundle %v is not tracked by the gateway", req.GetBundleHash())
+	}
+	return bundleStatus.Notification().Protobuf(), nil
+}
+
+// FeeBumpTxStatus returns the progress of a transaction replacement submitted by the account
+func (g *gateway) FeeBumpTxStatus(ctx context.Context, req *pb.FeeBumpTxStatusRequest) (*pb.FeeBumpTxStatusReply, error) {
+	accountModel, err := g.validateAuthHeaderWithContext(ctx, "", false, false)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	replacement, ok := g.txReplacements.Status(req.GetReplacementId(), accountModel.AccountID)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "replacement %v is not active", req.GetReplacementId())
+	}
+	return &pb.FeeBumpTxStatusReply{
+		ReplacementId: replacement.ID,
+		Released:      uint32(replacement.Released),
+		Transactions:  uint32(replacement.Rungs),
+		TxHash:        replacement.TxHash.Format(true),
+	}, nil
+}
+
+// BuilderHealth returns the health of the MEV builder endpoints the gateway sent bundles to
+func (g *gateway) BuilderHealth(ctx context.Context, _ *pb.BuilderHealthRequest) (*pb.BuilderHealthReply, error) {
+	if _, err := g.validateAuthHeaderWithContext(ctx, "", false, false); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	health := g.mevBundleDispatcher.Health()
+	reply := &pb.BuilderHealthReply{Endpoints: make([]*pb.BuilderEndpointHealth, 0, len(health))}
+	for _, endpoint := range health {
+		endpointHealth := &pb.BuilderEndpointHealth{
+			Builder:             endpoint.Builder,
+			Endpoint:            endpoint.Endpoint,
+			State:               string(endpoint.State),
+			Requests:            endpoint.Requests,
+			Failures:            endpoint.Failures,
+			Retries:             endpoint.Retries,
+			Skipped:             endpoint.Skipped,
+			ConsecutiveFailures: uint32(endpoint.ConsecutiveFailures),
+			AverageLatencyUs:    endpoint.AverageLatency.Microseconds(),
+			LastError:           endpoint.LastError,
+		}
+		if !endpoint.LastErrorTime.IsZero() {
+			endpointHealth.LastErrorTime = endpoint.LastErrorTime.UnixNano()
+		}
+		if !endpoint.LastSuccessTime.IsZero() {
+			endpointHealth.LastSuccessTime = endpoint.LastSuccessTime.UnixNano()
+		}
+		reply.Endpoints = append(reply.Endpoints, endpointHealth)
+	}
+	return reply, nil
+}
+
+// conditionalTxsAccountID returns the account the conditional transactions of a request are held for, the same one
+// BlxrTx holds them for
+func (g *gateway) conditionalTxsAccountID(ctx context.Context) (types.AccountID, error) {
+	accountModel, err := g.validateAuthHeader(retrieveAuthHeader(ctx, ""), true, false, servers.GetPeerAddr(ctx))
+	if err != nil {
+		return "", status.Error(codes.PermissionDenied, err.Error())
+	}
+	accountID, err := retrieveOriginalSenderAccountID(ctx, accountModel)
+	if err != nil {
+		return "", status.Error(codes.InvalidArgument, err.Error())
+	}
+	return *accountID, nil
+}
+
 func (g *gateway) BlxrBatchTX(ctx context.Context, req *pb.BlxrBatchTXRequest) (*pb.BlxrBatchTXReply, error) {
 	authHeader := retrieveAuthHeader(ctx, req.AuthHeader)
 
@@ -2306,9 +2859,8 @@ func (g *gateway) BlxrBatchTX(ctx context.Context, req *pb.BlxrBatchTXRequest) (
 	var txErrors []*pb.ErrorIndex
 	transactionsAndSenders := req.GetTransactionsAndSenders()
 
-	batchTxLimit := 10
-	if len(transactionsAndSenders) > batchTxLimit {
-		txError := fmt.Sprintf("blxr-batch-tx currently supports a maximum of %v transactions", batchTxLimit)
+	if len(transactionsAndSenders) > servers.MaxBatchTxs {
+		txError := fmt.Sprintf("blxr-batch-tx currently supports a maximum of %v transactions", servers.MaxBatchTxs)
 		txErrors = append(txErrors, &pb.ErrorIndex{Idx: 0, Error: txError})
 		return &pb.BlxrBatchTXReply{TxErrors: txErrors}, nil
 	}
@@ -2320,19 +2872,30 @@ func (g *gateway) BlxrBatchTX(ctx context.Context, req *pb.BlxrBatchTXRequest) (
 
 	grpc := connections.NewRPCConn(*accountID, servers.GetPeerAddr(ctx), g.sdn.NetworkNum(), utils.GRPC)
 
-	for idx, transactionsAndSender := range transactionsAndSenders {
-		tx := transactionsAndSender.GetTransaction()
-		txHash, ok, err := servers.HandleSingleTransaction(g.feedManager, tx, transactionsAndSender.GetSender(), grpc,
-			req.ValidatorsOnly, req.NextValidator, req.NodeValidation, req.FrontrunningProtection,
-			uint16(req.Fallback), g.feedManager.GetNextValidatorMap(), g.feedManager.GetValidatorStatusMap())
-		if err != nil {
-			txErrors = append(txErrors, &pb.ErrorIndex{Idx: int32(idx), Error: err.Error()})
-			continue
-		}
-		if !ok {
-			continue
+	transactions := make([]string, 0, len(transactionsAndSenders))
+	senders := make([][]byte, 0, len(transactionsAndSenders))
+	for _, transactionsAndSender := range transactionsAndSenders {
+		transactions = append(transactions, transactionsAndSender.GetTransaction())
+		senders = append(senders, transactionsAndSender.GetSender())
+	}
+
+	results := servers.HandleBatchTransactions(g.feedManager, transactions, senders, grpc, servers.BatchTxOptions{
+		ValidatorsOnly:         req.ValidatorsOnly,
+		NextValidator:          req.NextValidator,
+		NodeValidation:         req.NodeValidation,
+		FrontRunningProtection: req.FrontrunningProtection,
+		Fallback:               uint16(req.Fallback),
+		Simulate:               req.Simulate,
+		Atomic:                 req.Atomic,
+	})
+	batchResults := make([]*pb.BatchTxResult, 0, len(results))
+	for _, result := range results {
+		batchResults = append(batchResults, &pb.BatchTxResult{Idx: int32(result.Idx), TxHash: result.TxHash, Code: string(result.Code), Error: result.Error})
+		if result.Sent() {
+			txHashes = append(txHashes, &pb.TxIndex{Idx: int32(result.Idx), TxHash: result.TxHash})
+		} else {
+			txErrors = append(txErrors, &pb.ErrorIndex{Idx: int32(result.Idx), Error: result.Error})
 		}
-		txHashes = append(txHashes, &pb.TxIndex{Idx: int32(idx), TxHash: txHash})
 	}
 
 	g.log.WithFields(log.Fields{
@@ -2344,9 +2907,10 @@ func (g *gateway) BlxrBatchTX(ctx context.Context, req *pb.BlxrBatchTXRequest) (
 		"nextValidator":  req.NextValidator,
 		"fallback":       req.Fallback,
 		"nodeValidation": req.NodeValidation,
+		"atomic":         req.Atomic,
 	}).Debug("blxr-batch-tx")
 
-	return &pb.BlxrBatchTXReply{TxHashes: txHashes, TxErrors: txErrors}, nil
+	return &pb.BlxrBatchTXReply{TxHashes: txHashes, TxErrors: txErrors, Results: batchResults}, nil
 }
 
 func (g *gateway) NewTxs(req *pb.TxsRequest, stream pb.Gateway_NewTxsServer) error {
@@ -2460,11 +3024,37 @@ func (g *gateway) bxBlockToBlockInfo(bxBlock *types.BxBlock) (*eth.BlockInfo, er
 	return block, nil
 }
 
+// txStatusUpdate describes a transaction already processed by the gateway when its monitoring starts
+func (g *gateway) txStatusUpdate(hash types.SHA256Hash) (services.TxStatusUpdate, bool) {
+	bxTx, ok := g.TxStore.Get(hash)
+	if !ok || !bxTx.HasContent() {
+		return services.TxStatusUpdate{}, false
+	}
+
+	update := services.TxStatusUpdate{Hash: hash}
+	blockchainTx, err := bxTx.BlockchainTransaction(bxTx.Sender())
+	if err != nil {
+		return update, true
+	}
+	if ethTx, ok := blockchainTx.(*types.EthTransaction); ok {
+		update.Nonce = ethTx.Nonce()
+		if sender, err := ethTx.Sender(); err == nil {
+			update.Sender = sender
+		}
+	}
+	return update, true
+}
+
 func (g *gateway) onBlock(blockInfo *eth.BlockInfo) {
 	if blockInfo == nil {
 		return
 	}
 
+	g.mevBundleDispatcher.OnBlock(blockInfo.Block.NumberU64(), blockInfo.Block.Time())
+	if g.mempoolSnapshots != nil {
+		g.mempoolSnapshots.OnBlock(blockInfo.Block)
+	}
+
 	if err := g.blockProposer.OnBlock(g.context, blockInfo.Block); err != nil {
 		g.log.Debugf("failed to process block: %v", err)
 		return
@@ -2481,6 +3071,14 @@ func (g *gateway) TxReceipts(req *pb.TxReceiptsRequest, stream pb.Gateway_TxRece
 	return g.grpcHandler.TxReceipts(req, stream, *accountModel)
 }
 
+func (g *gateway) TransactionStatus(req *pb.TransactionStatusRequest, stream pb.Gateway_TransactionStatusServer) error {
+	accountModel, err := g.validateAuthHeader(retrieveAuthHeader(stream.Context(), ""), true, true, servers.GetPeerAddr(stream.Context()))
+	if err != nil {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return g.grpcHandler.TransactionStatus(req, stream, *accountModel)
+}
+
 func (g *gateway) sendStats() {
 	rss, err := utils.GetAppMemoryUsage()
 	if err != nil {
@@ -114,6 +114,7 @@ func setup(t *testing.T, numPeers int) (blockchain.Bridge, *gateway) {
 		0,
 		0,
 		false,
+		eth.NewChain(context.Background(), 0),
 	)
 
 	g := node.(*gateway)
@@ -1183,6 +1184,55 @@ func TestGateway_TestNoTxReceiptsWithoutSubscription(t *testing.T) {
 	}
 }
 
+func TestGateway_ReorgFeed(t *testing.T) {
+	bridge, g := setup(t, 1)
+	g.feedManager.Subscribe(types.ReorgFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
+	g.feedManager.Subscribe(types.TxReceiptsFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
+	g.feedManagerChan = make(chan types.Notification, bxgateway.BxNotificationChannelSize)
+	g.BxConfig.WebsocketEnabled = true
+	g.BxConfig.ReorgReemitFeeds = true
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go g.handleChainUpdates(ctx)
+
+	block1 := bxmock.NewEthBlock(10, common.Hash{})
+	block2a := bxmock.NewEthBlock(11, block1.Hash())
+	block2b := bxmock.NewEthBlock(11, block1.Hash())
+	block3b := bxmock.NewEthBlock(12, block2b.Hash())
+
+	for _, block := range []*ethtypes.Block{block1, block2a, block2b, block3b} {
+		bxBlock, err := bridge.BlockBlockchainToBDN(eth.NewBlockInfo(block, nil))
+		require.NoError(t, err)
+		// the blockchain backend adds the blocks of the node to the canonical chain
+		g.canonicalChain.AddBlock(eth.NewBlockInfo(block, nil), eth.BSBlockchain)
+		require.NoError(t, g.publishBlock(bxBlock, nil, nil, true))
+	}
+
+	// ethOnBlock of block 2b is sent when it is published, and again when it becomes canonical
+	onBlock2b := 0
+	var reorg *types.ReorgNotification
+	ticker := time.NewTicker(1 * time.Second)
+	for reorg == nil || onBlock2b < 2 {
+		select {
+		case notification := <-g.feedManagerChan:
+			switch notification.NotificationType() {
+			case types.ReorgFeed:
+				require.Nil(t, reorg, "received more than one reorg notification")
+				reorg = notification.(*types.ReorgNotification)
+			case types.OnBlockFeed:
+				if notification.GetHash() == block2b.Hash().String() {
+					onBlock2b++
+				}
+			}
+		case <-ticker.C:
+			assert.FailNowf(t, "did not receive expected feed notifications", "reorg %v, ethOnBlock of block 2b %v", reorg, onBlock2b)
+		}
+	}
+
+	assert.Equal(t, []types.ReorgBlock{{Hash: block2a.Hash().String(), Height: 11}}, reorg.Dropped)
+	assert.Equal(t, []types.ReorgBlock{{Hash: block2b.Hash().String(), Height: 11}, {Hash: block3b.Hash().String(), Height: 12}}, reorg.Added)
+}
+
 func expectNoFeedNotification(t *testing.T, bridge blockchain.Bridge, g *gateway, isBDNBlock bool, blockHeight int, expectedBestBlockHeight int, expectedSkipBlockCount int) {
 	ethBlock := bxmock.NewEthBlock(uint64(blockHeight), common.Hash{})
 	bxBlock, _ := bridge.BlockBlockchainToBDN(eth.NewBlockInfo(ethBlock, nil))
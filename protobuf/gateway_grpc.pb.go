@@ -34,12 +34,22 @@ type GatewayClient interface {
 	BdnBlocks(ctx context.Context, in *BlocksRequest, opts ...grpc.CallOption) (Gateway_BdnBlocksClient, error)
 	EthOnBlock(ctx context.Context, in *EthOnBlockRequest, opts ...grpc.CallOption) (Gateway_EthOnBlockClient, error)
 	TxReceipts(ctx context.Context, in *TxReceiptsRequest, opts ...grpc.CallOption) (Gateway_TxReceiptsClient, error)
+	TransactionStatus(ctx context.Context, in *TransactionStatusRequest, opts ...grpc.CallOption) (Gateway_TransactionStatusClient, error)
 	ShortIDs(ctx context.Context, in *TxHashListRequest, opts ...grpc.CallOption) (*ShortIDListReply, error)
 	ProposedBlock(ctx context.Context, in *ProposedBlockRequest, opts ...grpc.CallOption) (*ProposedBlockReply, error)
 	TxsFromShortIDs(ctx context.Context, in *ShortIDListRequest, opts ...grpc.CallOption) (*TxListReply, error)
 	BlockInfo(ctx context.Context, in *BlockInfoRequest, opts ...grpc.CallOption) (*BlockInfoReply, error)
 	ProposedBlockStats(ctx context.Context, in *ProposedBlockStatsRequest, opts ...grpc.CallOption) (*ProposedBlockStatsReply, error)
 	BlxrSubmitBundle(ctx context.Context, in *BlxrSubmitBundleRequest, opts ...grpc.CallOption) (*BlxrSubmitBundleReply, error)
+	BlxrSimulateBundle(ctx context.Context, in *BlxrSimulateBundleRequest, opts ...grpc.CallOption) (*BlxrSimulateBundleReply, error)
+	PendingNonces(ctx context.Context, in *PendingNoncesRequest, opts ...grpc.CallOption) (*PendingNoncesReply, error)
+	ConditionalTxs(ctx context.Context, in *ConditionalTxsRequest, opts ...grpc.CallOption) (*ConditionalTxsReply, error)
+	CancelConditionalTx(ctx context.Context, in *CancelConditionalTxRequest, opts ...grpc.CallOption) (*CancelConditionalTxReply, error)
+	MempoolSnapshot(ctx context.Context, in *MempoolSnapshotRequest, opts ...grpc.CallOption) (*MempoolSnapshotReply, error)
+	GasEstimate(ctx context.Context, in *GasEstimateRequest, opts ...grpc.CallOption) (*GasEstimateReply, error)
+	BuilderHealth(ctx context.Context, in *BuilderHealthRequest, opts ...grpc.CallOption) (*BuilderHealthReply, error)
+	BundleStatus(ctx context.Context, in *BundleStatusRequest, opts ...grpc.CallOption) (*BundleStatusReply, error)
+	FeeBumpTxStatus(ctx context.Context, in *FeeBumpTxStatusRequest, opts ...grpc.CallOption) (*FeeBumpTxStatusReply, error)
 	//Intent Gateway functions
 	SubmitIntent(ctx context.Context, in *SubmitIntentRequest, opts ...grpc.CallOption) (*SubmitIntentReply, error)
 	SubmitIntentSolution(ctx context.Context, in *SubmitIntentSolutionRequest, opts ...grpc.CallOption) (*SubmitIntentSolutionReply, error)
@@ -337,6 +347,38 @@ func (x *gatewayTxReceiptsClient) Recv() (*TxReceiptsReply, error) {
 	return m, nil
 }
 
+func (c *gatewayClient) TransactionStatus(ctx context.Context, in *TransactionStatusRequest, opts ...grpc.CallOption) (Gateway_TransactionStatusClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Gateway_ServiceDesc.Streams[6], "/gateway.Gateway/TransactionStatus", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gatewayTransactionStatusClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Gateway_TransactionStatusClient interface {
+	Recv() (*TransactionStatusReply, error)
+	grpc.ClientStream
+}
+
+type gatewayTransactionStatusClient struct {
+	grpc.ClientStream
+}
+
+func (x *gatewayTransactionStatusClient) Recv() (*TransactionStatusReply, error) {
+	m := new(TransactionStatusReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *gatewayClient) ShortIDs(ctx context.Context, in *TxHashListRequest, opts ...grpc.CallOption) (*ShortIDListReply, error) {
 	out := new(ShortIDListReply)
 	err := c.cc.Invoke(ctx, "/gateway.Gateway/ShortIDs", in, out, opts...)
@@ -391,6 +433,87 @@ func (c *gatewayClient) BlxrSubmitBundle(ctx context.Context, in *BlxrSubmitBund
 	return out, nil
 }
 
+func (c *gatewayClient) BlxrSimulateBundle(ctx context.Context, in *BlxrSimulateBundleRequest, opts ...grpc.CallOption) (*BlxrSimulateBundleReply, error) {
+	out := new(BlxrSimulateBundleReply)
+	err := c.cc.Invoke(ctx, "/gateway.Gateway/BlxrSimulateBundle", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gatewayClient) PendingNonces(ctx context.Context, in *PendingNoncesRequest, opts ...grpc.CallOption) (*PendingNoncesReply, error) {
+	out := new(PendingNoncesReply)
+	err := c.cc.Invoke(ctx, "/gateway.Gateway/PendingNonces", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gatewayClient) ConditionalTxs(ctx context.Context, in *ConditionalTxsRequest, opts ...grpc.CallOption) (*ConditionalTxsReply, error) {
+	out := new(ConditionalTxsReply)
+	err := c.cc.Invoke(ctx, "/gateway.Gateway/ConditionalTxs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gatewayClient) CancelConditionalTx(ctx context.Context, in *CancelConditionalTxRequest, opts ...grpc.CallOption) (*CancelConditionalTxReply, error) {
+	out := new(CancelConditionalTxReply)
+	err := c.cc.Invoke(ctx, "/gateway.Gateway/CancelConditionalTx", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gatewayClient) MempoolSnapshot(ctx context.Context, in *MempoolSnapshotRequest, opts ...grpc.CallOption) (*MempoolSnapshotReply, error) {
+	out := new(MempoolSnapshotReply)
+	err := c.cc.Invoke(ctx, "/gateway.Gateway/MempoolSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gatewayClient) GasEstimate(ctx context.Context, in *GasEstimateRequest, opts ...grpc.CallOption) (*GasEstimateReply, error) {
+	out := new(GasEstimateReply)
+	err := c.cc.Invoke(ctx, "/gateway.Gateway/GasEstimate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gatewayClient) BuilderHealth(ctx context.Context, in *BuilderHealthRequest, opts ...grpc.CallOption) (*BuilderHealthReply, error) {
+	out := new(BuilderHealthReply)
+	err := c.cc.Invoke(ctx, "/gateway.Gateway/BuilderHealth", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gatewayClient) BundleStatus(ctx context.Context, in *BundleStatusRequest, opts ...grpc.CallOption) (*BundleStatusReply, error) {
+	out := new(BundleStatusReply)
+	err := c.cc.Invoke(ctx, "/gateway.Gateway/BundleStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gatewayClient) FeeBumpTxStatus(ctx context.Context, in *FeeBumpTxStatusRequest, opts ...grpc.CallOption) (*FeeBumpTxStatusReply, error) {
+	out := new(FeeBumpTxStatusReply)
+	err := c.cc.Invoke(ctx, "/gateway.Gateway/FeeBumpTxStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *gatewayClient) SubmitIntent(ctx context.Context, in *SubmitIntentRequest, opts ...grpc.CallOption) (*SubmitIntentReply, error) {
 	out := new(SubmitIntentReply)
 	err := c.cc.Invoke(ctx, "/gateway.Gateway/SubmitIntent", in, out, opts...)
@@ -410,7 +533,7 @@ func (c *gatewayClient) SubmitIntentSolution(ctx context.Context, in *SubmitInte
 }
 
 func (c *gatewayClient) Intents(ctx context.Context, in *IntentsRequest, opts ...grpc.CallOption) (Gateway_IntentsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Gateway_ServiceDesc.Streams[6], "/gateway.Gateway/Intents", opts...)
+	stream, err := c.cc.NewStream(ctx, &Gateway_ServiceDesc.Streams[7], "/gateway.Gateway/Intents", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -442,7 +565,7 @@ func (x *gatewayIntentsClient) Recv() (*IntentsReply, error) {
 }
 
 func (c *gatewayClient) IntentSolutions(ctx context.Context, in *IntentSolutionsRequest, opts ...grpc.CallOption) (Gateway_IntentSolutionsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Gateway_ServiceDesc.Streams[7], "/gateway.Gateway/IntentSolutions", opts...)
+	stream, err := c.cc.NewStream(ctx, &Gateway_ServiceDesc.Streams[8], "/gateway.Gateway/IntentSolutions", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -493,12 +616,22 @@ type GatewayServer interface {
 	BdnBlocks(*BlocksRequest, Gateway_BdnBlocksServer) error
 	EthOnBlock(*EthOnBlockRequest, Gateway_EthOnBlockServer) error
 	TxReceipts(*TxReceiptsRequest, Gateway_TxReceiptsServer) error
+	TransactionStatus(*TransactionStatusRequest, Gateway_TransactionStatusServer) error
 	ShortIDs(context.Context, *TxHashListRequest) (*ShortIDListReply, error)
 	ProposedBlock(context.Context, *ProposedBlockRequest) (*ProposedBlockReply, error)
 	TxsFromShortIDs(context.Context, *ShortIDListRequest) (*TxListReply, error)
 	BlockInfo(context.Context, *BlockInfoRequest) (*BlockInfoReply, error)
 	ProposedBlockStats(context.Context, *ProposedBlockStatsRequest) (*ProposedBlockStatsReply, error)
 	BlxrSubmitBundle(context.Context, *BlxrSubmitBundleRequest) (*BlxrSubmitBundleReply, error)
+	BlxrSimulateBundle(context.Context, *BlxrSimulateBundleRequest) (*BlxrSimulateBundleReply, error)
+	PendingNonces(context.Context, *PendingNoncesRequest) (*PendingNoncesReply, error)
+	ConditionalTxs(context.Context, *ConditionalTxsRequest) (*ConditionalTxsReply, error)
+	CancelConditionalTx(context.Context, *CancelConditionalTxRequest) (*CancelConditionalTxReply, error)
+	MempoolSnapshot(context.Context, *MempoolSnapshotRequest) (*MempoolSnapshotReply, error)
+	GasEstimate(context.Context, *GasEstimateRequest) (*GasEstimateReply, error)
+	BuilderHealth(context.Context, *BuilderHealthRequest) (*BuilderHealthReply, error)
+	BundleStatus(context.Context, *BundleStatusRequest) (*BundleStatusReply, error)
+	FeeBumpTxStatus(context.Context, *FeeBumpTxStatusRequest) (*FeeBumpTxStatusReply, error)
 	//Intent Gateway functions
 	SubmitIntent(context.Context, *SubmitIntentRequest) (*SubmitIntentReply, error)
 	SubmitIntentSolution(context.Context, *SubmitIntentSolutionRequest) (*SubmitIntentSolutionReply, error)
@@ -559,6 +692,9 @@ func (UnimplementedGatewayServer) EthOnBlock(*EthOnBlockRequest, Gateway_EthOnBl
 func (UnimplementedGatewayServer) TxReceipts(*TxReceiptsRequest, Gateway_TxReceiptsServer) error {
 	return status.Errorf(codes.Unimplemented, "method TxReceipts not implemented")
 }
+func (UnimplementedGatewayServer) TransactionStatus(*TransactionStatusRequest, Gateway_TransactionStatusServer) error {
+	return status.Errorf(codes.Unimplemented, "method TransactionStatus not implemented")
+}
 func (UnimplementedGatewayServer) ShortIDs(context.Context, *TxHashListRequest) (*ShortIDListReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ShortIDs not implemented")
 }
@@ -577,6 +713,33 @@ func (UnimplementedGatewayServer) ProposedBlockStats(context.Context, *ProposedB
 func (UnimplementedGatewayServer) BlxrSubmitBundle(context.Context, *BlxrSubmitBundleRequest) (*BlxrSubmitBundleReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BlxrSubmitBundle not implemented")
 }
+func (UnimplementedGatewayServer) BlxrSimulateBundle(context.Context, *BlxrSimulateBundleRequest) (*BlxrSimulateBundleReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BlxrSimulateBundle not implemented")
+}
+func (UnimplementedGatewayServer) PendingNonces(context.Context, *PendingNoncesRequest) (*PendingNoncesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PendingNonces not implemented")
+}
+func (UnimplementedGatewayServer) ConditionalTxs(context.Context, *ConditionalTxsRequest) (*ConditionalTxsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConditionalTxs not implemented")
+}
+func (UnimplementedGatewayServer) CancelConditionalTx(context.Context, *CancelConditionalTxRequest) (*CancelConditionalTxReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelConditionalTx not implemented")
+}
+func (UnimplementedGatewayServer) MempoolSnapshot(context.Context, *MempoolSnapshotRequest) (*MempoolSnapshotReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MempoolSnapshot not implemented")
+}
+func (UnimplementedGatewayServer) GasEstimate(context.Context, *GasEstimateRequest) (*GasEstimateReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GasEstimate not implemented")
+}
+func (UnimplementedGatewayServer) BuilderHealth(context.Context, *BuilderHealthRequest) (*BuilderHealthReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BuilderHealth not implemented")
+}
+func (UnimplementedGatewayServer) BundleStatus(context.Context, *BundleStatusRequest) (*BundleStatusReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BundleStatus not implemented")
+}
+func (UnimplementedGatewayServer) FeeBumpTxStatus(context.Context, *FeeBumpTxStatusRequest) (*FeeBumpTxStatusReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FeeBumpTxStatus not implemented")
+}
 func (UnimplementedGatewayServer) SubmitIntent(context.Context, *SubmitIntentRequest) (*SubmitIntentReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SubmitIntent not implemented")
 }
@@ -908,6 +1071,27 @@ func (x *gatewayTxReceiptsServer) Send(m *TxReceiptsReply) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Gateway_TransactionStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TransactionStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GatewayServer).TransactionStatus(m, &gatewayTransactionStatusServer{stream})
+}
+
+type Gateway_TransactionStatusServer interface {
+	Send(*TransactionStatusReply) error
+	grpc.ServerStream
+}
+
+type gatewayTransactionStatusServer struct {
+	grpc.ServerStream
+}
+
+func (x *gatewayTransactionStatusServer) Send(m *TransactionStatusReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Gateway_ShortIDs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(TxHashListRequest)
 	if err := dec(in); err != nil {
@@ -1016,6 +1200,168 @@ func _Gateway_BlxrSubmitBundle_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Gateway_BlxrSimulateBundle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlxrSimulateBundleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GatewayServer).BlxrSimulateBundle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gateway.Gateway/BlxrSimulateBundle",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GatewayServer).BlxrSimulateBundle(ctx, req.(*BlxrSimulateBundleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gateway_PendingNonces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PendingNoncesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GatewayServer).PendingNonces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gateway.Gateway/PendingNonces",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GatewayServer).PendingNonces(ctx, req.(*PendingNoncesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gateway_ConditionalTxs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConditionalTxsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GatewayServer).ConditionalTxs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gateway.Gateway/ConditionalTxs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GatewayServer).ConditionalTxs(ctx, req.(*ConditionalTxsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gateway_CancelConditionalTx_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelConditionalTxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GatewayServer).CancelConditionalTx(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gateway.Gateway/CancelConditionalTx",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GatewayServer).CancelConditionalTx(ctx, req.(*CancelConditionalTxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gateway_MempoolSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MempoolSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GatewayServer).MempoolSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gateway.Gateway/MempoolSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GatewayServer).MempoolSnapshot(ctx, req.(*MempoolSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gateway_GasEstimate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GasEstimateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GatewayServer).GasEstimate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gateway.Gateway/GasEstimate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GatewayServer).GasEstimate(ctx, req.(*GasEstimateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gateway_BuilderHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BuilderHealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GatewayServer).BuilderHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gateway.Gateway/BuilderHealth",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GatewayServer).BuilderHealth(ctx, req.(*BuilderHealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gateway_BundleStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BundleStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GatewayServer).BundleStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gateway.Gateway/BundleStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GatewayServer).BundleStatus(ctx, req.(*BundleStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gateway_FeeBumpTxStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FeeBumpTxStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GatewayServer).FeeBumpTxStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gateway.Gateway/FeeBumpTxStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GatewayServer).FeeBumpTxStatus(ctx, req.(*FeeBumpTxStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Gateway_SubmitIntent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SubmitIntentRequest)
 	if err := dec(in); err != nil {
@@ -1165,6 +1511,42 @@ var Gateway_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "BlxrSubmitBundle",
 			Handler:    _Gateway_BlxrSubmitBundle_Handler,
 		},
+		{
+			MethodName: "BlxrSimulateBundle",
+			Handler:    _Gateway_BlxrSimulateBundle_Handler,
+		},
+		{
+			MethodName: "PendingNonces",
+			Handler:    _Gateway_PendingNonces_Handler,
+		},
+		{
+			MethodName: "ConditionalTxs",
+			Handler:    _Gateway_ConditionalTxs_Handler,
+		},
+		{
+			MethodName: "CancelConditionalTx",
+			Handler:    _Gateway_CancelConditionalTx_Handler,
+		},
+		{
+			MethodName: "MempoolSnapshot",
+			Handler:    _Gateway_MempoolSnapshot_Handler,
+		},
+		{
+			MethodName: "GasEstimate",
+			Handler:    _Gateway_GasEstimate_Handler,
+		},
+		{
+			MethodName: "BuilderHealth",
+			Handler:    _Gateway_BuilderHealth_Handler,
+		},
+		{
+			MethodName: "BundleStatus",
+			Handler:    _Gateway_BundleStatus_Handler,
+		},
+		{
+			MethodName: "FeeBumpTxStatus",
+			Handler:    _Gateway_FeeBumpTxStatus_Handler,
+		},
 		{
 			MethodName: "SubmitIntent",
 			Handler:    _Gateway_SubmitIntent_Handler,
@@ -1205,6 +1587,11 @@ var Gateway_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Gateway_TxReceipts_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "TransactionStatus",
+			Handler:       _Gateway_TransactionStatus_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "Intents",
 			Handler:       _Gateway_Intents_Handler,
@@ -0,0 +1,121 @@
+package servers
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+var backpressurePolicies = []types.BackpressurePolicy{types.BackpressureDisconnect, types.BackpressureDropOldest,
+	types.BackpressureDropNewest, types.BackpressureCoalesce}
+
+// coalescedFeeds are the feeds in which a notification supersedes the previous ones
+var coalescedFeeds = []types.FeedType{types.NewBlocksFeed, types.BDNBlocksFeed, types.NewBeaconBlocksFeed,
+	types.BDNBeaconBlocksFeed, types.OnBlockFeed}
+
+// parseBackpressurePolicy validates the backpressure option of a subscription, disconnect is the default
+func parseBackpressurePolicy(feed types.FeedType, value string) (types.BackpressurePolicy, error) {
+	if value == "" {
+		return types.BackpressureDisconnect, nil
+	}
+
+	policy := types.BackpressurePolicy(value)
+	valid := false
+	for _, p := range backpressurePolicies {
+		if p == policy {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return "", fmt.Errorf("invalid backpressure policy %v, possible policies are: %v", value, backpressurePolicies)
+	}
+
+	if policy == types.BackpressureCoalesce {
+		for _, f := range coalescedFeeds {
+			if f == feed {
+				return policy, nil
+			}
+		}
+		return "", fmt.Errorf("%v backpressure policy is not supported by %v feed", policy, feed)
+	}
+	return policy, nil
+}
+
+// subscriptionStats counts the notifications of a subscription, it is shared by the copies of the ClientSubscription.
+// Messages are counted as sent once the subscription writer delivers them, not when they are queued
+type subscriptionStats struct {
+	sent           atomic.Uint64
+	dropped        atomic.Uint64
+	sendLatency    atomic.Int64
+	maxSendLatency atomic.Int64
+}
+
+func (s *subscriptionStats) observeSend(latency time.Duration) {
+	s.sent.Add(1)
+	s.sendLatency.Add(int64(latency))
+	for {
+		max := s.maxSendLatency.Load()
+		if int64(latency) <= max || s.maxSendLatency.CompareAndSwap(max, int64(latency)) {
+			return
+		}
+	}
+}
+
+func (s *subscriptionStats) avgSendLatency() time.Duration {
+	sends := s.sent.Load()
+	if sends == 0 {
+		return 0
+	}
+	return time.Duration(s.sendLatency.Load() / int64(sends))
+}
+
+// enqueue offers a notification to a subscription, applying its backpressure policy if the client is too slow.
+// It returns false if the subscription has to be closed
+func (f *FeedManager) enqueue(clientSub *ClientSubscription, notification types.Notification) bool {
+	select {
+	case clientSub.feed <- notification:
+		return true
+	default:
+	}
+
+	switch clientSub.Backpressure {
+	case types.BackpressureDropNewest:
+		clientSub.stats.dropped.Add(1)
+		return true
+	case types.BackpressureDropOldest:
+		select {
+		case <-clientSub.feed:
+			clientSub.stats.dropped.Add(1)
+		default:
+		}
+	case types.BackpressureCoalesce:
+		for drained := false; !drained; {
+			select {
+			case <-clientSub.feed:
+				clientSub.stats.dropped.Add(1)
+			default:
+				drained = true
+			}
+		}
+	default:
+		return false
+	}
+
+	// the feed manager is the only writer, so there is room for the notification now
+	select {
+	case clientSub.feed <- notification:
+	default:
+		clientSub.stats.dropped.Add(1)
+	}
+	return true
+}
+
+// observeSend records the time it took to send a notification to the client
+func (s *ClientSubscriptionHandlingInfo) observeSend(start time.Time) {
+	if s.stats != nil {
+		s.stats.observeSend(time.Since(start))
+	}
+}
@@ -0,0 +1,92 @@
+package servers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testBackpressureSubscription(policy types.BackpressurePolicy) *ClientSubscription {
+	return &ClientSubscription{
+		ReqOptions: types.ReqOptions{Backpressure: policy},
+		feed:       make(chan types.Notification, 2),
+		feedType:   types.TransactionStatusFeed,
+		stats:      &subscriptionStats{},
+	}
+}
+
+func testQueued(sub *ClientSubscription) []string {
+	var hashes []string
+	for len(sub.feed) > 0 {
+		hashes = append(hashes, (<-sub.feed).GetHash())
+	}
+	return hashes
+}
+
+func TestFeedManagerBackpressure(t *testing.T) {
+	tests := []struct {
+		policy  types.BackpressurePolicy
+		open    bool
+		queued  []string
+		dropped uint64
+	}{
+		{policy: types.BackpressureDisconnect, open: false, queued: []string{"0x01", "0x02"}},
+		{policy: types.BackpressureDropNewest, open: true, queued: []string{"0x01", "0x02"}, dropped: 1},
+		{policy: types.BackpressureDropOldest, open: true, queued: []string{"0x02", "0x03"}, dropped: 1},
+		{policy: types.BackpressureCoalesce, open: true, queued: []string{"0x03"}, dropped: 2},
+	}
+
+	f := &FeedManager{}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			sub := testBackpressureSubscription(tt.policy)
+			assert.True(t, f.enqueue(sub, testStatusNotification("0x01")))
+			assert.True(t, f.enqueue(sub, testStatusNotification("0x02")))
+			assert.Equal(t, tt.open, f.enqueue(sub, testStatusNotification("0x03")))
+
+			assert.Equal(t, tt.dropped, sub.stats.dropped.Load())
+			// the queued notifications are not sent until the writer delivers them
+			assert.Zero(t, sub.stats.sent.Load())
+			assert.Equal(t, tt.queued, testQueued(sub))
+		})
+	}
+}
+
+func TestParseBackpressurePolicy(t *testing.T) {
+	policy, err := parseBackpressurePolicy(types.NewTxsFeed, "")
+	require.NoError(t, err)
+	assert.Equal(t, types.BackpressureDisconnect, policy)
+
+	policy, err = parseBackpressurePolicy(types.NewTxsFeed, "drop-oldest")
+	require.NoError(t, err)
+	assert.Equal(t, types.BackpressureDropOldest, policy)
+
+	policy, err = parseBackpressurePolicy(types.BDNBlocksFeed, "coalesce")
+	require.NoError(t, err)
+	assert.Equal(t, types.BackpressureCoalesce, policy)
+
+	_, err = parseBackpressurePolicy(types.NewTxsFeed, "coalesce")
+	assert.EqualError(t, err, "coalesce backpressure policy is not supported by newTxs feed")
+
+	_, err = parseBackpressurePolicy(types.NewTxsFeed, "block")
+	assert.EqualError(t, err, "invalid backpressure policy block, possible policies are: [disconnect drop-oldest drop-newest coalesce]")
+}
+
+func TestSubscriptionStatsSendLatency(t *testing.T) {
+	stats := &subscriptionStats{}
+	assert.Equal(t, time.Duration(0), stats.avgSendLatency())
+
+	stats.observeSend(10 * time.Millisecond)
+	stats.observeSend(30 * time.Millisecond)
+	stats.observeSend(20 * time.Millisecond)
+
+	assert.Equal(t, uint64(3), stats.sent.Load())
+	assert.Equal(t, 20*time.Millisecond, stats.avgSendLatency())
+	assert.Equal(t, int64(30*time.Millisecond), stats.maxSendLatency.Load())
+
+	// subscriptions created outside of the feed manager have no stats
+	(&ClientSubscriptionHandlingInfo{}).observeSend(time.Now())
+}
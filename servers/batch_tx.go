@@ -0,0 +1,279 @@
+package servers
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/bloXroute-Labs/gateway/v2/blockchain"
+	"github.com/bloXroute-Labs/gateway/v2/connections"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils/ofac"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// MaxBatchTxs bounds the transactions of a batch, larger batches are rejected before any of their transactions is validated
+const MaxBatchTxs = 10
+
+// BatchTxCode classifies the result of a transaction of a batch
+type BatchTxCode string
+
+// BatchTxCode enumeration
+const (
+	BatchTxSent                BatchTxCode = "sent"
+	BatchTxInvalid             BatchTxCode = "invalid_transaction"
+	BatchTxInvalidChainID      BatchTxCode = "invalid_chain_id"
+	BatchTxInvalidSignature    BatchTxCode = "invalid_signature"
+	BatchTxInvalidNonceOrder   BatchTxCode = "invalid_nonce_order"
+	BatchTxNonceTooLow         BatchTxCode = "nonce_too_low"
+	BatchTxSanctioned          BatchTxCode = "sanctioned"
+	BatchTxInsufficientBalance BatchTxCode = "insufficient_balance"
+	BatchTxReverted            BatchTxCode = "reverted"
+	BatchTxNodeUnavailable     BatchTxCode = "node_unavailable"
+	BatchTxRejected            BatchTxCode = "rejected"
+	// BatchTxNotSent is the code of the valid transactions of an atomic batch which has invalid transactions
+	BatchTxNotSent BatchTxCode = "not_sent"
+)
+
+// BatchTxResult is the outcome of a transaction of a batch
+type BatchTxResult struct {
+	Idx    int
+	TxHash string
+	Code   BatchTxCode
+	Error  string
+}
+
+// Sent returns true if the transaction was sent
+func (r BatchTxResult) Sent() bool {
+	return r.Code == BatchTxSent
+}
+
+// BatchTxOptions are the options the transactions of a batch are sent with
+type BatchTxOptions struct {
+	ValidatorsOnly         bool
+	NextValidator          bool
+	NodeValidation         bool
+	FrontRunningProtection bool
+	Fallback               uint16
+	Simulate               bool
+	// Atomic validates the batch as a whole before sending any transaction, and sends none of them if one is invalid.
+	// The transactions already sent can't be recalled, if one fails to be sent the following ones are not sent
+	Atomic bool
+}
+
+// batchTxError is a validation error of a transaction of a batch
+type batchTxError struct {
+	code BatchTxCode
+	err  error
+}
+
+func (e *batchTxError) Error() string {
+	return e.err.Error()
+}
+
+func newBatchTxError(code BatchTxCode, format string, args ...interface{}) *batchTxError {
+	return &batchTxError{code: code, err: fmt.Errorf(format, args...)}
+}
+
+// HandleBatchTransactions sends the transactions of a batch and returns the result of each of them. The senders are
+// optional and set the sender of the transaction with the same index
+func HandleBatchTransactions(feedManager *FeedManager, transactions []string, senders [][]byte, conn connections.Conn, options BatchTxOptions) []BatchTxResult {
+	results := make([]BatchTxResult, len(transactions))
+	for i := range results {
+		results[i].Idx = i
+	}
+
+	if options.Atomic && !validateBatchTransactions(feedManager, transactions, conn.GetAccountID(), options.Simulate, results) {
+		return results
+	}
+
+	failed := -1
+	for i, transaction := range transactions {
+		if options.Atomic && failed >= 0 {
+			results[i].Code = BatchTxNotSent
+			results[i].Error = fmt.Sprintf("transaction %v of the batch failed to be sent", failed)
+			continue
+		}
+		if options.Simulate && !options.Atomic {
+			if err := SimulateTransaction(feedManager, transaction); err != nil {
+				results[i].setError(err)
+				continue
+			}
+		}
+
+		var sender []byte
+		if i < len(senders) {
+			sender = senders[i]
+		}
+		txHash, ok, err := HandleSingleTransaction(feedManager, transaction, sender, conn, options.ValidatorsOnly,
+			options.NextValidator, options.NodeValidation, options.FrontRunningProtection, options.Fallback,
+			feedManager.nextValidatorMap, feedManager.validatorStatusMap)
+		if err != nil {
+			results[i].setError(err)
+			failed = i
+			continue
+		}
+		if !ok {
+			results[i].setError(errors.New("failed to send transaction"))
+			failed = i
+			continue
+		}
+		results[i].TxHash = txHash
+		results[i].Code = BatchTxSent
+	}
+	return results
+}
+
+// validateBatchTransactions checks the signature, chain ID and sanctions of each transaction, that the nonces of each
+// sender follow each other, and the nonce and balance of each sender with the node. It returns false and sets the
+// results if a transaction is invalid
+func validateBatchTransactions(feedManager *FeedManager, transactions []string, accountID types.AccountID, simulate bool, results []BatchTxResult) bool {
+	txs := make([]*ethtypes.Transaction, len(transactions))
+	type senderTxs struct {
+		firstNonce uint64
+		lastNonce  uint64
+		cost       *big.Int
+		first      int
+	}
+	senders := make(map[common.Address]*senderTxs)
+	var order []common.Address
+
+	valid := true
+	for i, transaction := range transactions {
+		tx, sender, err := validateBatchTransaction(transaction, feedManager.chainID, accountID)
+		if err != nil {
+			results[i].setError(err)
+			valid = false
+			continue
+		}
+		txs[i] = tx
+		results[i].TxHash = strings.TrimPrefix(tx.Hash().Hex(), "0x")
+
+		cost := new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), tx.GasFeeCap())
+		cost.Add(cost, tx.Value())
+		s, ok := senders[sender]
+		if !ok {
+			senders[sender] = &senderTxs{firstNonce: tx.Nonce(), lastNonce: tx.Nonce(), cost: cost, first: i}
+			order = append(order, sender)
+			continue
+		}
+		if tx.Nonce() != s.lastNonce+1 {
+			results[i].setError(newBatchTxError(BatchTxInvalidNonceOrder, "nonce %v of sender %v does not follow its previous nonce %v in the batch", tx.Nonce(), sender, s.lastNonce))
+			valid = false
+			continue
+		}
+		s.lastNonce = tx.Nonce()
+		s.cost.Add(s.cost, cost)
+	}
+
+	if valid {
+		ws, ok := feedManager.nodeWSManager.SyncedProvider()
+		if !ok {
+			for i := range results {
+				results[i].setError(newBatchTxError(BatchTxNodeUnavailable, "cannot validate the batch, the gateway has no synced blockchain node websocket connection"))
+			}
+			return false
+		}
+		for _, sender := range order {
+			s := senders[sender]
+			if err := validateBatchSender(ws, sender, s.firstNonce, s.cost); err != nil {
+				results[s.first].setError(err)
+				valid = false
+			}
+		}
+		if valid && simulate {
+			for i, tx := range txs {
+				if err := simulateTransaction(ws, tx); err != nil {
+					results[i].setError(err)
+					valid = false
+				}
+			}
+		}
+	}
+
+	if valid {
+		return true
+	}
+	for i := range results {
+		if results[i].Code == "" {
+			results[i].Code = BatchTxNotSent
+			results[i].Error = "batch contains invalid transactions"
+		}
+	}
+	return false
+}
+
+func validateBatchTransaction(transaction string, chainID types.NetworkID, accountID types.AccountID) (*ethtypes.Transaction, common.Address, error) {
+	tx, err := ParseRawTransaction(transaction)
+	if err != nil {
+		return nil, common.Address{}, &batchTxError{code: BatchTxInvalid, err: err}
+	}
+	if tx.ChainId().Int64() != 0 && chainID != 0 && types.NetworkID(tx.ChainId().Int64()) != chainID {
+		return nil, common.Address{}, newBatchTxError(BatchTxInvalidChainID, "chainID mismatch for hash %v, expect %v got %v", tx.Hash(), chainID, tx.ChainId())
+	}
+	sender, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, common.Address{}, newBatchTxError(BatchTxInvalidSignature, "invalid signature for hash %v: %v", tx.Hash(), err)
+	}
+	if err = ofac.CheckTransaction(tx, accountID); err != nil {
+		return nil, common.Address{}, err
+	}
+	return tx, sender, nil
+}
+
+// validateBatchSender checks that the first nonce of the sender in the batch is not used yet, and that its balance
+// covers the maximum cost of its transactions
+func validateBatchSender(ws blockchain.WSProvider, sender common.Address, firstNonce uint64, cost *big.Int) error {
+	response, err := ws.CallRPC("eth_getTransactionCount", []interface{}{sender.Hex(), "latest"}, blockchain.DefaultRPCOptions)
+	if err != nil {
+		return newBatchTxError(BatchTxNodeUnavailable, "failed to fetch the nonce of sender %v: %v", sender, err)
+	}
+	nonce, err := hexutil.DecodeUint64(interfaceToString(response))
+	if err != nil {
+		return newBatchTxError(BatchTxNodeUnavailable, "failed to decode the nonce of sender %v: %v", sender, err)
+	}
+	if firstNonce < nonce {
+		return newBatchTxError(BatchTxNonceTooLow, "nonce %v of sender %v is too low, next nonce is %v", firstNonce, sender, nonce)
+	}
+
+	response, err = ws.CallRPC("eth_getBalance", []interface{}{sender.Hex(), "latest"}, blockchain.DefaultRPCOptions)
+	if err != nil {
+		return newBatchTxError(BatchTxNodeUnavailable, "failed to fetch the balance of sender %v: %v", sender, err)
+	}
+	balance, err := hexutil.DecodeBig(interfaceToString(response))
+	if err != nil {
+		return newBatchTxError(BatchTxNodeUnavailable, "failed to decode the balance of sender %v: %v", sender, err)
+	}
+	if balance.Cmp(cost) < 0 {
+		return newBatchTxError(BatchTxInsufficientBalance, "balance %v of sender %v does not cover the cost %v of its transactions", balance, sender, cost)
+	}
+	return nil
+}
+
+func (r *BatchTxResult) setError(err error) {
+	r.Error = err.Error()
+	r.Code = batchTxCode(err)
+}
+
+// batchTxCode classifies the errors of the validation and of the transaction processing
+func batchTxCode(err error) BatchTxCode {
+	var batchErr *batchTxError
+	if errors.As(err, &batchErr) {
+		return batchErr.code
+	}
+	var blockedErr *ofac.BlockedError
+	if errors.As(err, &blockedErr) {
+		return BatchTxSanctioned
+	}
+	var revertedErr *TxRevertedError
+	if errors.As(err, &revertedErr) {
+		return BatchTxReverted
+	}
+	if errors.Is(err, errSimulationUnavailable) {
+		return BatchTxNodeUnavailable
+	}
+	return BatchTxRejected
+}
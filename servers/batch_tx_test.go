@@ -0,0 +1,131 @@
+package servers
+
+import (
+	"testing"
+
+	"github.com/bloXroute-Labs/gateway/v2/blockchain"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBatchWSManager provides the synced node of the batch validation, if any
+type testBatchWSManager struct {
+	blockchain.WSManager
+	provider blockchain.WSProvider
+}
+
+func (m *testBatchWSManager) SyncedProvider() (blockchain.WSProvider, bool) {
+	return m.provider, m.provider != nil
+}
+
+// newTestBatchProvider returns the same nonce and balance for every sender
+func newTestBatchProvider(nonce, balance string) *testSimulationProvider {
+	return &testSimulationProvider{responses: map[string]interface{}{
+		"eth_getTransactionCount": nonce,
+		"eth_getBalance":          balance,
+		simulateCallMethod:        "0x",
+	}}
+}
+
+func batchTxCodes(results []BatchTxResult) []BatchTxCode {
+	codes := make([]BatchTxCode, 0, len(results))
+	for _, result := range results {
+		codes = append(codes, result.Code)
+	}
+	return codes
+}
+
+func TestValidateBatchTransactions(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	otherKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		chainID      types.NetworkID
+		provider     blockchain.WSProvider
+		transactions []string
+		simulate     bool
+		expected     []BatchTxCode
+	}{
+		{
+			name:         "valid",
+			chainID:      1,
+			provider:     newTestBatchProvider("0x5", "0xffffffffff"),
+			transactions: []string{newFeeBumpTx(t, key, 5, 100, 10), newFeeBumpTx(t, otherKey, 5, 100, 10), newFeeBumpTx(t, key, 6, 100, 10)},
+			expected:     []BatchTxCode{"", "", ""},
+		},
+		{
+			name:         "nonce gap",
+			chainID:      1,
+			provider:     newTestBatchProvider("0x5", "0xffffffffff"),
+			transactions: []string{newFeeBumpTx(t, key, 5, 100, 10), newFeeBumpTx(t, key, 7, 100, 10)},
+			expected:     []BatchTxCode{BatchTxNotSent, BatchTxInvalidNonceOrder},
+		},
+		{
+			name:         "nonce too low",
+			chainID:      1,
+			provider:     newTestBatchProvider("0x6", "0xffffffffff"),
+			transactions: []string{newFeeBumpTx(t, key, 5, 100, 10), newFeeBumpTx(t, key, 6, 100, 10)},
+			expected:     []BatchTxCode{BatchTxNonceTooLow, BatchTxNotSent},
+		},
+		{
+			// each transaction costs 21000 * 100 + 1, the balance covers only one of them
+			name:         "insufficient balance",
+			chainID:      1,
+			provider:     newTestBatchProvider("0x5", "0x200b21"),
+			transactions: []string{newFeeBumpTx(t, otherKey, 5, 100, 10), newFeeBumpTx(t, key, 5, 100, 10), newFeeBumpTx(t, key, 6, 100, 10)},
+			expected:     []BatchTxCode{BatchTxNotSent, BatchTxInsufficientBalance, BatchTxNotSent},
+		},
+		{
+			name:         "chain ID mismatch",
+			chainID:      56,
+			provider:     newTestBatchProvider("0x5", "0xffffffffff"),
+			transactions: []string{newFeeBumpTx(t, key, 5, 100, 10)},
+			expected:     []BatchTxCode{BatchTxInvalidChainID},
+		},
+		{
+			name:         "invalid transaction",
+			chainID:      1,
+			provider:     newTestBatchProvider("0x5", "0xffffffffff"),
+			transactions: []string{newFeeBumpTx(t, key, 5, 100, 10), "00"},
+			expected:     []BatchTxCode{BatchTxNotSent, BatchTxInvalid},
+		},
+		{
+			name:         "no synced node",
+			chainID:      1,
+			transactions: []string{newFeeBumpTx(t, key, 5, 100, 10), newFeeBumpTx(t, key, 6, 100, 10)},
+			expected:     []BatchTxCode{BatchTxNodeUnavailable, BatchTxNodeUnavailable},
+		},
+		{
+			name:    "reverted",
+			chainID: 1,
+			provider: &testSimulationProvider{
+				responses: map[string]interface{}{"eth_getTransactionCount": "0x5", "eth_getBalance": "0xffffffffff"},
+				errors:    map[string]error{simulateCallMethod: &testRPCError{message: "execution reverted", data: "0x12345678"}},
+			},
+			transactions: []string{newFeeBumpTx(t, key, 5, 100, 10)},
+			simulate:     true,
+			expected:     []BatchTxCode{BatchTxReverted},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			feedManager := &FeedManager{chainID: test.chainID, nodeWSManager: &testBatchWSManager{provider: test.provider}}
+			results := make([]BatchTxResult, len(test.transactions))
+			valid := validateBatchTransactions(feedManager, test.transactions, "account", test.simulate, results)
+
+			assert.Equal(t, test.expected, batchTxCodes(results))
+			assert.Equal(t, test.expected[0] == "", valid)
+			for _, result := range results {
+				if result.Code != "" {
+					assert.NotEmpty(t, result.Error)
+				}
+			}
+		})
+	}
+}
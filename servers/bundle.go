@@ -13,6 +13,7 @@ import (
 	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
 	log "github.com/bloXroute-Labs/gateway/v2/logger"
 	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
 	"github.com/bloXroute-Labs/gateway/v2/utils/ofac"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -74,7 +75,8 @@ func GetRawTxsHashes(transactions []string) ([]string, error) {
 }
 
 // ParseRawTransactionGroup is a helper function used to process a group of rawTransactions
-func ParseRawTransactionGroup(transactions []string, trimTxHashPrefix bool, chainID int64) (*RawTransactionGroupData, error) {
+// The OFAC checks follow the sanctions policy of the account
+func ParseRawTransactionGroup(transactions []string, trimTxHashPrefix bool, chainID int64, accountID types.AccountID) (*RawTransactionGroupData, error) {
 	bundleHash := sha3.NewLegacyKeccak256()
 	rawTxs := make([]string, 0, len(transactions))
 	txHashes := make([]string, 0, len(transactions))
@@ -110,9 +112,10 @@ func ParseRawTransactionGroup(transactions []string, trimTxHashPrefix bool, chai
 
 		bundleHash.Write(transaction.Hash().Bytes())
 
-		if addresses, shouldBlock := ofac.ShouldBlockTransaction(transaction); shouldBlock {
+		var blockedErr *ofac.BlockedError
+		if errors.As(ofac.CheckTransaction(transaction, accountID), &blockedErr) {
 			blockedTxHashes = append(blockedTxHashes, trimmedHash)
-			for _, address := range addresses {
+			for _, address := range blockedErr.Addresses {
 				if _, found := sanctionedAddressMap[address]; !found {
 					sanctionedAddressMap[address] = true
 					sanctionedAddresses = append(sanctionedAddresses, address)
@@ -132,9 +135,9 @@ func ParseRawTransactionGroup(transactions []string, trimTxHashPrefix bool, chai
 
 // parseBundle is a function used by the blxr_submit_bundle handler on the gateway
 // includes OFAC checks
-func parseBundle(transactions []string, chainID int64) (*GatewayParsedBundle, error) {
+func parseBundle(transactions []string, chainID int64, accountID types.AccountID) (*GatewayParsedBundle, error) {
 	parsedBundle := GatewayParsedBundle{}
-	txGroupData, err := ParseRawTransactionGroup(transactions, false, chainID)
+	txGroupData, err := ParseRawTransactionGroup(transactions, false, chainID, accountID)
 	if err != nil {
 		return nil, err
 	}
@@ -173,7 +176,7 @@ func trimZeroFromHEX(hex string) (string, error) {
 	return strings.ToLower(hexutil.EncodeUint64(value)), nil
 }
 
-func mevBundleFromRequest(payload *jsonrpc.RPCBundleSubmissionPayload, networkNum types.NetworkNum) (*bxmessage.MEVBundle, string, error) {
+func mevBundleFromRequest(payload *jsonrpc.RPCBundleSubmissionPayload, networkNum types.NetworkNum, accountID types.AccountID) (*bxmessage.MEVBundle, string, error) {
 	if err := payload.Validate(); err != nil {
 		return nil, "", fmt.Errorf("%w: %v", errInvalidPayload, err)
 	}
@@ -182,7 +185,7 @@ func mevBundleFromRequest(payload *jsonrpc.RPCBundleSubmissionPayload, networkNu
 		return nil, "", fmt.Errorf("%w: %v", errInvalidNetwork, networkNum)
 	}
 
-	parsedBundle, err := parseBundle(payload.Transaction, int64(bxgateway.NetworkNumToChainID[networkNum]))
+	parsedBundle, err := parseBundle(payload.Transaction, int64(bxgateway.NetworkNumToChainID[networkNum]), accountID)
 	if err != nil {
 		return nil, "", fmt.Errorf("%w: %v", errUnableToParseBundle, err)
 	}
@@ -217,6 +220,27 @@ func mevBundleFromRequest(payload *jsonrpc.RPCBundleSubmissionPayload, networkNu
 		return nil, "", err
 	}
 
+	if (payload.TargetBlocks > 1 || payload.MaxBlockNumber != "") && len(mevBundle.Transactions) > 0 {
+		blockNumber, err := hexutil.DecodeUint64(payload.BlockNumber)
+		if err != nil {
+			// Validated before, should not happen
+			return nil, "", err
+		}
+		if payload.MaxBlockNumber != "" {
+			mevBundle.MaxBlockNumber, err = hexutil.DecodeUint64(payload.MaxBlockNumber)
+			if err != nil {
+				// Validated before, should not happen
+				return nil, "", err
+			}
+		} else {
+			mevBundle.MaxBlockNumber = blockNumber + uint64(payload.TargetBlocks) - 1
+		}
+		// the builders replace the bundle submitted for the previous block with the same UUID
+		if mevBundle.UUID == "" {
+			mevBundle.UUID = utils.GenerateUUID()
+		}
+	}
+
 	mevBundle.SetHash()
 
 	return &mevBundle, parsedBundle.bundleHash, nil
@@ -0,0 +1,404 @@
+package servers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/bloXroute-Labs/gateway/v2"
+	"github.com/bloXroute-Labs/gateway/v2/blockchain"
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	pb "github.com/bloXroute-Labs/gateway/v2/protobuf"
+	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
+	"github.com/bloXroute-Labs/gateway/v2/services"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	simulateBundleMethod = "eth_callBundle"
+	simulateBlockMethod  = "eth_getBlockByNumber"
+	// methodNotFoundCode is the JSON-RPC error code of the methods a node does not provide
+	methodNotFoundCode = -32601
+)
+
+var errBundleSimulationUnavailable = errors.New("cannot simulate bundle, the gateway has no synced blockchain node websocket connection")
+
+// BundleSimulationTxResult is the outcome of a transaction of a simulated bundle, amounts are decimal strings in wei
+type BundleSimulationTxResult struct {
+	TxHash      string `json:"txHash"`
+	FromAddress string `json:"fromAddress"`
+	ToAddress   string `json:"toAddress,omitempty"`
+	GasUsed     uint64 `json:"gasUsed"`
+	// GasPrice is the effective gas price of the transaction
+	GasPrice     string `json:"gasPrice"`
+	CoinbaseDiff string `json:"coinbaseDiff"`
+	Error        string `json:"error,omitempty"`
+	Revert       string `json:"revert,omitempty"`
+}
+
+// BundleSimulationResult is the outcome of a bundle simulated by the blockchain node of the gateway, in the format of
+// eth_callBundle
+type BundleSimulationResult struct {
+	BundleHash       string                     `json:"bundleHash"`
+	StateBlockNumber uint64                     `json:"stateBlockNumber"`
+	TotalGasUsed     uint64                     `json:"totalGasUsed"`
+	BundleGasPrice   string                     `json:"bundleGasPrice"`
+	CoinbaseDiff     string                     `json:"coinbaseDiff"`
+	Results          []BundleSimulationTxResult `json:"results"`
+	// SimulationMethod is the method of the node the bundle was simulated with, eth_callBundle or debug_traceCall
+	SimulationMethod string `json:"simulationMethod"`
+}
+
+// callFrame is the part of the callTracer result the simulation reads
+type callFrame struct {
+	GasUsed      hexutil.Uint64 `json:"gasUsed"`
+	Output       string         `json:"output"`
+	Error        string         `json:"error"`
+	RevertReason string         `json:"revertReason"`
+}
+
+// prestateAccount is an account of the prestateTracer result in diff mode
+type prestateAccount struct {
+	Balance *hexutil.Big                `json:"balance"`
+	Nonce   *uint64                     `json:"nonce"`
+	Code    hexutil.Bytes               `json:"code"`
+	Storage map[common.Hash]common.Hash `json:"storage"`
+}
+
+type prestateDiff struct {
+	Pre  map[common.Address]prestateAccount `json:"pre"`
+	Post map[common.Address]prestateAccount `json:"post"`
+}
+
+// stateOverride is the state of an account modified by the previous transactions of the bundle
+type stateOverride struct {
+	Balance   *hexutil.Big                `json:"balance,omitempty"`
+	Nonce     *hexutil.Uint64             `json:"nonce,omitempty"`
+	Code      hexutil.Bytes               `json:"code,omitempty"`
+	StateDiff map[common.Hash]common.Hash `json:"stateDiff,omitempty"`
+}
+
+// SimulateBundle executes the transactions of the bundle one after the other with a synced blockchain node, with
+// eth_callBundle if the node provides it, or else with debug_traceCall carrying the state changed by the previous
+// transactions of the bundle as state overrides. The bundle is bounded by the txs limit of the bundles of the account
+func SimulateBundle(feedManager *FeedManager, connectionAccount sdnmessage.Account, params *jsonrpc.RPCBundleSimulationPayload) (*BundleSimulationResult, error) {
+	if err := params.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidPayload, err)
+	}
+	maxTxsLen := connectionAccount.Bundles.Networks[bxgateway.NetworkNumToBlockchainNetwork[feedManager.networkNum]].TxsLenLimit
+	if maxTxsLen > 0 && len(params.Transaction) > maxTxsLen {
+		return nil, fmt.Errorf("%w: txs limit exceeded, max txs allowed: %v", errInvalidPayload, maxTxsLen)
+	}
+	txs := make([]*ethtypes.Transaction, 0, len(params.Transaction))
+	for _, transaction := range params.Transaction {
+		tx, err := ParseRawTransaction(transaction)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errInvalidPayload, err)
+		}
+		txs = append(txs, tx)
+	}
+
+	ws, ok := feedManager.nodeWSManager.SyncedProvider()
+	if !ok {
+		return nil, errBundleSimulationUnavailable
+	}
+	return simulateBundle(ws, params, txs)
+}
+
+func simulateBundle(ws blockchain.WSProvider, params *jsonrpc.RPCBundleSimulationPayload, txs []*ethtypes.Transaction) (*BundleSimulationResult, error) {
+	stateBlock := params.StateBlockNumber
+	if stateBlock == "" {
+		stateBlock = "latest"
+	}
+
+	result, err := callBundle(ws, params, stateBlock)
+	if err == nil || !isMethodNotFound(err) {
+		return result, err
+	}
+	return traceBundle(ws, params, stateBlock, txs)
+}
+
+// callBundle simulates the bundle with eth_callBundle, provided by the builder clients
+func callBundle(ws blockchain.WSProvider, params *jsonrpc.RPCBundleSimulationPayload, stateBlock string) (*BundleSimulationResult, error) {
+	args := map[string]interface{}{
+		"txs":              params.Transaction,
+		"blockNumber":      params.BlockNumber,
+		"stateBlockNumber": stateBlock,
+	}
+	if params.Timestamp > 0 {
+		args["timestamp"] = params.Timestamp
+	}
+
+	response, err := ws.CallRPC(simulateBundleMethod, []interface{}{args}, simulationRPCOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to simulate bundle with %v: %w", simulateBundleMethod, err)
+	}
+
+	var result BundleSimulationResult
+	if err = decodeRPCResponse(response, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode %v response: %v", simulateBundleMethod, err)
+	}
+	for i := range result.Results {
+		result.Results[i].Revert = bundleRevertReason(result.Results[i].Revert)
+	}
+	result.SimulationMethod = simulateBundleMethod
+	return &result, nil
+}
+
+// traceBundle simulates the transactions of the bundle one by one with debug_traceCall. The callTracer provides the gas
+// used and the revert reason of a transaction, and the prestateTracer the state it changed, which overrides the state
+// of the following transactions
+func traceBundle(ws blockchain.WSProvider, params *jsonrpc.RPCBundleSimulationPayload, stateBlock string, txs []*ethtypes.Transaction) (*BundleSimulationResult, error) {
+	response, err := ws.CallRPC(simulateBlockMethod, []interface{}{stateBlock, false}, simulationRPCOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %v: %v", stateBlock, err)
+	}
+	var header struct {
+		Number   hexutil.Uint64 `json:"number"`
+		Miner    common.Address `json:"miner"`
+		BaseFee  *hexutil.Big   `json:"baseFeePerGas"`
+		GasUsed  hexutil.Uint64 `json:"gasUsed"`
+		GasLimit hexutil.Uint64 `json:"gasLimit"`
+	}
+	if err = decodeRPCResponse(response, &header); err != nil {
+		return nil, fmt.Errorf("failed to decode block %v: %v", stateBlock, err)
+	}
+
+	// the transactions pay the base fee of the block following the state block, and the coinbase diff is measured on
+	// the fee recipient of the state block
+	blockOverrides := map[string]interface{}{"number": params.BlockNumber, "coinbase": header.Miner}
+	var baseFee *big.Int
+	if header.BaseFee != nil {
+		baseFee = services.NextBaseFee(header.BaseFee.ToInt(), uint64(header.GasUsed), uint64(header.GasLimit))
+		blockOverrides["baseFee"] = (*hexutil.Big)(baseFee)
+	}
+	if params.Timestamp > 0 {
+		blockOverrides["time"] = hexutil.Uint64(params.Timestamp)
+	}
+
+	overrides := make(map[common.Address]*stateOverride)
+	totalCoinbaseDiff := new(big.Int)
+	txHashes := make([]byte, 0, len(txs)*common.HashLength)
+	result := &BundleSimulationResult{
+		StateBlockNumber: uint64(header.Number),
+		Results:          make([]BundleSimulationTxResult, 0, len(txs)),
+		SimulationMethod: simulateTraceMethod,
+	}
+	for _, tx := range txs {
+		args, err := txCallArgs(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		var frame callFrame
+		if err = traceCall(ws, args, stateBlock, map[string]interface{}{
+			"tracer":         "callTracer",
+			"stateOverrides": overrides,
+			"blockOverrides": blockOverrides,
+		}, &frame); err != nil {
+			return nil, fmt.Errorf("failed to simulate transaction %v: %w", tx.Hash(), err)
+		}
+		var diff prestateDiff
+		if err = traceCall(ws, args, stateBlock, map[string]interface{}{
+			"tracer":         "prestateTracer",
+			"tracerConfig":   map[string]interface{}{"diffMode": true},
+			"stateOverrides": overrides,
+			"blockOverrides": blockOverrides,
+		}, &diff); err != nil {
+			return nil, fmt.Errorf("failed to simulate transaction %v: %w", tx.Hash(), err)
+		}
+		applyStateDiff(overrides, &diff)
+
+		coinbaseDiff := diff.balanceDiff(header.Miner)
+		totalCoinbaseDiff.Add(totalCoinbaseDiff, coinbaseDiff)
+		txResult := BundleSimulationTxResult{
+			TxHash:       tx.Hash().Hex(),
+			FromAddress:  args["from"].(string),
+			GasUsed:      uint64(frame.GasUsed),
+			GasPrice:     effectiveGasPrice(tx, baseFee).String(),
+			CoinbaseDiff: coinbaseDiff.String(),
+			Error:        frame.Error,
+		}
+		if to := tx.To(); to != nil {
+			txResult.ToAddress = to.Hex()
+		}
+		if frame.Error != "" {
+			txResult.Revert = frame.RevertReason
+			if txResult.Revert == "" {
+				txResult.Revert = decodeRevertReason(frame.Output)
+			}
+		}
+		result.Results = append(result.Results, txResult)
+		result.TotalGasUsed += txResult.GasUsed
+		txHashes = append(txHashes, tx.Hash().Bytes()...)
+	}
+
+	result.BundleHash = crypto.Keccak256Hash(txHashes).Hex()
+	result.CoinbaseDiff = totalCoinbaseDiff.String()
+	bundleGasPrice := new(big.Int)
+	if result.TotalGasUsed > 0 {
+		bundleGasPrice.Div(totalCoinbaseDiff, new(big.Int).SetUint64(result.TotalGasUsed))
+	}
+	result.BundleGasPrice = bundleGasPrice.String()
+	return result, nil
+}
+
+func traceCall(ws blockchain.WSProvider, args map[string]interface{}, stateBlock string, config map[string]interface{}, result interface{}) error {
+	response, err := ws.CallRPC(simulateTraceMethod, []interface{}{args, stateBlock, config}, simulationRPCOptions)
+	if err != nil {
+		if isMethodNotFound(err) {
+			return fmt.Errorf("the blockchain node provides neither %v nor %v: %w", simulateBundleMethod, simulateTraceMethod, err)
+		}
+		return err
+	}
+	return decodeRPCResponse(response, result)
+}
+
+// applyStateDiff adds the state changed by a transaction to the state overrides of the following transactions
+func applyStateDiff(overrides map[common.Address]*stateOverride, diff *prestateDiff) {
+	for address, post := range diff.Post {
+		override, ok := overrides[address]
+		if !ok {
+			override = &stateOverride{}
+			overrides[address] = override
+		}
+		if post.Balance != nil {
+			override.Balance = post.Balance
+		}
+		if post.Nonce != nil {
+			nonce := hexutil.Uint64(*post.Nonce)
+			override.Nonce = &nonce
+		}
+		if len(post.Code) > 0 {
+			override.Code = post.Code
+		}
+		for slot, value := range post.Storage {
+			if override.StateDiff == nil {
+				override.StateDiff = make(map[common.Hash]common.Hash)
+			}
+			override.StateDiff[slot] = value
+		}
+	}
+	// the slots cleared by the transaction are left out of its post state
+	for address, pre := range diff.Pre {
+		post, ok := diff.Post[address]
+		if !ok {
+			continue
+		}
+		for slot := range pre.Storage {
+			if _, ok = post.Storage[slot]; ok {
+				continue
+			}
+			override := overrides[address]
+			if override.StateDiff == nil {
+				override.StateDiff = make(map[common.Hash]common.Hash)
+			}
+			override.StateDiff[slot] = common.Hash{}
+		}
+	}
+}
+
+// balanceDiff returns the change of the balance of the address, zero if the transaction did not change it
+func (d *prestateDiff) balanceDiff(address common.Address) *big.Int {
+	post, ok := d.Post[address]
+	if !ok || post.Balance == nil {
+		return new(big.Int)
+	}
+	diff := new(big.Int).Set(post.Balance.ToInt())
+	if pre, ok := d.Pre[address]; ok && pre.Balance != nil {
+		diff.Sub(diff, pre.Balance.ToInt())
+	}
+	return diff
+}
+
+// effectiveGasPrice returns the gas price the transaction pays at the base fee, nil before London
+func effectiveGasPrice(tx *ethtypes.Transaction, baseFee *big.Int) *big.Int {
+	if baseFee == nil {
+		return tx.GasPrice()
+	}
+	tip, err := tx.EffectiveGasTip(baseFee)
+	if err != nil {
+		// the fee cap is below the base fee
+		return tx.GasFeeCap()
+	}
+	return tip.Add(tip, baseFee)
+}
+
+// bundleRevertReason decodes the revert data eth_callBundle returns as a string of bytes
+func bundleRevertReason(revert string) string {
+	if revert == "" {
+		return ""
+	}
+	if reason, err := abi.UnpackRevert([]byte(revert)); err == nil {
+		return reason
+	}
+	if strings.HasPrefix(revert, "0x") {
+		if reason := decodeRevertReason(revert); reason != "" {
+			return reason
+		}
+	}
+	// the bytes of the selector which are not valid UTF-8 are replaced in the JSON response, the reason is read from
+	// the end of the length of the Error(string) data instead
+	data := strings.TrimRight(revert, "\x00")
+	encoded := data[strings.LastIndexByte(data, 0)+1:]
+	for lengthBytes := 1; lengthBytes <= 2 && lengthBytes < len(encoded); lengthBytes++ {
+		length := 0
+		for _, b := range []byte(encoded[:lengthBytes]) {
+			length = length<<8 | int(b)
+		}
+		if length == len(encoded)-lengthBytes {
+			return encoded[lengthBytes:]
+		}
+	}
+	return revert
+}
+
+func isMethodNotFound(err error) bool {
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) {
+		return false
+	}
+	return rpcErr.ErrorCode() == methodNotFoundCode || strings.Contains(rpcErr.Error(), "does not exist")
+}
+
+// decodeRPCResponse decodes the JSON response of a node into result
+func decodeRPCResponse(response interface{}, result interface{}) error {
+	b, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, result)
+}
+
+// Protobuf formats the simulation result as a protobuf response struct
+func (r *BundleSimulationResult) Protobuf() *pb.BlxrSimulateBundleReply {
+	reply := &pb.BlxrSimulateBundleReply{
+		BundleHash:       r.BundleHash,
+		StateBlockNumber: r.StateBlockNumber,
+		TotalGasUsed:     r.TotalGasUsed,
+		BundleGasPrice:   r.BundleGasPrice,
+		CoinbaseDiff:     r.CoinbaseDiff,
+		Results:          make([]*pb.BundleSimulationTxResult, 0, len(r.Results)),
+		SimulationMethod: r.SimulationMethod,
+	}
+	for _, txResult := range r.Results {
+		reply.Results = append(reply.Results, &pb.BundleSimulationTxResult{
+			TxHash:       txResult.TxHash,
+			FromAddress:  txResult.FromAddress,
+			ToAddress:    txResult.ToAddress,
+			GasUsed:      txResult.GasUsed,
+			GasPrice:     txResult.GasPrice,
+			CoinbaseDiff: txResult.CoinbaseDiff,
+			Error:        txResult.Error,
+			Revert:       txResult.Revert,
+		})
+	}
+	return reply
+}
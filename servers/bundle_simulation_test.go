@@ -0,0 +1,159 @@
+package servers
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/bloXroute-Labs/gateway/v2/blockchain"
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBundleSimulationProvider answers the simulation calls of the node with respond, and records their payloads
+type testBundleSimulationProvider struct {
+	blockchain.WSProvider
+	calls   []string
+	respond func(method string, payload []interface{}) (interface{}, error)
+}
+
+func (p *testBundleSimulationProvider) CallRPC(method string, payload []interface{}, _ blockchain.RPCOptions) (interface{}, error) {
+	b, _ := json.Marshal(payload)
+	p.calls = append(p.calls, method+" "+string(b))
+	return p.respond(method, payload)
+}
+
+func tracerName(payload []interface{}) string {
+	return payload[2].(map[string]interface{})["tracer"].(string)
+}
+
+func TestSimulateBundleWithCallBundle(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	params := &jsonrpc.RPCBundleSimulationPayload{Transaction: []string{newFeeBumpTx(t, key, 0, 100, 10)}, BlockNumber: "0x11"}
+	tx, err := ParseRawTransaction(params.Transaction[0])
+	require.NoError(t, err)
+
+	revertData := common.FromHex(testRevertData(t, "too little received"))
+	provider := &testBundleSimulationProvider{respond: func(method string, payload []interface{}) (interface{}, error) {
+		require.Equal(t, simulateBundleMethod, method)
+		return map[string]interface{}{
+			"bundleHash":       "0x01",
+			"bundleGasPrice":   "10",
+			"coinbaseDiff":     "210000",
+			"stateBlockNumber": 16,
+			"totalGasUsed":     21000,
+			"results": []interface{}{map[string]interface{}{
+				"txHash":       tx.Hash().Hex(),
+				"gasUsed":      21000,
+				"gasPrice":     "60",
+				"coinbaseDiff": "210000",
+				"error":        "execution reverted",
+				"revert":       string(revertData),
+			}},
+		}, nil
+	}}
+
+	result, err := simulateBundle(provider, params, nil)
+	require.NoError(t, err)
+	assert.Equal(t, simulateBundleMethod, result.SimulationMethod)
+	assert.Equal(t, uint64(16), result.StateBlockNumber)
+	require.Len(t, result.Results, 1)
+	assert.Equal(t, uint64(21000), result.Results[0].GasUsed)
+	assert.Equal(t, "too little received", result.Results[0].Revert)
+	assert.Contains(t, provider.calls[0], `"stateBlockNumber":"latest"`)
+}
+
+func TestSimulateBundleWithTraceCall(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	coinbase := common.HexToAddress("0x95222290dd7278aa3ddd389cc1e1d165cc4bafe5")
+	params := &jsonrpc.RPCBundleSimulationPayload{
+		Transaction: []string{newFeeBumpTx(t, key, 5, 100, 10), newFeeBumpTx(t, key, 6, 40, 10)},
+		BlockNumber: "0x11",
+	}
+	txs := make([]*ethtypes.Transaction, 0, len(params.Transaction))
+	for _, transaction := range params.Transaction {
+		tx, err := ParseRawTransaction(transaction)
+		require.NoError(t, err)
+		txs = append(txs, tx)
+	}
+
+	traces := 0
+	provider := &testBundleSimulationProvider{respond: func(method string, payload []interface{}) (interface{}, error) {
+		switch method {
+		case simulateBundleMethod:
+			return nil, &testRPCError{message: "the method eth_callBundle does not exist/is not available"}
+		case simulateBlockMethod:
+			return map[string]interface{}{"number": "0x10", "miner": coinbase.Hex(), "baseFeePerGas": "0x32", "gasUsed": "0x3c", "gasLimit": "0x3c"}, nil
+		}
+		require.Equal(t, simulateTraceMethod, method)
+		traces++
+		first := traces <= 2
+		if tracerName(payload) == "callTracer" {
+			if first {
+				return map[string]interface{}{"gasUsed": "0x5208"}, nil
+			}
+			return map[string]interface{}{"gasUsed": "0x6000", "error": "execution reverted", "output": testRevertData(t, "too little received")}, nil
+		}
+		if first {
+			return map[string]interface{}{
+				"pre": map[string]interface{}{
+					from.Hex():     map[string]interface{}{"balance": "0x100000", "nonce": 5},
+					coinbase.Hex(): map[string]interface{}{"balance": "0x10"},
+				},
+				"post": map[string]interface{}{
+					from.Hex():     map[string]interface{}{"balance": "0x80000", "nonce": 6},
+					coinbase.Hex(): map[string]interface{}{"balance": "0x33460"},
+				},
+			}, nil
+		}
+		return map[string]interface{}{"pre": map[string]interface{}{}, "post": map[string]interface{}{}}, nil
+	}}
+
+	result, err := simulateBundle(provider, params, txs)
+	require.NoError(t, err)
+	assert.Equal(t, simulateTraceMethod, result.SimulationMethod)
+	assert.Equal(t, uint64(16), result.StateBlockNumber)
+	assert.Equal(t, uint64(21000+0x6000), result.TotalGasUsed)
+	require.Len(t, result.Results, 2)
+
+	// the fee cap of the first transaction covers the base fee of the next block and the tip
+	assert.Equal(t, "66", result.Results[0].GasPrice)
+	assert.Equal(t, "210000", result.Results[0].CoinbaseDiff)
+	assert.Empty(t, result.Results[0].Revert)
+	assert.Equal(t, "40", result.Results[1].GasPrice)
+	assert.Equal(t, "0", result.Results[1].CoinbaseDiff)
+	assert.Equal(t, "too little received", result.Results[1].Revert)
+	assert.Equal(t, "210000", result.CoinbaseDiff)
+	assert.Equal(t, new(big.Int).Div(big.NewInt(210000), big.NewInt(21000+0x6000)).String(), result.BundleGasPrice)
+	assert.Equal(t, crypto.Keccak256Hash(txs[0].Hash().Bytes(), txs[1].Hash().Bytes()).Hex(), result.BundleHash)
+
+	// the second transaction runs on the state changed by the first one
+	require.Len(t, provider.calls, 6)
+	assert.Contains(t, provider.calls[4], `"balance":"0x80000","nonce":"0x6"`)
+	assert.Contains(t, provider.calls[4], `"blockOverrides":{"baseFee":"0x38","coinbase":"`+strings.ToLower(coinbase.Hex())+`","number":"0x11"}`)
+}
+
+func TestSimulateBundleWithoutTraceCall(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	params := &jsonrpc.RPCBundleSimulationPayload{Transaction: []string{newFeeBumpTx(t, key, 0, 100, 10)}, BlockNumber: "0x11"}
+	tx, err := ParseRawTransaction(params.Transaction[0])
+	require.NoError(t, err)
+
+	provider := &testBundleSimulationProvider{respond: func(method string, payload []interface{}) (interface{}, error) {
+		if method == simulateBlockMethod {
+			return map[string]interface{}{"number": "0x10"}, nil
+		}
+		return nil, &testRPCError{message: "the method " + method + " does not exist/is not available"}
+	}}
+	_, err = simulateBundle(provider, params, []*ethtypes.Transaction{tx})
+	assert.ErrorContains(t, err, "provides neither")
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
 	"github.com/bloXroute-Labs/gateway/v2/types"
 	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/bloXroute-Labs/gateway/v2/utils/ofac"
 	"github.com/bloXroute-Labs/gateway/v2/utils/orderedmap"
 	"github.com/bloXroute-Labs/gateway/v2/utils/syncmap"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
@@ -239,6 +240,19 @@ func errorWithDelay(w http.ResponseWriter, r *http.Request, msg string) {
 	c.Close()
 }
 
+// txErrorCode returns the RPC error code of a transaction which failed to be handled
+func txErrorCode(err error) jsonrpc.RPCErrorCode {
+	var blockedErr *ofac.BlockedError
+	if errors.As(err, &blockedErr) {
+		return jsonrpc.Sanctioned
+	}
+	var revertedErr *TxRevertedError
+	if errors.As(err, &revertedErr) {
+		return jsonrpc.Reverted
+	}
+	return jsonrpc.InvalidParams
+}
+
 // SendErrorMsg formats and sends an RPC error message back to the client
 func SendErrorMsg(ctx context.Context, code jsonrpc.RPCErrorCode, data string, conn *jsonrpc2.Conn, reqID jsonrpc2.ID) {
 	rpcError := &jsonrpc2.Error{
@@ -254,7 +268,7 @@ func SendErrorMsg(ctx context.Context, code jsonrpc.RPCErrorCode, data string, c
 }
 
 // validateTxFromExternalSource validate transaction from external source (ws / grpc), return bool indicates if tx is pending reevaluation
-func validateTxFromExternalSource(transaction string, txBytes []byte, validatorsOnly bool, gatewayChainID types.NetworkID, nextValidator bool, fallback uint16, nextValidatorMap *orderedmap.OrderedMap, validatorStatusMap *syncmap.SyncMap[string, bool], networkNum types.NetworkNum, accountID types.AccountID, nodeValidationRequested bool, wsManager blockchain.WSManager, source connections.Conn, pendingBSCNextValidatorTxHashToInfo map[string]PendingNextValidatorTxInfo, frontRunningProtection bool) (*bxmessage.Tx, bool, error) {
+func validateTxFromExternalSource(transaction string, txBytes []byte, validatorsOnly bool, gatewayChainID types.NetworkID, nextValidator bool, fallback uint16, nextValidatorMap *orderedmap.OrderedMap, validatorStatusMap *syncmap.SyncMap[string, bool], networkNum types.NetworkNum, accountID types.AccountID, nodeValidationRequested bool, wsManager blockchain.WSManager, source connections.Conn, pendingBSCNextValidatorTxHashToInfo map[string]PendingNextValidatorTxInfo, frontRunningProtection bool, blockSanctioned bool) (*bxmessage.Tx, bool, error) {
 	// Ethereum's transactions encoding for RPC interfaces is slightly different from the RLP encoded format, so decode + re-encode the transaction for consistency.
 	// Specifically, note `UnmarshalBinary` should be used for RPC interfaces, and rlp.DecodeBytes should be used for the wire protocol.
 	var ethTx ethtypes.Transaction
@@ -274,6 +288,13 @@ func validateTxFromExternalSource(transaction string, txBytes []byte, validators
 		return nil, false, fmt.Errorf("chainID mismatch for hash %v, expect %v got %v, make sure the tx is sent with the right blockchain network", ethTx.Hash().String(), gatewayChainID, ethTx.ChainId().Int64())
 	}
 
+	// blocking the transactions involving sanctioned addresses is opt-in
+	if blockSanctioned {
+		if err = ofac.CheckTransaction(&ethTx, accountID); err != nil {
+			return nil, false, err
+		}
+	}
+
 	txContent, err := rlp.EncodeToBytes(&ethTx)
 
 	if err != nil {
@@ -401,7 +422,7 @@ func HandleMEVBundle(feedManager *FeedManager, conn connections.Conn, connection
 		}
 	}
 
-	mevBundle, bundleHash, err := mevBundleFromRequest(params, feedManager.networkNum)
+	mevBundle, bundleHash, err := mevBundleFromRequest(params, feedManager.networkNum, connectionAccount.AccountID)
 	var result *GatewayBundleResponse
 	if params.UUID == "" {
 		result = &GatewayBundleResponse{BundleHash: bundleHash}
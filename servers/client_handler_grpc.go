@@ -3,6 +3,7 @@ package servers
 import (
 	"context"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -16,7 +17,6 @@ import (
 	"github.com/bloXroute-Labs/gateway/v2/types"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/sourcegraph/jsonrpc2"
-	"github.com/zhouzhuojie/conditions"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
@@ -51,6 +51,25 @@ func NewGrpcHandler(feedManager GRPCFeedManager, nodeWSManager blockchain.WSMana
 	}
 }
 
+// abiRegistry returns the named contract ABIs of the gateway the abi option of a request can refer to
+func (g *GrpcHandler) abiRegistry() *abiRegistry {
+	if feedManager, ok := g.feedManager.(*FeedManager); ok {
+		return feedManager.abiRegistry
+	}
+	return nil
+}
+
+// grpcABIOption converts the abi field of a gRPC request, an ABI JSON array or the name of an ABI of the registry, to
+// the abi option of a subscription
+func grpcABIOption(contractABI string) json.RawMessage {
+	contractABI = strings.TrimSpace(contractABI)
+	if contractABI == "" || strings.HasPrefix(contractABI, "[") {
+		return json.RawMessage(contractABI)
+	}
+	name, _ := json.Marshal(contractABI)
+	return name
+}
+
 func (*GrpcHandler) decodeHex(data string) []byte {
 	hexBytes, err := hex.DecodeString(strings.TrimPrefix(data, "0x"))
 	if err != nil {
@@ -166,12 +185,25 @@ func generateTxReceiptReply(n *types.TxReceipt) *pb.TxReceiptsReply {
 			BlockHash:        interfaceToString(receiptLogMap["blockHash"]),
 			LogIndex:         interfaceToString(receiptLogMap["logIndex"]),
 			Removed:          interfaceToBool(receiptLogMap["removed"]),
+			Decoded:          decodedLogJSON(receiptLogMap[decodedLogResult]),
 		})
 	}
 
 	return txReceiptsReply
 }
 
+// decodedLogJSON formats the event decoded from a log with the abi of the subscription, empty if it is not decoded
+func decodedLogJSON(decoded interface{}) string {
+	if decoded == nil {
+		return ""
+	}
+	decodedJSON, err := json.Marshal(decoded)
+	if err != nil {
+		return ""
+	}
+	return string(decodedJSON)
+}
+
 func generateEthOnBlockReply(n *types.OnBlockNotification) *pb.EthOnBlockReply {
 	return &pb.EthOnBlockReply{
 		Name:        n.Name,
@@ -232,10 +264,14 @@ func processTx(clientReq *clientReq, notification types.Notification, multiTxsRe
 }
 
 func (g *GrpcHandler) handleTransactions(req *pb.TxsRequest, stream pb.Gateway_NewTxsServer, feedType types.FeedType, account sdnmessage.Account) error {
-	var expr conditions.Expr
+	calldata, err := newCalldataABI(grpcABIOption(req.GetAbi()), g.abiRegistry())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("error creating ABI: %v", err))
+	}
+
+	var expr *filterExpr
 	if req.GetFilters() != "" {
-		var err error
-		expr, err = validateFilters(req.GetFilters(), g.txFromFieldIncludable)
+		expr, err = validateFilters(req.GetFilters(), g.txFromFieldIncludable, calldata)
 		if err != nil {
 			return status.Error(codes.InvalidArgument, err.Error())
 		}
@@ -246,6 +282,11 @@ func (g *GrpcHandler) handleTransactions(req *pb.TxsRequest, stream pb.Gateway_N
 		return status.Error(codes.InvalidArgument, err.Error())
 	}
 
+	backpressure, err := parseBackpressurePolicy(feedType, req.GetBackpressure())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	ci := types.ClientInfo{
 		AccountID:     account.AccountID,
 		Tier:          string(account.TierName),
@@ -254,7 +295,8 @@ func (g *GrpcHandler) handleTransactions(req *pb.TxsRequest, stream pb.Gateway_N
 	}
 
 	ro := types.ReqOptions{
-		Filters: req.GetFilters(),
+		Filters:      req.GetFilters(),
+		Backpressure: backpressure,
 	}
 
 	sub, err := g.feedManager.Subscribe(feedType, types.GRPCFeed, nil, ci, ro, false)
@@ -263,17 +305,19 @@ func (g *GrpcHandler) handleTransactions(req *pb.TxsRequest, stream pb.Gateway_N
 	}
 	defer g.feedManager.Unsubscribe(sub.SubscriptionID, false, "")
 
-	clReq := &clientReq{includes: includes, expr: expr, feed: feedType}
+	clReq := &clientReq{includes: includes, expr: expr, feed: feedType, calldata: calldata}
 
 	var txsResponse []*pb.Tx
 	for notification := range sub.FeedChan {
 		processTx(clReq, notification, &txsResponse, ci.RemoteAddress, account.AccountID, feedType, g.txFromFieldIncludable)
 
 		if (len(sub.FeedChan) == 0 || len(txsResponse) == maxTxsInSingleResponse) && len(txsResponse) > 0 {
+			start := time.Now()
 			err = stream.Send(&pb.TxsReply{Tx: txsResponse})
 			if err != nil {
 				return status.Error(codes.Internal, err.Error())
 			}
+			sub.observeSend(start)
 
 			txsResponse = txsResponse[:0]
 		}
@@ -293,6 +337,11 @@ func (g *GrpcHandler) BdnBlocks(req *pb.BlocksRequest, stream pb.Gateway_BdnBloc
 
 // EthOnBlock handler for stream of changes in the EVM state when a new block is mined
 func (g *GrpcHandler) EthOnBlock(req *pb.EthOnBlockRequest, stream pb.Gateway_EthOnBlockServer, account sdnmessage.Account) error {
+	backpressure, err := parseBackpressurePolicy(types.OnBlockFeed, req.GetBackpressure())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	ci := types.ClientInfo{
 		AccountID:     account.AccountID,
 		Tier:          string(account.TierName),
@@ -300,7 +349,7 @@ func (g *GrpcHandler) EthOnBlock(req *pb.EthOnBlockRequest, stream pb.Gateway_Et
 		RemoteAddress: GetPeerAddr(stream.Context()),
 	}
 
-	sub, err := g.feedManager.Subscribe(types.OnBlockFeed, types.GRPCFeed, nil, ci, types.ReqOptions{}, false)
+	sub, err := g.feedManager.Subscribe(types.OnBlockFeed, types.GRPCFeed, nil, ci, types.ReqOptions{Backpressure: backpressure}, false)
 	if err != nil {
 		return status.Error(codes.InvalidArgument, "failed to subscribe to gRPC ethOnBlock")
 	}
@@ -331,6 +380,7 @@ func (g *GrpcHandler) EthOnBlock(req *pb.EthOnBlockRequest, stream pb.Gateway_Et
 			return status.Error(codes.Internal, "error when reading new block from gRPC ethOnBlock")
 		}
 
+		start := time.Now()
 		block := notification.(*types.EthBlockNotification)
 		sendEthOnBlockGrpcNotification := func(notification *types.OnBlockNotification) error {
 			ethOnBlockNotificationReply := notification.WithFields(includes).(*types.OnBlockNotification)
@@ -342,11 +392,30 @@ func (g *GrpcHandler) EthOnBlock(req *pb.EthOnBlockRequest, stream pb.Gateway_Et
 		if err != nil {
 			return status.Error(codes.Internal, err.Error())
 		}
+		sub.observeSend(start)
 	}
 }
 
 // TxReceipts handler for stream of all transaction receipts in each newly mined block
 func (g *GrpcHandler) TxReceipts(req *pb.TxReceiptsRequest, stream pb.Gateway_TxReceiptsServer, account sdnmessage.Account) error {
+	backpressure, err := parseBackpressurePolicy(types.TxReceiptsFeed, req.GetBackpressure())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	events, err := newEventABI(grpcABIOption(req.GetAbi()), g.abiRegistry())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("error creating ABI: %v", err))
+	}
+
+	var expr *filterExpr
+	if req.GetFilters() != "" {
+		expr, err = validateReceiptFilters(req.GetFilters())
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
 	ci := types.ClientInfo{
 		AccountID:     account.AccountID,
 		Tier:          string(account.TierName),
@@ -354,7 +423,12 @@ func (g *GrpcHandler) TxReceipts(req *pb.TxReceiptsRequest, stream pb.Gateway_Tx
 		RemoteAddress: GetPeerAddr(stream.Context()),
 	}
 
-	sub, err := g.feedManager.Subscribe(types.TxReceiptsFeed, types.GRPCFeed, nil, ci, types.ReqOptions{}, false)
+	ro := types.ReqOptions{
+		Filters:      req.GetFilters(),
+		Backpressure: backpressure,
+	}
+
+	sub, err := g.feedManager.Subscribe(types.TxReceiptsFeed, types.GRPCFeed, nil, ci, ro, false)
 	if err != nil {
 		return status.Error(codes.InvalidArgument, "failed to subscribe to gRPC txReceipts")
 	}
@@ -367,20 +441,75 @@ func (g *GrpcHandler) TxReceipts(req *pb.TxReceiptsRequest, stream pb.Gateway_Tx
 		includes = req.GetIncludes()
 	}
 
+	clReq := &clientReq{includes: includes, expr: expr, feed: types.TxReceiptsFeed, events: events}
+
 	for notification := range sub.FeedChan {
-		txReceiptsNotificationReply := notification.WithFields(includes).(*types.TxReceiptsNotification)
+		start := time.Now()
+		txReceiptsNotificationReply := filterTxReceipts(clReq, notification.(*types.TxReceiptsNotification))
 		for _, receipt := range txReceiptsNotificationReply.Receipts {
 			grpcTxReceiptsNotificationReply := generateTxReceiptReply(receipt)
 			if err := stream.Send(grpcTxReceiptsNotificationReply); err != nil {
 				return status.Error(codes.Internal, err.Error())
 			}
 		}
+		sub.observeSend(start)
 	}
 
 	return status.Error(codes.Internal, "error when reading new block from gRPC txReceipts")
 }
 
+// TransactionStatus handler for stream of the status updates of the transactions submitted by the account
+func (g *GrpcHandler) TransactionStatus(req *pb.TransactionStatusRequest, stream pb.Gateway_TransactionStatusServer, account sdnmessage.Account) error {
+	if err := validateFeed(types.TransactionStatusFeed, account.TransactionStateFeed, nil, nil); err != nil {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	backpressure, err := parseBackpressurePolicy(types.TransactionStatusFeed, req.GetBackpressure())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ci := types.ClientInfo{
+		AccountID:     account.AccountID,
+		Tier:          string(account.TierName),
+		MetaInfo:      types.SDKMetaFromContext(stream.Context()),
+		RemoteAddress: GetPeerAddr(stream.Context()),
+	}
+
+	ro := types.ReqOptions{
+		Backpressure: backpressure,
+	}
+
+	sub, err := g.feedManager.Subscribe(types.TransactionStatusFeed, types.GRPCFeed, nil, ci, ro, false)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "failed to subscribe to gRPC transactionStatus")
+	}
+	defer g.feedManager.Unsubscribe(sub.SubscriptionID, false, "")
+
+	for notification := range sub.FeedChan {
+		start := time.Now()
+		txStatus := notification.(*types.TransactionStatusNotification)
+		reply := &pb.TransactionStatusReply{
+			TransactionHash: txStatus.TransactionHash,
+			Status:          string(txStatus.Status),
+			BlockNumber:     txStatus.BlockNumber,
+			ReplacedBy:      txStatus.ReplacedBy,
+		}
+		if err := stream.Send(reply); err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		sub.observeSend(start)
+	}
+
+	return status.Error(codes.Internal, "error when reading transaction status from gRPC transactionStatus")
+}
+
 func (g *GrpcHandler) handleBlocks(req *pb.BlocksRequest, stream pb.Gateway_BdnBlocksServer, feedType types.FeedType, account sdnmessage.Account) error {
+	backpressure, err := parseBackpressurePolicy(feedType, req.GetBackpressure())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	ci := types.ClientInfo{
 		AccountID:     account.AccountID,
 		Tier:          string(account.TierName),
@@ -388,7 +517,7 @@ func (g *GrpcHandler) handleBlocks(req *pb.BlocksRequest, stream pb.Gateway_BdnB
 		RemoteAddress: GetPeerAddr(stream.Context()),
 	}
 
-	sub, err := g.feedManager.Subscribe(feedType, types.GRPCFeed, nil, ci, types.ReqOptions{}, false)
+	sub, err := g.feedManager.Subscribe(feedType, types.GRPCFeed, nil, ci, types.ReqOptions{Backpressure: backpressure}, false)
 	if err != nil {
 		return status.Error(codes.InvalidArgument, fmt.Sprintf("failed to subscribe to gRPC %v feed", feedType))
 	}
@@ -408,6 +537,7 @@ func (g *GrpcHandler) handleBlocks(req *pb.BlocksRequest, stream pb.Gateway_BdnB
 				return status.Error(codes.Internal, "error when reading new notification for gRPC bdnBlocks")
 			}
 
+			start := time.Now()
 			blocks := notification.WithFields(includes).(*types.EthBlockNotification)
 			blocksReply := g.generateBlockReply(blocks)
 			blocksReply.SubscriptionID = sub.SubscriptionID
@@ -416,6 +546,7 @@ func (g *GrpcHandler) handleBlocks(req *pb.BlocksRequest, stream pb.Gateway_BdnB
 			if err != nil {
 				return status.Error(codes.Internal, err.Error())
 			}
+			sub.observeSend(start)
 		}
 	}
 }
@@ -437,7 +568,17 @@ func interfaceToString(value interface{}) string {
 }
 
 func interfaceToStringArray(value interface{}) []string {
-	if stringArray, ok := value.([]string); ok {
+	switch array := value.(type) {
+	case []string:
+		return array
+	case []interface{}:
+		// arrays decoded from JSON, e.g. the topics of a log fetched from the node
+		stringArray := make([]string, 0, len(array))
+		for _, v := range array {
+			if s, ok := v.(string); ok {
+				stringArray = append(stringArray, s)
+			}
+		}
 		return stringArray
 	}
 	return []string{}
@@ -11,7 +11,6 @@ import (
 
 	"github.com/bloXroute-Labs/gateway/v2/connections"
 	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
-	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/sourcegraph/jsonrpc2"
 
 	"github.com/bloXroute-Labs/gateway/v2"
@@ -27,8 +26,11 @@ import (
 	"github.com/bloXroute-Labs/gateway/v2/test/fixtures"
 	"github.com/bloXroute-Labs/gateway/v2/types"
 	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -70,6 +72,13 @@ var accountIDToAccountModel = map[types.AccountID]sdnmessage.Account{
 				AvailableFields: []string{"all"},
 			},
 		},
+		TransactionStateFeed: sdnmessage.BDNFeedService{
+			ExpireDate: "2999-12-31",
+			Feed: sdnmessage.FeedProperties{
+				AllowFiltering:  true,
+				AvailableFields: []string{"all"},
+			},
+		},
 	},
 }
 
@@ -136,7 +145,7 @@ func TestClientHandler(t *testing.T) {
 	wsURLs := []string{fmt.Sprintf("ws://%s/ws", url), fmt.Sprintf("ws://%s/", url)}
 
 	gwAccount, _ := getMockCustomerAccountModel("gw")
-	cfg := config.Bx{WebsocketPort: 28332, ManageWSServer: true, WebsocketTLSEnabled: false}
+	cfg := config.Bx{WebsocketPort: 28332, ManageWSServer: true, WebsocketTLSEnabled: false, BlockSanctionedTxs: true}
 
 	blockchainPeers, blockchainPeersInfo := test.GenerateBlockchainPeersInfo(3)
 
@@ -217,11 +226,13 @@ func TestClientHandler(t *testing.T) {
 			handleBlxrTxRequestWithNextValidator(t, ws)
 			handleBlxrTxRequestRLPTx(t, ws)
 			handleBlxrTxWithWrongChainID(t, ws)
+			handleBlxrTxSanctioned(t, ws)
 			handleNonBloxrouteRPCMethods(t, fm, ws, blockchainPeers)
 			handleNonBloxrouteSendTxMethod(t, fm, ws, blockchainPeers)
 			handleSubscribe(t, fm, ws)
 			handleEthSubscribe(t, fm, ws, blockchainPeers)
 			handleTxReceiptsSubscribe(t, fm, ws)
+			handleTxStatusNotification(t, fm, ws)
 			handleInvalidSubscribe(t, ws)
 			testWSShutdown(t, fm, ws, blockchainPeers)
 		})
@@ -526,6 +537,39 @@ func handleTxReceiptsSubscribe(t *testing.T, fm *FeedManager, ws *websocket.Conn
 	handlePingRequest(t, ws)
 }
 
+func handleTxStatusNotification(t *testing.T, fm *FeedManager, ws *websocket.Conn) {
+	unsubscribeFilter, subscriptionID := assertSubscribe(t, ws, fm, `{"id": "1", "method": "subscribe", "params": ["transactionStatus", {"include": []}]}`)
+
+	// the status of a transaction of another account is not delivered
+	otherStatus := &types.TransactionStatusNotification{TransactionHash: "0x01", Status: types.TxPool}
+	otherStatus.AccountIDs = []types.AccountID{"a"}
+	fm.feed <- otherStatus
+	status := &types.TransactionStatusNotification{TransactionHash: "0x02", Status: types.Mined, BlockNumber: 10}
+	status.AccountIDs = []types.AccountID{"gw"}
+	fm.feed <- status
+
+	require.NoError(t, ws.SetReadDeadline(time.Now().Add(time.Second)))
+	_, message, err := ws.ReadMessage()
+	require.NoError(t, err)
+	require.NoError(t, ws.SetReadDeadline(time.Time{}))
+	var notification struct {
+		Params struct {
+			Subscription string                              `json:"subscription"`
+			Result       types.TransactionStatusNotification `json:"result"`
+		} `json:"params"`
+	}
+	require.NoError(t, json.Unmarshal(message, &notification))
+	assert.Equal(t, subscriptionID, notification.Params.Subscription)
+	assert.Equal(t, "0x02", notification.Params.Result.TransactionHash)
+	assert.Equal(t, types.Mined, notification.Params.Result.Status)
+	assert.Equal(t, uint64(10), notification.Params.Result.BlockNumber)
+
+	writeMsgToWsAndReadResponse(t, ws, []byte(unsubscribeFilter), nil)
+	time.Sleep(time.Millisecond)
+	assert.False(t, fm.SubscriptionExists(subscriptionID))
+	handlePingRequest(t, ws)
+}
+
 func handleTxReceiptsNotification(t *testing.T, fm *FeedManager, ws *websocket.Conn, blockchainPeers []types.NodeEndpoint) {
 	bridge := blockchain.NewBxBridge(eth.Converter{}, false)
 	wsProvider, ok := fm.nodeWSManager.Provider(&blockchainPeers[0])
@@ -921,6 +965,26 @@ func handleBlxrTxWithWrongChainID(t *testing.T, ws *websocket.Conn) {
 	assert.NotNil(t, clientRes.Error)
 }
 
+func handleBlxrTxSanctioned(t *testing.T, ws *websocket.Conn) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sanctioned := common.HexToAddress("0x8576acc5c05d6ce88f4e49bf65bdf0c62f91353c")
+	tx, err := ethtypes.SignNewTx(key, ethtypes.NewLondonSigner(big.NewInt(1)), &ethtypes.DynamicFeeTx{ChainID: big.NewInt(1), To: &sanctioned, Gas: 21000})
+	require.NoError(t, err)
+	rawTx, err := tx.MarshalBinary()
+	require.NoError(t, err)
+
+	reqPayload := fmt.Sprintf(`{"id": "1", "method": "blxr_tx", "params": {"transaction": "%x"}}`, rawTx)
+	msg := writeMsgToWsAndReadResponse(t, ws, []byte(reqPayload), nil)
+	var res struct {
+		Error *jsonrpc2.Error `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(msg, &res))
+	require.NotNil(t, res.Error)
+	assert.Equal(t, int64(jsonrpc.Sanctioned), res.Error.Code)
+	assert.Contains(t, string(*res.Error.Data), "sanctioned_recipient")
+}
+
 func handleBlxrTxsRequestLegacyTx(t *testing.T, ws *websocket.Conn) {
 	reqPayload := fmt.Sprintf(`{"id": "1", "method": "blxr_batch_tx", "params": {"transactions": ["%s"]}}`, fixtures.LegacyTransaction)
 	msg := writeMsgToWsAndReadResponse(t, ws, []byte(reqPayload), nil)
@@ -1050,69 +1114,3 @@ func assertEthSubscribe(t *testing.T, ws *websocket.Conn, fm *FeedManager, filte
 		subscriptionID,
 	), subscriptionID
 }
-
-func TestisFiltersSupportedByTxType(t *testing.T) {
-	tests := []struct {
-		name     string
-		txType   uint8
-		filters  []string
-		expected bool
-	}{
-		{
-			name:     "DynamicFeeTxType with gas_price filter",
-			txType:   ethtypes.DynamicFeeTxType,
-			filters:  []string{"gas_price"},
-			expected: false,
-		},
-		{
-			name:     "DynamicFeeTxType with gas_price and max_fee_per_gas filters",
-			txType:   ethtypes.DynamicFeeTxType,
-			filters:  []string{"gas_price", "max_fee_per_gas"},
-			expected: true,
-		},
-		{
-			name:     "DynamicFeeTxType with gas_price and max_priority_fee_per_gas filters",
-			txType:   ethtypes.DynamicFeeTxType,
-			filters:  []string{"gas_price", "max_priority_fee_per_gas"},
-			expected: true,
-		},
-		{
-			name:     "DynamicFeeTxType with gas_price and max_priority_fee_per_gas filters",
-			txType:   ethtypes.DynamicFeeTxType,
-			filters:  []string{"gas_price", "max_fee_per_gas", "max_priority_fee_per_gas"},
-			expected: true,
-		},
-		{
-			name:     "Non-DynamicFeeTxType with max_fee_per_gas filter",
-			txType:   ethtypes.LegacyTxType,
-			filters:  []string{"max_fee_per_gas"},
-			expected: false,
-		},
-		{
-			name:     "Non-DynamicFeeTxType with max_priority_fee_per_gas filter",
-			txType:   ethtypes.LegacyTxType,
-			filters:  []string{"max_priority_fee_per_gas"},
-			expected: false,
-		},
-		{
-			name:     "Non-DynamicFeeTxType with no filters",
-			txType:   ethtypes.LegacyTxType,
-			filters:  []string{},
-			expected: true,
-		},
-		{
-			name:     "Non-DynamicFeeTxType with gasPrice",
-			txType:   ethtypes.LegacyTxType,
-			filters:  []string{"gas_price"},
-			expected: true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := isFiltersSupportedByTxType(tt.txType, tt.filters); got != tt.expected {
-				t.Errorf("isFiltersSupportedByTxType() = %v, expected %v", got, tt.expected)
-			}
-		})
-	}
-}
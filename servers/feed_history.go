@@ -0,0 +1,350 @@
+package servers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const (
+	feedHistoryDirName         = "feeds"
+	currentFeedHistoryFileName = "current.log"
+	// previousFeedHistoryFileName holds the entries of the current log before its last rotation
+	previousFeedHistoryFileName = "previous.log"
+	// maxFeedHistoryEntrySize is the largest line read from a history file, large blocks are a few MB
+	maxFeedHistoryEntrySize = 64 * 1024 * 1024
+	// maxFeedHistoryPending is the number of notifications waiting to be stored beyond which new ones are dropped
+	maxFeedHistoryPending = 10000
+)
+
+// historyFeeds are the feeds whose notifications can be replayed with resume_from
+var historyFeeds = []types.FeedType{types.NewBlocksFeed, types.BDNBlocksFeed, types.TxReceiptsFeed, types.TransactionStatusFeed}
+
+// blockHistoryFeeds are the history feeds recorded from the blocks, the gateway keeps publishing the blocks without
+// subscriptions to record them
+var blockHistoryFeeds = []types.FeedType{types.NewBlocksFeed, types.BDNBlocksFeed, types.TxReceiptsFeed}
+
+type feedHistoryEntry struct {
+	Seq          uint64          `json:"seq"`
+	Notification json.RawMessage `json:"notification"`
+}
+
+// feedHistory is a bounded on-disk ring log of the notifications of a feed. Notifications are numbered with
+// increasing sequence numbers, which survive restarts. Once the current log holds size entries it replaces
+// the previous one, so at least the last size notifications can be replayed.
+// Notifications are numbered by append and stored by a background writer, so the feed is not slowed down by the disk
+type feedHistory struct {
+	feed types.FeedType
+	dir  string
+	size int
+
+	// lock guards the sequence number and the notifications not stored yet
+	lock    sync.Mutex
+	cond    *sync.Cond
+	lastSeq uint64
+	pending []types.SequencedNotification
+	writing []types.SequencedNotification
+	closed  bool
+	done    chan struct{}
+
+	// fileLock guards the log files, replays hold it while reading them
+	fileLock sync.RWMutex
+	current  *os.File
+	count    int
+}
+
+func newFeedHistory(dataDir string, feed types.FeedType, size int) (*feedHistory, error) {
+	h := &feedHistory{
+		feed: feed,
+		dir:  path.Join(dataDir, feedHistoryDirName, string(feed)),
+		size: size,
+		done: make(chan struct{}),
+	}
+	h.cond = sync.NewCond(&h.lock)
+	if err := os.MkdirAll(h.dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	previous, err := readFeedHistoryFile(path.Join(h.dir, previousFeedHistoryFileName))
+	if err != nil {
+		return nil, err
+	}
+	if err := truncateFeedHistoryFile(path.Join(h.dir, currentFeedHistoryFileName)); err != nil {
+		return nil, err
+	}
+	current, err := readFeedHistoryFile(path.Join(h.dir, currentFeedHistoryFileName))
+	if err != nil {
+		return nil, err
+	}
+	if len(previous) > 0 {
+		h.lastSeq = previous[len(previous)-1].Seq
+	}
+	if len(current) > 0 {
+		h.lastSeq = current[len(current)-1].Seq
+	}
+	h.count = len(current)
+
+	h.current, err = os.OpenFile(path.Join(h.dir, currentFeedHistoryFileName), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	go h.write()
+	return h, nil
+}
+
+// append assigns the next sequence number to the notification and queues it to be stored. A notification is not
+// stored if the writer is too far behind, it is then missing from the replays like the rotated ones
+func (h *feedHistory) append(notification types.SequencedNotification) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.lastSeq++
+	notification.SetSeq(h.lastSeq)
+
+	if len(h.pending) >= maxFeedHistoryPending {
+		return fmt.Errorf("%v history writer is behind, notification %v is not stored", h.feed, h.lastSeq)
+	}
+	h.pending = append(h.pending, notification)
+	h.cond.Broadcast()
+	return nil
+}
+
+// write stores the queued notifications until the history is closed
+func (h *feedHistory) write() {
+	defer close(h.done)
+
+	for {
+		h.lock.Lock()
+		for len(h.pending) == 0 && !h.closed {
+			h.cond.Wait()
+		}
+		if len(h.pending) == 0 {
+			h.lock.Unlock()
+			return
+		}
+		h.writing, h.pending = h.pending, nil
+		batch := h.writing
+		h.lock.Unlock()
+
+		h.fileLock.Lock()
+		for _, notification := range batch {
+			if err := h.store(notification); err != nil {
+				log.Errorf("failed to add notification %v to history: %v", notification.GetHash(), err)
+			}
+		}
+		h.fileLock.Unlock()
+
+		h.lock.Lock()
+		h.writing = nil
+		h.cond.Broadcast()
+		h.lock.Unlock()
+	}
+}
+
+// store writes a notification to the current log, should be called with h.fileLock held
+func (h *feedHistory) store(notification types.SequencedNotification) error {
+	content, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %v notification %v: %v", h.feed, notification.Seq(), err)
+	}
+	line, err := json.Marshal(feedHistoryEntry{Seq: notification.Seq(), Notification: content})
+	if err != nil {
+		return fmt.Errorf("failed to marshal %v notification %v: %v", h.feed, notification.Seq(), err)
+	}
+
+	if h.count >= h.size {
+		if err = h.rotate(); err != nil {
+			return fmt.Errorf("failed to rotate %v history: %v", h.feed, err)
+		}
+	}
+	if _, err = h.current.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to store %v notification %v: %v", h.feed, notification.Seq(), err)
+	}
+	h.count++
+	return nil
+}
+
+func (h *feedHistory) rotate() error {
+	if err := h.current.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(path.Join(h.dir, currentFeedHistoryFileName), path.Join(h.dir, previousFeedHistoryFileName)); err != nil {
+		return err
+	}
+	current, err := os.OpenFile(path.Join(h.dir, currentFeedHistoryFileName), os.O_CREATE|os.O_TRUNC|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	h.current = current
+	h.count = 0
+	return nil
+}
+
+// since returns the notifications with a sequence number higher than seq, oldest first. The stored ones are read
+// without blocking append, followed by the ones the writer has not stored yet
+func (h *feedHistory) since(seq uint64) ([]types.SequencedNotification, error) {
+	h.lock.Lock()
+	lastSeq := h.lastSeq
+	h.lock.Unlock()
+	if seq >= lastSeq {
+		return nil, nil
+	}
+
+	h.fileLock.RLock()
+	var entries []feedHistoryEntry
+	for _, name := range []string{previousFeedHistoryFileName, currentFeedHistoryFileName} {
+		fileEntries, err := readFeedHistoryFile(path.Join(h.dir, name))
+		if err != nil {
+			h.fileLock.RUnlock()
+			return nil, err
+		}
+		entries = append(entries, fileEntries...)
+	}
+	// the writer can't store the queued notifications while the files are read, so none is missed
+	h.lock.Lock()
+	queued := make([]types.SequencedNotification, 0, len(h.writing)+len(h.pending))
+	queued = append(append(queued, h.writing...), h.pending...)
+	h.lock.Unlock()
+	h.fileLock.RUnlock()
+
+	var notifications []types.SequencedNotification
+	for _, entry := range entries {
+		if entry.Seq <= seq {
+			continue
+		}
+		notification, err := decodeFeedHistoryNotification(h.feed, entry.Notification)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %v notification %v: %v", h.feed, entry.Seq, err)
+		}
+		notification.SetSeq(entry.Seq)
+		notifications = append(notifications, notification)
+		seq = entry.Seq
+	}
+	for _, notification := range queued {
+		if notification.Seq() > seq {
+			notifications = append(notifications, notification)
+		}
+	}
+	return notifications, nil
+}
+
+// lastSequence returns the sequence number of the last notification appended
+func (h *feedHistory) lastSequence() uint64 {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.lastSeq
+}
+
+// flush waits until the queued notifications are stored
+func (h *feedHistory) flush() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	for len(h.pending) > 0 || len(h.writing) > 0 {
+		h.cond.Wait()
+	}
+}
+
+// close stores the queued notifications and closes the current log
+func (h *feedHistory) close() error {
+	h.lock.Lock()
+	h.closed = true
+	h.cond.Broadcast()
+	h.lock.Unlock()
+	<-h.done
+
+	h.fileLock.Lock()
+	defer h.fileLock.Unlock()
+	return h.current.Close()
+}
+
+// readFeedHistoryFile reads the entries of a history file, a missing file has no entries. A line that can't be
+// parsed is skipped, it is the last one if the gateway stopped while writing it
+func readFeedHistoryFile(name string) ([]feedHistoryEntry, error) {
+	f, err := os.Open(name)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []feedHistoryEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxFeedHistoryEntrySize)
+	for scanner.Scan() {
+		var entry feedHistoryEntry
+		if err = json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %v: %v", name, err)
+	}
+	return entries, nil
+}
+
+// truncateFeedHistoryFile removes the partially written last line of a history file, which would otherwise
+// corrupt the next entry appended to it
+func truncateFeedHistoryFile(name string) error {
+	content, err := os.ReadFile(name)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(content) == 0 || content[len(content)-1] == '\n' {
+		return nil
+	}
+	return os.Truncate(name, int64(bytes.LastIndexByte(content, '\n')+1))
+}
+
+func decodeFeedHistoryNotification(feed types.FeedType, content json.RawMessage) (types.SequencedNotification, error) {
+	switch feed {
+	case types.NewBlocksFeed, types.BDNBlocksFeed:
+		block := &types.EthBlockNotification{}
+		if err := json.Unmarshal(content, block); err != nil {
+			return nil, err
+		}
+		if block.Header != nil {
+			number, err := hexutil.DecodeUint64(block.Header.Number)
+			if err != nil {
+				return nil, fmt.Errorf("invalid block number %v: %v", block.Header.Number, err)
+			}
+			block.Header.UpdateNumber(number)
+		}
+		block.SetNotificationType(feed)
+		return block, nil
+	case types.TxReceiptsFeed:
+		var receipts []*types.TxReceipt
+		if err := json.Unmarshal(content, &receipts); err != nil {
+			return nil, err
+		}
+		return types.NewTxReceiptsNotification(receipts), nil
+	case types.TransactionStatusFeed:
+		status := &types.TransactionStatusNotification{}
+		if err := json.Unmarshal(content, status); err != nil {
+			return nil, err
+		}
+		return status, nil
+	}
+	return nil, fmt.Errorf("%v feed has no history", feed)
+}
+
+// notificationSeq returns the sequence number of a notification, 0 if its feed has no history
+func notificationSeq(notification types.Notification) uint64 {
+	if sequenced, ok := notification.(types.SequencedNotification); ok {
+		return sequenced.Seq()
+	}
+	return 0
+}
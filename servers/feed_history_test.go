@@ -0,0 +1,148 @@
+package servers
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testStatusNotification(hash string) *types.TransactionStatusNotification {
+	return &types.TransactionStatusNotification{TransactionHash: hash, Status: types.Mined}
+}
+
+func TestFeedHistoryReplay(t *testing.T) {
+	datadir := t.TempDir()
+	history, err := newFeedHistory(datadir, types.TransactionStatusFeed, 2)
+	require.NoError(t, err)
+
+	for i, hash := range []string{"0x01", "0x02", "0x03", "0x04", "0x05"} {
+		n := testStatusNotification(hash)
+		require.NoError(t, history.append(n))
+		assert.Equal(t, uint64(i+1), n.Seq())
+	}
+
+	// the current log is rotated every 2 entries, 0x01 and 0x02 are gone once stored
+	history.flush()
+	notifications, err := history.since(0)
+	require.NoError(t, err)
+	require.Len(t, notifications, 3)
+	assert.Equal(t, uint64(3), notifications[0].Seq())
+	assert.Equal(t, "0x03", notifications[0].GetHash())
+	assert.Equal(t, uint64(5), notifications[2].Seq())
+
+	notifications, err = history.since(4)
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, "0x05", notifications[0].GetHash())
+
+	notifications, err = history.since(5)
+	require.NoError(t, err)
+	assert.Empty(t, notifications)
+	require.NoError(t, history.close())
+
+	// the sequence continues after a restart, a partially written entry is ignored
+	f, err := os.OpenFile(path.Join(datadir, feedHistoryDirName, string(types.TransactionStatusFeed), currentFeedHistoryFileName), os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"seq":6,"notifica`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	history, err = newFeedHistory(datadir, types.TransactionStatusFeed, 2)
+	require.NoError(t, err)
+	defer history.close()
+
+	n := testStatusNotification("0x06")
+	require.NoError(t, history.append(n))
+	assert.Equal(t, uint64(6), n.Seq())
+
+	// a notification not stored yet is replayed as well
+	notifications, err = history.since(4)
+	require.NoError(t, err)
+	require.Len(t, notifications, 2)
+	assert.Equal(t, "0x06", notifications[1].GetHash())
+	history.flush()
+	notifications, err = history.since(4)
+	require.NoError(t, err)
+	require.Len(t, notifications, 2)
+	assert.Equal(t, "0x06", notifications[1].GetHash())
+}
+
+func TestFeedHistoryAccounts(t *testing.T) {
+	history, err := newFeedHistory(t.TempDir(), types.TransactionStatusFeed, 10)
+	require.NoError(t, err)
+	defer history.close()
+
+	n := testStatusNotification("0x01")
+	n.AccountIDs = []types.AccountID{"a"}
+	require.NoError(t, history.append(n))
+	history.flush()
+
+	notifications, err := history.since(0)
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+	assert.True(t, notificationForAccount(notifications[0], "a"))
+	assert.False(t, notificationForAccount(notifications[0], "b"))
+	assert.True(t, notificationForAccount(&types.OnBlockNotification{}, "b"))
+}
+
+func TestFeedHistoryBlocks(t *testing.T) {
+	history, err := newFeedHistory(t.TempDir(), types.NewBlocksFeed, 10)
+	require.NoError(t, err)
+	defer history.close()
+
+	hash := ethcommon.HexToHash("0x1234")
+	block := &types.EthBlockNotification{
+		BlockHash:    &hash,
+		Header:       &types.Header{Number: "0x10"},
+		Transactions: []map[string]interface{}{{"hash": "0x01"}},
+	}
+	block.SetNotificationType(types.NewBlocksFeed)
+	require.NoError(t, history.append(block))
+
+	receipts := types.NewTxReceiptsNotification([]*types.TxReceipt{{TransactionHash: "0x01", Status: "0x1"}})
+	receiptsHistory, err := newFeedHistory(t.TempDir(), types.TxReceiptsFeed, 10)
+	require.NoError(t, err)
+	defer receiptsHistory.close()
+	require.NoError(t, receiptsHistory.append(receipts))
+
+	// the replayed notifications are decoded from the stored ones
+	history.flush()
+	receiptsHistory.flush()
+	notifications, err := history.since(0)
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+	replayed := notifications[0].(*types.EthBlockNotification)
+	assert.Equal(t, hash, *replayed.BlockHash)
+	assert.Equal(t, uint64(16), replayed.Header.GetNumber())
+	assert.Equal(t, types.NewBlocksFeed, replayed.NotificationType())
+	assert.Equal(t, "0x01", replayed.Transactions[0]["hash"])
+	assert.Equal(t, uint64(1), notificationSeq(replayed))
+
+	notifications, err = receiptsHistory.since(0)
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, receipts.Receipts, notifications[0].(*types.TxReceiptsNotification).Receipts)
+
+	assert.Equal(t, uint64(0), notificationSeq(&types.OnBlockNotification{}))
+}
+
+func TestFeedHistoryResumeFrom(t *testing.T) {
+	history, err := newFeedHistory(t.TempDir(), types.TransactionStatusFeed, 10)
+	require.NoError(t, err)
+	defer history.close()
+	fm := &FeedManager{history: map[types.FeedType]*feedHistory{types.TransactionStatusFeed: history}}
+
+	require.NoError(t, history.append(testStatusNotification("0x01")))
+	require.NoError(t, history.append(testStatusNotification("0x02")))
+
+	assert.NoError(t, fm.validateResumeFrom(types.TransactionStatusFeed, 0))
+	assert.NoError(t, fm.validateResumeFrom(types.TransactionStatusFeed, 2))
+	// the live notifications up to a sequence number ahead of the history would be dropped
+	assert.Error(t, fm.validateResumeFrom(types.TransactionStatusFeed, 3))
+	assert.Error(t, fm.validateResumeFrom(types.NewTxsFeed, 0))
+}
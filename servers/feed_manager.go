@@ -17,6 +17,7 @@ import (
 	pb "github.com/bloXroute-Labs/gateway/v2/protobuf"
 	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
 	"github.com/bloXroute-Labs/gateway/v2/services"
+	"github.com/bloXroute-Labs/gateway/v2/services/metrics"
 	"github.com/bloXroute-Labs/gateway/v2/services/statistics"
 	"github.com/bloXroute-Labs/gateway/v2/types"
 	"github.com/bloXroute-Labs/gateway/v2/utils/orderedmap"
@@ -36,7 +37,7 @@ type ClientSubscription struct {
 	connection         *jsonrpc2.Conn
 	network            types.NetworkNum
 	timeOpenedFeed     time.Time
-	messagesSent       uint64
+	stats              *subscriptionStats
 	errMsgChan         chan string
 }
 
@@ -46,6 +47,7 @@ type ClientSubscriptionHandlingInfo struct {
 	FeedChan           chan types.Notification
 	ErrMsgChan         chan string
 	PermissionRespChan chan *sdnmessage.SubscriptionPermissionMessage
+	stats              *subscriptionStats
 }
 
 // PendingNextValidatorTxInfo holds info needed to reevaluate next validator tx when next block published
@@ -77,6 +79,15 @@ type FeedManager struct {
 	validatorStatusMap                  *syncmap.SyncMap[string, bool]
 	pendingBSCNextValidatorTxHashToInfo map[string]PendingNextValidatorTxInfo
 	pendingBSCNextValidatorTxsMapLock   sync.Mutex
+	abiRegistry                         *abiRegistry
+	history                             map[types.FeedType]*feedHistory
+	pendingNonces                       *services.PendingNonceTracker
+	txReplacements                      *services.TxReplacementManager
+	conditionalTxs                      *services.ConditionalTxStore
+	txStatuses                          *services.TxStatusTracker
+	mempoolSnapshots                    *MempoolSnapshots
+	gasOracle                           *services.GasOracle
+	bundleTracker                       *services.BundleTracker
 
 	context context.Context
 	cancel  context.CancelFunc
@@ -118,6 +129,33 @@ func NewFeedManager(parent context.Context, node connections.BxListener, wsFeedC
 		log:                                 logger,
 		pendingBSCNextValidatorTxHashToInfo: make(map[string]PendingNextValidatorTxInfo),
 	}
+
+	if cfg.Env != nil && cfg.DataDir != "" {
+		registry, err := loadABIRegistry(cfg.DataDir)
+		if err != nil {
+			logger.Errorf("failed to load contract ABIs, subscriptions can only use inline ABIs: %v", err)
+		} else {
+			newServer.abiRegistry = registry
+		}
+	}
+
+	if cfg.Env != nil && cfg.DataDir != "" && cfg.FeedHistorySize > 0 {
+		newServer.history = make(map[types.FeedType]*feedHistory)
+		for _, name := range strings.Split(cfg.FeedHistoryFeeds, ",") {
+			feed := types.FeedType(strings.TrimSpace(name))
+			if !types.Exists(feed, historyFeeds) {
+				logger.Errorf("%v feed has no history, valid feeds are %v", feed, historyFeeds)
+				continue
+			}
+			history, err := newFeedHistory(cfg.DataDir, feed, cfg.FeedHistorySize)
+			if err != nil {
+				logger.Errorf("failed to load %v history, %v notifications can't be replayed: %v", feed, feed, err)
+				continue
+			}
+			newServer.history[feed] = history
+		}
+	}
+
 	return newServer
 }
 
@@ -156,6 +194,9 @@ func (f *FeedManager) Subscribe(feedName types.FeedType, feedConnectionType type
 	conn *jsonrpc2.Conn, ci types.ClientInfo, ro types.ReqOptions, ethSubscribe bool) (*ClientSubscriptionHandlingInfo, error) {
 
 	id := f.subscriptionServices.GenerateSubscriptionID(ethSubscribe)
+	if ro.Backpressure == "" {
+		ro.Backpressure = types.BackpressureDisconnect
+	}
 	clientSubscription := ClientSubscription{
 		feed:               make(chan types.Notification, bxgateway.BxNotificationChannelSize),
 		feedType:           feedName,
@@ -163,6 +204,7 @@ func (f *FeedManager) Subscribe(feedName types.FeedType, feedConnectionType type
 		connection:         conn,
 		network:            f.networkNum,
 		timeOpenedFeed:     time.Now(),
+		stats:              &subscriptionStats{},
 		errMsgChan:         make(chan string, 1),
 		ClientInfo:         ci,
 		ReqOptions:         ro,
@@ -193,14 +235,16 @@ func (f *FeedManager) Subscribe(feedName types.FeedType, feedConnectionType type
 	f.lock.Lock()
 	f.idToClientSubscription[id] = clientSubscription
 	f.lock.Unlock()
+	metrics.FeedSubscribers.WithLabelValues(string(feedName)).Inc()
 
-	f.log.Infof("%v subscribed to %v id %v with includes [%v] and filter [%v]", ci.RemoteAddress, feedName, id, ro.Includes, ro.Filters)
+	f.log.Infof("%v subscribed to %v id %v with includes [%v], filter [%v] and backpressure %v", ci.RemoteAddress, feedName, id, ro.Includes, ro.Filters, ro.Backpressure)
 
 	handlingInfo := ClientSubscriptionHandlingInfo{
 		SubscriptionID:     id,
 		FeedChan:           clientSubscription.feed,
 		ErrMsgChan:         clientSubscription.errMsgChan,
 		PermissionRespChan: permissionRespChannel,
+		stats:              clientSubscription.stats,
 	}
 	return &handlingInfo, nil
 }
@@ -254,6 +298,7 @@ func (f *FeedManager) Unsubscribe(subscriptionID string, closeClientConnection b
 		sdnmessage.AccountTier(clientSub.Tier))
 	close(clientSub.feed)
 	delete(f.idToClientSubscription, subscriptionID)
+	metrics.FeedSubscribers.WithLabelValues(string(clientSub.feedType)).Dec()
 	if closeClientConnection && clientSub.connection != nil {
 		// TODO: need to unsubscribe all other subscriptions on this connection.
 		err := clientSub.connection.Close()
@@ -296,6 +341,11 @@ func (f *FeedManager) run(ctx context.Context) {
 		select {
 		case <-ctx.Done():
 			f.log.Infof("feedManager stopped for network %v", f.networkNum)
+			for feed, history := range f.history {
+				if err := history.close(); err != nil {
+					f.log.Errorf("failed to close %v history: %v", feed, err)
+				}
+			}
 			return
 		case <-dailyTicker.C:
 			// checks every 24 hours for all existing user subscription, if account expired close the subscription.
@@ -339,18 +389,19 @@ func (f *FeedManager) run(ctx context.Context) {
 				f.log.Errorf("can't pull from ws feed channel. Terminating")
 				break
 			}
+			if history, ok := f.history[notification.NotificationType()]; ok {
+				if sequenced, ok := notification.(types.SequencedNotification); ok {
+					if err := history.append(sequenced); err != nil {
+						f.log.Errorf("failed to add notification %v to history: %v", notification.GetHash(), err)
+					}
+				}
+			}
+			fanoutStart := time.Now()
 			f.lock.RLock()
 			for uid, clientSub := range f.idToClientSubscription {
-				if (clientSub.feedConnectionType == types.WebSocketFeed || clientSub.feedConnectionType == types.GRPCFeed) && clientSub.feedType == notification.NotificationType() {
-					select {
-					case clientSub.feed <- notification:
-						// Offer: I took this out as we are locking the map in read and can't write.
-						// also, do we need to update the map after we update the counter?
-						// if entry, ok := f.idToClientSubscription[uid]; ok {
-						//	entry.messagesSent++
-						//	f.idToClientSubscription[uid] = entry
-						// }
-					default:
+				if (clientSub.feedConnectionType == types.WebSocketFeed || clientSub.feedConnectionType == types.GRPCFeed) && clientSub.feedType == notification.NotificationType() &&
+					notificationForAccount(notification, clientSub.AccountID) {
+					if !f.enqueue(&clientSub, notification) {
 						f.log.Errorf("can't send %v to channel %v without blocking. Ignored hash %v and unsubscribing", clientSub.feedType, uid, notification.GetHash())
 						go func(subscriptionID string) {
 							// running as go-routine since we are holding the lock. Closing the connection since we can't write
@@ -363,6 +414,7 @@ func (f *FeedManager) run(ctx context.Context) {
 				}
 			}
 			f.lock.RUnlock()
+			metrics.FeedFanoutDuration.WithLabelValues(string(notification.NotificationType())).Observe(time.Since(fanoutStart).Seconds())
 		}
 	}
 }
@@ -390,8 +442,40 @@ func (f *FeedManager) SubscriptionTypeExists(feedType types.FeedType) bool {
 	return false
 }
 
+// notificationForAccount checks if a notification is delivered to the subscriptions of the account
+func notificationForAccount(notification types.Notification, accountID types.AccountID) bool {
+	accountNotification, ok := notification.(types.AccountNotification)
+	return !ok || accountNotification.ForAccount(accountID)
+}
+
+// HistoryEnabled checks if the notifications of the feed are kept to be replayed
+func (f *FeedManager) HistoryEnabled(feedType types.FeedType) bool {
+	_, ok := f.history[feedType]
+	return ok
+}
+
+// validateResumeFrom checks that the notifications following resumeFrom can be replayed from the history of the feed.
+// A sequence number ahead of the history is rejected, the live notifications numbered up to it would be skipped
+func (f *FeedManager) validateResumeFrom(feedType types.FeedType, resumeFrom uint64) error {
+	history, ok := f.history[feedType]
+	if !ok {
+		return fmt.Errorf("resume_from is not supported by %v feed, the gateway does not keep its history", feedType)
+	}
+	if lastSeq := history.lastSequence(); resumeFrom > lastSeq {
+		return fmt.Errorf("resume_from %v is ahead of the last %v notification %v", resumeFrom, feedType, lastSeq)
+	}
+	return nil
+}
+
 // NeedBlocks checks if feedManager should receive block notifications
 func (f *FeedManager) NeedBlocks() bool {
+	// the history of the block feeds records the blocks even without subscriptions
+	for _, feed := range blockHistoryFeeds {
+		if f.HistoryEnabled(feed) {
+			return true
+		}
+	}
+
 	f.lock.RLock()
 	defer f.lock.RUnlock()
 	for _, clientSub := range f.idToClientSubscription {
@@ -409,16 +493,21 @@ func (f *FeedManager) GetGrpcSubscriptionReply() *pb.SubscriptionsReply {
 	defer f.lock.RUnlock()
 	for _, clientData := range f.idToClientSubscription {
 		subscribe := &pb.Subscription{
-			AccountId:    string(clientData.AccountID),
-			Tier:         clientData.Tier,
-			FeedName:     string(clientData.feedType),
-			Network:      uint32(clientData.network),
-			RemoteAddr:   clientData.RemoteAddress,
-			Include:      clientData.Includes,
-			Filter:       clientData.Filters,
-			Age:          uint64(time.Since(clientData.timeOpenedFeed).Seconds()),
-			MessagesSent: clientData.messagesSent,
-			ConnType:     string(clientData.feedConnectionType),
+			AccountId:        string(clientData.AccountID),
+			Tier:             clientData.Tier,
+			FeedName:         string(clientData.feedType),
+			Network:          uint32(clientData.network),
+			RemoteAddr:       clientData.RemoteAddress,
+			Include:          clientData.Includes,
+			Filter:           clientData.Filters,
+			Age:              uint64(time.Since(clientData.timeOpenedFeed).Seconds()),
+			MessagesSent:     clientData.stats.sent.Load(),
+			ConnType:         string(clientData.feedConnectionType),
+			Backpressure:     string(clientData.Backpressure),
+			Queued:           uint64(len(clientData.feed)),
+			Dropped:          clientData.stats.dropped.Load(),
+			AvgSendLatencyUs: uint64(clientData.stats.avgSendLatency().Microseconds()),
+			MaxSendLatencyUs: uint64(time.Duration(clientData.stats.maxSendLatency.Load()).Microseconds()),
 		}
 		resp.Subscriptions = append(resp.Subscriptions, subscribe)
 	}
@@ -497,6 +586,41 @@ func (f *FeedManager) UnlockPendingNextValidatorTxs() {
 	f.pendingBSCNextValidatorTxsMapLock.Unlock()
 }
 
+// SetPendingNonceTracker sets the tracker of the pending nonces served to the clients
+func (f *FeedManager) SetPendingNonceTracker(tracker *services.PendingNonceTracker) {
+	f.pendingNonces = tracker
+}
+
+// SetTxReplacementManager sets the manager of the transaction replacements submitted by the clients
+func (f *FeedManager) SetTxReplacementManager(manager *services.TxReplacementManager) {
+	f.txReplacements = manager
+}
+
+// SetConditionalTxStore sets the store of the transactions held until their condition matches a block
+func (f *FeedManager) SetConditionalTxStore(store *services.ConditionalTxStore) {
+	f.conditionalTxs = store
+}
+
+// SetTxStatusTracker sets the tracker of the transactions monitored by the clients
+func (f *FeedManager) SetTxStatusTracker(tracker *services.TxStatusTracker) {
+	f.txStatuses = tracker
+}
+
+// SetMempoolSnapshots sets the snapshots of the pending transactions served to the clients
+func (f *FeedManager) SetMempoolSnapshots(snapshots *MempoolSnapshots) {
+	f.mempoolSnapshots = snapshots
+}
+
+// SetGasOracle sets the oracle of the fees recommended to the clients
+func (f *FeedManager) SetGasOracle(oracle *services.GasOracle) {
+	f.gasOracle = oracle
+}
+
+// SetBundleTracker sets the tracker of the bundles submitted by the clients
+func (f *FeedManager) SetBundleTracker(tracker *services.BundleTracker) {
+	f.bundleTracker = tracker
+}
+
 // GetSyncedWSProvider returns synced websocket provider
 func (f *FeedManager) GetSyncedWSProvider(preferredProviderEndpoint *types.NodeEndpoint) (blockchain.WSProvider, bool) {
 	if !f.nodeWSManager.Synced() {
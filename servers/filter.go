@@ -3,191 +3,171 @@ package servers
 import (
 	"errors"
 	"fmt"
-	"regexp"
-	"strconv"
+	"sort"
 	"strings"
 
 	log "github.com/bloXroute-Labs/gateway/v2/logger"
-	"github.com/bloXroute-Labs/gateway/v2/types"
-	"github.com/bloXroute-Labs/gateway/v2/utils"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
-	"github.com/zhouzhuojie/conditions"
 )
 
 var (
-	operators        = []string{"=", ">", "<", "!=", ">=", "<=", "in"}
-	operands         = []string{"and", "or"}
-	availableFilters = []string{"gas", "gas_price", "value", "to", "from", "method_id", "type", "chain_id", "max_fee_per_gas", "max_priority_fee_per_gas"}
+	legacyGasTxTypes  = []int64{ethtypes.LegacyTxType, ethtypes.AccessListTxType}
+	dynamicFeeTxTypes = []int64{ethtypes.DynamicFeeTxType}
+
+	// txFilterSchema describes the fields of newTxs/pendingTxs filters and the tx types each of them is defined for
+	txFilterSchema = newFilterSchema("type", []int64{ethtypes.LegacyTxType, ethtypes.AccessListTxType, ethtypes.DynamicFeeTxType},
+		&filterField{name: "gas", kind: filterKindNumber},
+		&filterField{name: "gas_price", kind: filterKindNumber, variants: legacyGasTxTypes},
+		&filterField{name: "value", kind: filterKindNumber},
+		&filterField{name: "to", kind: filterKindHex},
+		&filterField{name: "from", kind: filterKindHex},
+		&filterField{name: "method_id", kind: filterKindHex},
+		&filterField{name: "type", kind: filterKindNumber},
+		&filterField{name: "chain_id", kind: filterKindNumber},
+		&filterField{name: "max_fee_per_gas", kind: filterKindNumber, variants: dynamicFeeTxTypes},
+		&filterField{name: "max_priority_fee_per_gas", kind: filterKindNumber, variants: dynamicFeeTxTypes},
+	)
+	txFilterSchemaWithoutFrom = txFilterSchema.without(txFromFilter)
 )
 
-// This function is used to skip the evaluation of txs which are not supported by the filters.
-// for example, if the client requested to filter by gas_price we will not evaluate dynamic fee txs, cause the
-// evaluation result will not be correct, or cause the error.
-// It helps us to proceed only to evaluation part with complex filters, like this one:
-// (type == '0' && gas_price > 21000000000) || (type == '2' && max_priority_fee_per_gas > 21000000000)
-//
-// TODO: to get rid of this stuff, we need to move to another expression evaluation library (or forking existing one)
-// to support short circuit behavior for the logical operators.
-func isFiltersSupportedByTxType(txType uint8, filters []string) bool {
-	gasPriceExists := utils.Exists("gas_price", filters)
-	maxFeePerGasExists := utils.Exists("max_fee_per_gas", filters)
-	maxPriorityFeePerGasExists := utils.Exists("max_priority_fee_per_gas", filters)
-
-	if txType == ethtypes.DynamicFeeTxType {
-		if gasPriceExists && !maxFeePerGasExists && !maxPriorityFeePerGasExists {
-			return false
-		}
-	} else {
-		if !gasPriceExists && (maxFeePerGasExists || maxPriorityFeePerGasExists) {
-			return false
-		}
-	}
-	return true
+// filterSchema is the set of fields a filter expression can reference. When variantField is set, notifications
+// come in several variants (e.g. tx types) and some fields only exist for some of them. Fields that are not
+// part of the schema are resolved against the calldata ABI of the subscription, if any
+type filterSchema struct {
+	fields       map[string]*filterField
+	variantField string
+	variants     []int64
+	calldata     *calldataABI
 }
 
-func validateFilters(filters string, txFromFieldIncludable bool) (conditions.Expr, error) {
-	_, expr, err := parseFilter(filters)
-	if err != nil {
-		return nil, fmt.Errorf("error parsing Filters: %v", err)
+func newFilterSchema(variantField string, variants []int64, fields ...*filterField) *filterSchema {
+	s := &filterSchema{
+		fields:       make(map[string]*filterField, len(fields)),
+		variantField: variantField,
+		variants:     variants,
 	}
-	if expr == nil {
-		return nil, nil
+	for _, f := range fields {
+		s.fields[f.name] = f
 	}
+	return s
+}
 
-	err = evaluateFilters(expr)
-	if err != nil {
-		return nil, fmt.Errorf("error evaluated Filters: %v", err)
+func (s *filterSchema) without(name string) *filterSchema {
+	c := &filterSchema{fields: make(map[string]*filterField, len(s.fields)), variantField: s.variantField, variants: s.variants, calldata: s.calldata}
+	for n, f := range s.fields {
+		if n != name {
+			c.fields[n] = f
+		}
 	}
+	return c
+}
 
-	if !txFromFieldIncludable && utils.Exists(txFromFilter, expr.Args()) {
-		return nil, fmt.Errorf("error creating Filters: error evaluated Filters: argument: %s not found", txFromFilter)
+func (s *filterSchema) withCalldata(calldata *calldataABI) *filterSchema {
+	c := *s
+	c.calldata = calldata
+	return &c
+}
+
+func (s *filterSchema) names() []string {
+	names := make([]string, 0, len(s.fields))
+	for name := range s.fields {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+	return names
+}
 
-	log.Infof("GetTxContentAndFilters string - %s, GetTxContentAndFilters args - %s", expr, expr.Args())
-	return expr, nil
+func (s *filterSchema) lookup(name string, pos int) (*filterField, error) {
+	if f, ok := s.fields[strings.ToLower(name)]; ok {
+		return f, nil
+	}
+	if s.calldata != nil && strings.Contains(name, ".") {
+		f, err := s.calldata.resolve(name)
+		if err != nil {
+			return nil, newFilterError(pos, "%v", err)
+		}
+		return f, nil
+	}
+	return nil, newFilterError(pos, "unknown field %v, available fields are: %v", name, strings.Join(s.names(), ", "))
 }
 
-// evaluateFilters - evaluating if the Filters provided by the user are ok
-func evaluateFilters(expr conditions.Expr) error {
-	// Evaluate if we should send the tx
-	_, err := conditions.Evaluate(expr, types.EmptyFilteredTransactionMap)
-	return err
+// filterExpr is a compiled filter expression
+type filterExpr struct {
+	root filterNode
+	args []string
 }
 
-// IsCorrectGasPriceFilters check if the gas price filters provided by the user are correct.
-// Needed because of the way we are parsing the filters. All gas price filters configured
-// at once without a type filter will not cause the error to be thrown.
-//
-// TODO: to get rid of this stuff, we need to move to another expression evaluation library (or forking existing one)
-// to support short circuit behavior for the logical operators.
-func IsCorrectGasPriceFilters(filters []string) bool {
-	gasPriceExists := utils.Exists("gas_price", filters)
-	maxFeePerGasExists := utils.Exists("max_fee_per_gas", filters)
-	maxPriorityFeePerGasExists := utils.Exists("max_priority_fee_per_gas", filters)
-	txType := utils.Exists("type", filters)
-
-	if gasPriceExists && (maxFeePerGasExists || maxPriorityFeePerGasExists) && !txType {
+// Args returns the fields referenced by the expression
+func (e *filterExpr) Args() []string {
+	return e.args
+}
+
+// String returns the canonical {field} form of the expression
+func (e *filterExpr) String() string {
+	return e.root.String()
+}
+
+// Evaluate checks the field values of a notification against the expression
+func (e *filterExpr) Evaluate(values map[string]interface{}) bool {
+	if values == nil {
 		return false
 	}
-	return true
+	return e.root.eval(values)
 }
 
-// parseFilter parsing the filter
-func parseFilter(filters string) (string, conditions.Expr, error) {
-	// if the filters values are go-type filters, for example: {value}, parse the filters
-	// if not go-type, convert it to go-type filters
-	if strings.Contains(filters, "{") {
-		p := conditions.NewParser(strings.NewReader(strings.ToLower(strings.Replace(filters, "'", "\"", -1))))
-		expr, err := p.Parse()
-		if err == nil {
-			isEmptyValue := filtersHasEmptyValue(expr.String())
-			if isEmptyValue != nil {
-				return "", nil, errors.New("filter is empty")
-			}
-			if !IsCorrectGasPriceFilters(expr.Args()) {
-				return "", nil, errors.New("invalid filters, add the type filter")
-			}
-		}
-		return "", expr, err
+// compileFilter parses a filter in either the SQL-like or the {field} syntax and type checks it against the schema
+func compileFilter(filter string, schema *filterSchema) (*filterExpr, error) {
+	if strings.TrimSpace(filter) == "" {
+		return nil, errors.New("filter is empty")
 	}
 
-	// convert the string and add whitespace to separate elements
-	tempFilters := strings.ReplaceAll(filters, "(", " ( ")
-	tempFilters = strings.ReplaceAll(tempFilters, ")", " ) ")
-	tempFilters = strings.ReplaceAll(tempFilters, "[", " [ ")
-	tempFilters = strings.ReplaceAll(tempFilters, "]", " ] ")
-	tempFilters = strings.ReplaceAll(tempFilters, ",", " , ")
-	tempFilters = strings.ReplaceAll(tempFilters, "=", " = ")
-	tempFilters = strings.ReplaceAll(tempFilters, "<", " < ")
-	tempFilters = strings.ReplaceAll(tempFilters, ">", " > ")
-	tempFilters = strings.ReplaceAll(tempFilters, "!", " ! ")
-	tempFilters = strings.ReplaceAll(tempFilters, ",", " , ")
-	tempFilters = strings.ReplaceAll(tempFilters, "<  =", "<=")
-	tempFilters = strings.ReplaceAll(tempFilters, ">  =", ">=")
-	tempFilters = strings.ReplaceAll(tempFilters, "!  =", "!=")
-	tempFilters = strings.Trim(tempFilters, " ")
-	tempFilters = strings.ToLower(tempFilters)
-	filtersArr := strings.Split(tempFilters, " ")
-
-	var newFilterString strings.Builder
-	for _, elem := range filtersArr {
-		switch {
-		case elem == "":
-		case elem == "(", elem == ")", elem == ",", elem == "]", elem == "[":
-			newFilterString.WriteString(elem)
-		case utils.Exists(elem, operators):
-			newFilterString.WriteString(" ")
-			if elem == "=" {
-				newFilterString.WriteString(elem)
-			}
-			newFilterString.WriteString(elem + " ")
-		case utils.Exists(elem, operands):
-			newFilterString.WriteString(")")
-			newFilterString.WriteString(" " + elem + " ")
-		case utils.Exists(elem, availableFilters):
-			newFilterString.WriteString("({" + elem + "}")
-		default:
-			isString := false
-			if _, err := strconv.Atoi(elem); err != nil {
-				isString = true
-			}
-			switch {
-			case isString && len(elem) >= 2 && elem[0:2] != "0x":
-				newFilterString.WriteString("'0x" + elem + "'")
-			case isString && len(elem) >= 2 && elem[0:2] == "0x":
-				newFilterString.WriteString("'" + elem + "'")
-			default:
-				newFilterString.WriteString(elem)
-			}
-		}
+	tokens, err := tokenizeFilter(filter)
+	if err != nil {
+		return nil, err
 	}
 
-	newFilterString.WriteString(")")
-
-	p := conditions.NewParser(strings.NewReader(strings.ToLower(strings.Replace(newFilterString.String(), "'", "\"", -1))))
-	expr, err := p.Parse()
+	p := &filterParser{tokens: tokens, schema: schema}
+	root, err := p.parse()
 	if err != nil {
-		return "", nil, err
+		return nil, err
 	}
 
-	err = filtersHasEmptyValue(expr.String())
-	if err != nil {
-		return "", nil, err
+	if schema.variantField != "" {
+		satisfiable := false
+		for _, variant := range schema.variants {
+			if root.satisfiable(schema, variant) {
+				satisfiable = true
+				break
+			}
+		}
+		if !satisfiable {
+			return nil, newFilterError(root.pos(), "filter can never match, the fields it requires are not defined for the same %v, "+
+				"use the %v field to separate the conditions", schema.variantField, schema.variantField)
+		}
 	}
 
-	return newFilterString.String(), expr, nil
+	return &filterExpr{root: root, args: root.fields(nil)}, nil
 }
 
-var rex = regexp.MustCompile(`\(([^)]+)\)`)
+// parseFilter compiles a newTxs/pendingTxs filter
+func parseFilter(filters string) (*filterExpr, error) {
+	return compileFilter(filters, txFilterSchema)
+}
 
-func filtersHasEmptyValue(rawFilters string) error {
-	out := rex.FindAllStringSubmatch(rawFilters, -1)
-	for _, i := range out {
-		for _, filter := range availableFilters {
-			if i[1] == filter || filter == rawFilters {
-				return fmt.Errorf("filter is empty: %v", i[1])
-			}
-		}
+func validateFilters(filters string, txFromFieldIncludable bool, calldata *calldataABI) (*filterExpr, error) {
+	schema := txFilterSchema
+	if !txFromFieldIncludable {
+		schema = txFilterSchemaWithoutFrom
 	}
-	return nil
+	if calldata != nil {
+		schema = schema.withCalldata(calldata)
+	}
+
+	expr, err := compileFilter(filters, schema)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing Filters: %v", err)
+	}
+
+	log.Infof("GetTxContentAndFilters string - %s, GetTxContentAndFilters args - %s", expr, expr.Args())
+	return expr, nil
 }
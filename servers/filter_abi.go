@@ -0,0 +1,385 @@
+package servers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const (
+	abiDirName         = "abi"
+	decodedInputField  = "tx_contents.decoded_input"
+	decodedInputResult = "decodedInput"
+)
+
+// abiRegistry holds the named contract ABIs that subscriptions can refer to, loaded from <data-dir>/abi/<name>.json
+type abiRegistry struct {
+	abis map[string]*abi.ABI
+}
+
+func loadABIRegistry(datadir string) (*abiRegistry, error) {
+	registry := &abiRegistry{abis: make(map[string]*abi.ABI)}
+
+	dir := path.Join(datadir, abiDirName)
+	files, err := filepath.Glob(path.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		contents, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read abi file %v: %w", file, err)
+		}
+		contractABI, err := abi.JSON(bytes.NewReader(contents))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse abi file %v: %w", file, err)
+		}
+		name := strings.ToLower(strings.TrimSuffix(filepath.Base(file), ".json"))
+		registry.abis[name] = &contractABI
+	}
+
+	if len(registry.abis) > 0 {
+		log.Infof("loaded %v contract ABIs from %v", len(registry.abis), dir)
+	}
+	return registry, nil
+}
+
+func (r *abiRegistry) get(name string) (*abi.ABI, bool) {
+	if r == nil {
+		return nil, false
+	}
+	contractABI, ok := r.abis[strings.ToLower(name)]
+	return contractABI, ok
+}
+
+// subscriptionABI returns the ABI of a subscription abi option, which is either an inline ABI JSON array
+// or the name of an ABI of the registry. nil is returned if the option is not set
+func subscriptionABI(option json.RawMessage, registry *abiRegistry) (*abi.ABI, error) {
+	if len(option) == 0 || string(option) == "null" {
+		return nil, nil
+	}
+
+	var name string
+	if err := json.Unmarshal(option, &name); err == nil {
+		contractABI, ok := registry.get(name)
+		if !ok {
+			return nil, fmt.Errorf("abi %v is not found", name)
+		}
+		return contractABI, nil
+	}
+
+	contractABI, err := abi.JSON(bytes.NewReader(option))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse abi: %w", err)
+	}
+	return &contractABI, nil
+}
+
+// newCalldataABI builds the calldata decoder of a newTxs/pendingTxs subscription from its abi option
+func newCalldataABI(option json.RawMessage, registry *abiRegistry) (*calldataABI, error) {
+	contractABI, err := subscriptionABI(option, registry)
+	if err != nil || contractABI == nil {
+		return nil, err
+	}
+	if len(contractABI.Methods) == 0 {
+		return nil, errors.New("abi does not contain any methods")
+	}
+	return &calldataABI{abi: contractABI, paths: make(map[string]*abiFieldPath)}, nil
+}
+
+// calldataABI decodes tx input with the methods of a subscription ABI. Filter fields named
+// <method>.<argument> (e.g. transfer.amount, swapExactTokensForTokens.path[0], fill.order.maker)
+// are resolved against it while the filter is compiled
+type calldataABI struct {
+	abi   *abi.ABI
+	paths map[string]*abiFieldPath
+}
+
+type abiPathStep struct {
+	index   int
+	element bool // index is an array element, otherwise a tuple component
+}
+
+type abiFieldPath struct {
+	method *abi.Method
+	arg    int
+	steps  []abiPathStep
+}
+
+func (c *calldataABI) method(name string) *abi.Method {
+	for _, method := range c.abi.Methods {
+		if strings.EqualFold(method.Name, name) {
+			method := method
+			return &method
+		}
+	}
+	return nil
+}
+
+// resolve checks a field path against the ABI and returns the filter field describing it
+func (c *calldataABI) resolve(name string) (*filterField, error) {
+	name = strings.ToLower(name)
+	if p, ok := c.paths[name]; ok {
+		kind, _ := abiFilterKind(p.leafType())
+		return &filterField{name: name, kind: kind}, nil
+	}
+
+	dot := strings.IndexByte(name, '.')
+	if dot <= 0 {
+		return nil, fmt.Errorf("unknown field %v", name)
+	}
+	method := c.method(name[:dot])
+	if method == nil {
+		return nil, fmt.Errorf("method %v is not found in abi", name[:dot])
+	}
+
+	p := &abiFieldPath{method: method, arg: -1}
+	var t *abi.Type
+	rest := name[dot+1:]
+	for rest != "" {
+		switch {
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("missing ']' in %v", name)
+			}
+			i, err := strconv.Atoi(rest[1:end])
+			if err != nil || i < 0 {
+				return nil, fmt.Errorf("invalid index %v in %v", rest[1:end], name)
+			}
+			if t == nil || (t.T != abi.SliceTy && t.T != abi.ArrayTy) {
+				return nil, fmt.Errorf("%v is not an array", strings.TrimSuffix(name, rest))
+			}
+			if t.T == abi.ArrayTy && i >= t.Size {
+				return nil, fmt.Errorf("index %v is out of range in %v", i, name)
+			}
+			p.steps = append(p.steps, abiPathStep{index: i, element: true})
+			t = t.Elem
+			rest = rest[end+1:]
+		default:
+			rest = strings.TrimPrefix(rest, ".")
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			component := rest[:end]
+			rest = rest[end:]
+
+			if t == nil {
+				for i, arg := range method.Inputs {
+					if strings.EqualFold(arg.Name, component) {
+						p.arg = i
+						t = &method.Inputs[i].Type
+						break
+					}
+				}
+				if t == nil {
+					return nil, fmt.Errorf("method %v has no argument %v", method.Name, component)
+				}
+				continue
+			}
+
+			if t.T != abi.TupleTy {
+				return nil, fmt.Errorf("%v is not a tuple", strings.TrimSuffix(name, "."+component+rest))
+			}
+			found := false
+			for i, raw := range t.TupleRawNames {
+				if strings.EqualFold(raw, component) {
+					p.steps = append(p.steps, abiPathStep{index: i})
+					t = t.TupleElems[i]
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("tuple %v has no component %v", strings.TrimSuffix(name, "."+component+rest), component)
+			}
+		}
+	}
+	if t == nil {
+		return nil, fmt.Errorf("field %v does not select a method argument", name)
+	}
+
+	kind, ok := abiFilterKind(*t)
+	if !ok {
+		return nil, fmt.Errorf("field %v of type %v can not be filtered, select an element or a component of it", name, t)
+	}
+
+	c.paths[name] = p
+	return &filterField{name: name, kind: kind}, nil
+}
+
+func (p *abiFieldPath) leafType() abi.Type {
+	t := p.method.Inputs[p.arg].Type
+	for _, step := range p.steps {
+		if step.element {
+			t = *t.Elem
+		} else {
+			t = *t.TupleElems[step.index]
+		}
+	}
+	return t
+}
+
+func abiFilterKind(t abi.Type) (filterKind, bool) {
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		return filterKindNumber, true
+	case abi.AddressTy, abi.BytesTy, abi.FixedBytesTy, abi.HashTy:
+		return filterKindHex, true
+	case abi.BoolTy:
+		return filterKindBool, true
+	case abi.StringTy:
+		return filterKindString, true
+	}
+	return 0, false
+}
+
+// unpack decodes the arguments of input if its selector is the one of method
+func unpackCalldata(method *abi.Method, input []byte) ([]interface{}, bool) {
+	if len(input) < 4 || !bytes.Equal(input[:4], method.ID) {
+		return nil, false
+	}
+	args, err := method.Inputs.Unpack(input[4:])
+	if err != nil {
+		return nil, false
+	}
+	return args, true
+}
+
+// addValues decodes the input and adds the values of the resolved fields referenced by the filter.
+// Fields of methods other than the one called stay missing, so they never match
+func (c *calldataABI) addValues(values map[string]interface{}, input []byte, fields []string) {
+	unpacked := make(map[*abi.Method][]interface{})
+	for _, name := range fields {
+		p, ok := c.paths[name]
+		if !ok {
+			continue
+		}
+
+		args, decoded := unpacked[p.method]
+		if !decoded {
+			args, _ = unpackCalldata(p.method, input)
+			unpacked[p.method] = args
+		}
+		if args == nil {
+			continue
+		}
+
+		v := reflect.ValueOf(args[p.arg])
+		for _, step := range p.steps {
+			if step.element {
+				if step.index >= v.Len() {
+					v = reflect.Value{}
+					break
+				}
+				v = v.Index(step.index)
+			} else {
+				v = v.Field(step.index)
+			}
+		}
+		if !v.IsValid() {
+			continue
+		}
+		if value, ok := abiFilterValue(v); ok {
+			values[name] = value
+		}
+	}
+}
+
+// decode returns the method name and the JSON friendly arguments of the input, nil if it does not call a method of the ABI
+func (c *calldataABI) decode(input []byte) map[string]interface{} {
+	if len(input) < 4 {
+		return nil
+	}
+	method, err := c.abi.MethodById(input[:4])
+	if err != nil {
+		return nil
+	}
+	args, ok := unpackCalldata(method, input)
+	if !ok {
+		return nil
+	}
+
+	decodedArgs := make(map[string]interface{}, len(args))
+	for i, arg := range method.Inputs {
+		name := arg.Name
+		if name == "" {
+			name = strconv.Itoa(i)
+		}
+		decodedArgs[name] = abiJSONValue(arg.Type, reflect.ValueOf(args[i]))
+	}
+	return map[string]interface{}{
+		"method": method.Name,
+		"args":   decodedArgs,
+	}
+}
+
+func abiFilterValue(v reflect.Value) (interface{}, bool) {
+	switch x := v.Interface().(type) {
+	case *big.Int:
+		return x, x != nil
+	case common.Address:
+		return types.AddressAsString(&x), true
+	case []byte:
+		return hexutil.Encode(x), true
+	case bool, string:
+		return x, true
+	}
+
+	switch v.Kind() {
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(v.Uint()), true
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(v.Int()), true
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			return hexutil.Encode(b), true
+		}
+	}
+	return nil, false
+}
+
+func abiJSONValue(t abi.Type, v reflect.Value) interface{} {
+	switch t.T {
+	case abi.SliceTy, abi.ArrayTy:
+		values := make([]interface{}, v.Len())
+		for i := range values {
+			values[i] = abiJSONValue(*t.Elem, v.Index(i))
+		}
+		return values
+	case abi.TupleTy:
+		values := make(map[string]interface{}, len(t.TupleElems))
+		for i, elem := range t.TupleElems {
+			values[t.TupleRawNames[i]] = abiJSONValue(*elem, v.Field(i))
+		}
+		return values
+	}
+
+	value, ok := abiFilterValue(v)
+	if !ok {
+		return nil
+	}
+	if n, isNumber := value.(*big.Int); isNumber {
+		// numbers are sent as strings, uint256 values don't fit in JSON numbers of most clients
+		return n.String()
+	}
+	return value
+}
@@ -0,0 +1,84 @@
+package servers
+
+import (
+	"fmt"
+	"strings"
+
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	blockTxFieldPrefix = "txs."
+	blockTxHashField   = "txs.hash"
+	blockTxToField     = "txs.to"
+)
+
+// conditionFilterSchema describes the fields of the conditions of held transactions, which are evaluated against
+// each new block. Fields prefixed with txs. are matched against each transaction of the block separately
+var conditionFilterSchema = newFilterSchema("", nil,
+	&filterField{name: "block_number", kind: filterKindNumber},
+	&filterField{name: "base_fee_per_gas", kind: filterKindNumber},
+	&filterField{name: "timestamp", kind: filterKindNumber},
+	&filterField{name: "gas_used", kind: filterKindNumber},
+	&filterField{name: "gas_limit", kind: filterKindNumber},
+	&filterField{name: "miner", kind: filterKindHex},
+	&filterField{name: blockTxHashField, kind: filterKindHex},
+	&filterField{name: blockTxToField, kind: filterKindHex},
+)
+
+// validateConditionFilters compiles the condition of a held transaction
+func validateConditionFilters(condition string) (*filterExpr, error) {
+	expr, err := compileFilter(condition, conditionFilterSchema)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing condition: %v", err)
+	}
+	return expr, nil
+}
+
+func conditionFilterValues(block *ethtypes.Block) map[string]interface{} {
+	values := map[string]interface{}{
+		"block_number": block.Number(),
+		"timestamp":    block.Time(),
+		"gas_used":     block.GasUsed(),
+		"gas_limit":    block.GasLimit(),
+		"miner":        block.Coinbase().Hex(),
+	}
+	if baseFee := block.BaseFee(); baseFee != nil {
+		values["base_fee_per_gas"] = baseFee
+	}
+	return values
+}
+
+// matchBlockCondition evaluates the condition against a block. If the condition references transaction fields, the
+// block matches when the condition matches together with at least one of its transactions
+func matchBlockCondition(expr *filterExpr, block *ethtypes.Block) bool {
+	values := conditionFilterValues(block)
+
+	txFields := false
+	for _, arg := range expr.Args() {
+		if strings.HasPrefix(arg, blockTxFieldPrefix) {
+			txFields = true
+			break
+		}
+	}
+	if !txFields {
+		return expr.Evaluate(values)
+	}
+
+	txValues := make(map[string]interface{}, len(values)+2)
+	for k, v := range values {
+		txValues[k] = v
+	}
+	for _, tx := range block.Transactions() {
+		txValues[blockTxHashField] = tx.Hash().Hex()
+		if to := tx.To(); to != nil {
+			txValues[blockTxToField] = to.Hex()
+		} else {
+			delete(txValues, blockTxToField)
+		}
+		if expr.Evaluate(txValues) {
+			return true
+		}
+	}
+	return false
+}
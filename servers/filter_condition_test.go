@@ -0,0 +1,72 @@
+package servers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConditionBlock(number, baseFee int64, txs ...*ethtypes.Transaction) *ethtypes.Block {
+	header := &ethtypes.Header{
+		Number:   big.NewInt(number),
+		Time:     1700000000,
+		GasLimit: 30000000,
+		GasUsed:  15000000,
+		Coinbase: common.HexToAddress(testSenderAddress),
+	}
+	if baseFee != 0 {
+		header.BaseFee = big.NewInt(baseFee)
+	}
+	return ethtypes.NewBlockWithHeader(header).WithBody(txs, nil)
+}
+
+func TestValidateConditionFilters(t *testing.T) {
+	_, err := validateConditionFilters("{block_number} >= 100 and {base_fee_per_gas} < 30 gwei")
+	assert.Error(t, err)
+
+	expr, err := validateConditionFilters("block_number >= 100 AND base_fee_per_gas < 30e9")
+	require.NoError(t, err)
+	assert.Equal(t, "({block_number} >= 100) and ({base_fee_per_gas} < 30000000000)", expr.String())
+
+	_, err = validateConditionFilters("{gas_price} > 1")
+	assert.Error(t, err)
+	_, err = validateConditionFilters("")
+	assert.Error(t, err)
+}
+
+func TestMatchBlockCondition(t *testing.T) {
+	to := common.HexToAddress(testPairAddress)
+	tx := ethtypes.NewTx(&ethtypes.DynamicFeeTx{Nonce: 1, To: &to, Gas: 21000})
+	deploy := ethtypes.NewTx(&ethtypes.DynamicFeeTx{Nonce: 2, Gas: 100000})
+
+	tests := []struct {
+		condition string
+		block     *ethtypes.Block
+		match     bool
+	}{
+		{"{block_number} >= 100", testConditionBlock(100, 0), true},
+		{"{block_number} >= 100", testConditionBlock(99, 0), false},
+		{"{base_fee_per_gas} < 30000000000", testConditionBlock(100, 20000000000), true},
+		{"{base_fee_per_gas} < 30000000000", testConditionBlock(100, 40000000000), false},
+		// blocks without a base fee never match a base fee condition
+		{"{base_fee_per_gas} < 30000000000", testConditionBlock(100, 0), false},
+		{"{miner} == '" + testSenderAddress + "'", testConditionBlock(100, 0), true},
+		{"{timestamp} >= 1700000000 and {gas_used} > 0 and {gas_limit} == 30000000", testConditionBlock(100, 0), true},
+		{"{txs.hash} == '" + tx.Hash().Hex() + "'", testConditionBlock(100, 0, deploy, tx), true},
+		{"{txs.hash} == '" + tx.Hash().Hex() + "'", testConditionBlock(100, 0, deploy), false},
+		{"{txs.to} == '" + testPairAddress + "' and {block_number} > 100", testConditionBlock(101, 0, deploy, tx), true},
+		{"{txs.to} == '" + testPairAddress + "' and {block_number} > 100", testConditionBlock(100, 0, deploy, tx), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.condition, func(t *testing.T) {
+			expr, err := validateConditionFilters(test.condition)
+			require.NoError(t, err)
+			assert.Equal(t, test.match, matchBlockCondition(expr, test.block))
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package servers
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const maxEthLogsTopics = 4
+
+// ethLogsFilter - criteria of an eth_subscribe("logs") subscription, matched with the geth semantics: a log matches
+// if its address is one of the addresses (any if empty), and each topic position is either a wildcard or one of its values
+type ethLogsFilter struct {
+	addresses []string
+	topics    [][]string
+}
+
+// newEthLogsFilter parses the filter param of eth_subscribe("logs"), as decoded from the JSON request
+func newEthLogsFilter(param interface{}) (*ethLogsFilter, error) {
+	f := &ethLogsFilter{}
+	if param == nil {
+		return f, nil
+	}
+	criteria, ok := param.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid logs filter, expected an object")
+	}
+
+	for _, block := range []string{"fromBlock", "toBlock"} {
+		if v, ok := criteria[block]; ok && v != nil && v != "latest" {
+			return nil, fmt.Errorf("%v is not supported by logs subscriptions", block)
+		}
+	}
+	if v, ok := criteria["blockHash"]; ok && v != nil {
+		return nil, errors.New("blockHash is not supported by logs subscriptions")
+	}
+
+	switch address := criteria["address"].(type) {
+	case nil:
+	case string:
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("invalid address: %v", address)
+		}
+		f.addresses = append(f.addresses, strings.ToLower(address))
+	case []interface{}:
+		for i, a := range address {
+			s, ok := a.(string)
+			if !ok || !common.IsHexAddress(s) {
+				return nil, fmt.Errorf("invalid address at index %v: %v", i, a)
+			}
+			f.addresses = append(f.addresses, strings.ToLower(s))
+		}
+	default:
+		return nil, errors.New("invalid addresses in query")
+	}
+
+	switch topics := criteria["topics"].(type) {
+	case nil:
+	case []interface{}:
+		if len(topics) > maxEthLogsTopics {
+			return nil, errors.New("exceed max topics")
+		}
+		f.topics = make([][]string, len(topics))
+		for i, topic := range topics {
+			switch t := topic.(type) {
+			case nil:
+			case string:
+				hash, err := parseEthLogsTopic(t)
+				if err != nil {
+					return nil, err
+				}
+				f.topics[i] = []string{hash}
+			case []interface{}:
+				for _, alternative := range t {
+					if alternative == nil {
+						// a null alternative matches any topic
+						f.topics[i] = nil
+						break
+					}
+					s, ok := alternative.(string)
+					if !ok {
+						return nil, errors.New("invalid topic(s)")
+					}
+					hash, err := parseEthLogsTopic(s)
+					if err != nil {
+						return nil, err
+					}
+					f.topics[i] = append(f.topics[i], hash)
+				}
+			default:
+				return nil, errors.New("invalid topic(s)")
+			}
+		}
+	default:
+		return nil, errors.New("invalid topic(s)")
+	}
+
+	return f, nil
+}
+
+func parseEthLogsTopic(topic string) (string, error) {
+	b, err := hexutil.Decode(topic)
+	if err != nil || len(b) != common.HashLength {
+		return "", fmt.Errorf("invalid topic %v", topic)
+	}
+	return common.BytesToHash(b).Hex(), nil
+}
+
+func (f *ethLogsFilter) match(logMap map[string]interface{}) bool {
+	if len(f.addresses) > 0 {
+		address := strings.ToLower(interfaceToString(logMap["address"]))
+		found := false
+		for _, a := range f.addresses {
+			if a == address {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	topics := interfaceToStringArray(logMap["topics"])
+	if len(f.topics) > len(topics) {
+		return false
+	}
+	for i, alternatives := range f.topics {
+		if len(alternatives) == 0 {
+			continue
+		}
+		found := false
+		for _, alternative := range alternatives {
+			if strings.EqualFold(alternative, topics[i]) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// matchingLogs returns the logs of the receipts matching the filter, in the order they were emitted in the block
+func (f *ethLogsFilter) matchingLogs(receipts []*types.TxReceipt) []map[string]interface{} {
+	var logs []map[string]interface{}
+	for _, receipt := range receipts {
+		for _, receiptLog := range receipt.Logs {
+			logMap, ok := receiptLog.(map[string]interface{})
+			if ok && f.match(logMap) {
+				logs = append(logs, logMap)
+			}
+		}
+	}
+	// receipts are fetched concurrently, so they are not ordered
+	sort.SliceStable(logs, func(i, j int) bool {
+		return ethLogIndex(logs[i]) < ethLogIndex(logs[j])
+	})
+	return logs
+}
+
+func ethLogIndex(logMap map[string]interface{}) uint64 {
+	index, _ := hexutil.DecodeUint64(interfaceToString(logMap["logIndex"]))
+	return index
+}
+
+// removedEthLog copies a log with removed set, logs of the receipts are shared by all subscriptions
+func removedEthLog(logMap map[string]interface{}) map[string]interface{} {
+	removed := make(map[string]interface{}, len(logMap))
+	for k, v := range logMap {
+		removed[k] = v
+	}
+	removed["removed"] = true
+	return removed
+}
@@ -0,0 +1,123 @@
+package servers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testTransferTopic = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+	testApprovalTopic = "0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925"
+)
+
+func testEthLog(address string, logIndex string, topics ...string) map[string]interface{} {
+	logTopics := make([]interface{}, 0, len(topics))
+	for _, topic := range topics {
+		logTopics = append(logTopics, topic)
+	}
+	return map[string]interface{}{
+		"address":  address,
+		"topics":   logTopics,
+		"data":     "0x",
+		"logIndex": logIndex,
+		"removed":  false,
+	}
+}
+
+func testEthLogsFilter(t *testing.T, criteria string) *ethLogsFilter {
+	var param interface{}
+	require.NoError(t, json.Unmarshal([]byte(criteria), &param))
+	f, err := newEthLogsFilter(param)
+	require.NoError(t, err)
+	return f
+}
+
+func TestEthLogsFilterMatch(t *testing.T) {
+	transfer := testEthLog(testTokenAddress, "0x0", testTransferTopic, testAddressTopic(testPairAddress), testAddressTopic(testToAddress))
+	approval := testEthLog(testPairAddress, "0x1", testApprovalTopic, testAddressTopic(testToAddress))
+
+	tests := []struct {
+		criteria string
+		transfer bool
+		approval bool
+	}{
+		{criteria: `{}`, transfer: true, approval: true},
+		{criteria: `{"address": "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"}`, transfer: true},
+		{criteria: `{"address": ["` + testTokenAddress + `", "` + testPairAddress + `"]}`, transfer: true, approval: true},
+		{criteria: `{"topics": ["` + testTransferTopic + `"]}`, transfer: true},
+		{criteria: `{"topics": [["` + testTransferTopic + `", "` + testApprovalTopic + `"]]}`, transfer: true, approval: true},
+		{criteria: `{"topics": [null, null, "` + testAddressTopic(testToAddress) + `"]}`, transfer: true},
+		{criteria: `{"topics": [null, "` + testAddressTopic(testToAddress) + `"]}`, approval: true},
+		{criteria: `{"topics": [["` + testApprovalTopic + `", null]]}`, transfer: true, approval: true},
+		// more topics than the log has never match
+		{criteria: `{"topics": [null, null, null]}`, transfer: true},
+		{criteria: `{"address": "` + testPairAddress + `", "topics": ["` + testTransferTopic + `"]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.criteria, func(t *testing.T) {
+			f := testEthLogsFilter(t, tt.criteria)
+			assert.Equal(t, tt.transfer, f.match(transfer))
+			assert.Equal(t, tt.approval, f.match(approval))
+		})
+	}
+
+	f, err := newEthLogsFilter(nil)
+	require.NoError(t, err)
+	assert.True(t, f.match(transfer))
+}
+
+func TestEthLogsFilterErrors(t *testing.T) {
+	tests := map[string]string{
+		`{"address": "0x01"}`:                          "invalid address: 0x01",
+		`{"address": ["` + testTokenAddress + `", 1]}`: "invalid address at index 1: 1",
+		`{"topics": ["0x01"]}`:                         "invalid topic 0x01",
+		`{"topics": [null, null, null, null, null]}`:   "exceed max topics",
+		`{"topics": [1]}`:                              "invalid topic(s)",
+		`{"fromBlock": "0x1"}`:                         "fromBlock is not supported by logs subscriptions",
+		`{"blockHash": "` + testTransferTopic + `"}`:   "blockHash is not supported by logs subscriptions",
+		`["` + testTokenAddress + `"]`:                 "invalid logs filter, expected an object",
+		`{"fromBlock": "latest", "toBlock": "latest"}`: "",
+	}
+
+	for criteria, expected := range tests {
+		t.Run(criteria, func(t *testing.T) {
+			var param interface{}
+			require.NoError(t, json.Unmarshal([]byte(criteria), &param))
+			_, err := newEthLogsFilter(param)
+			if expected == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, expected)
+		})
+	}
+}
+
+func TestEthLogsMatchingLogsOrder(t *testing.T) {
+	f := testEthLogsFilter(t, `{"topics": ["`+testTransferTopic+`"]}`)
+	receipts := []*types.TxReceipt{
+		{Logs: []interface{}{testEthLog(testTokenAddress, "0x5", testTransferTopic), testEthLog(testTokenAddress, "0x6", testApprovalTopic)}},
+		{Logs: []interface{}{testEthLog(testTokenAddress, "0x1", testTransferTopic), testEthLog(testTokenAddress, "0x2", testTransferTopic)}},
+	}
+
+	logs := f.matchingLogs(receipts)
+	require.Len(t, logs, 3)
+	assert.Equal(t, "0x1", logs[0]["logIndex"])
+	assert.Equal(t, "0x2", logs[1]["logIndex"])
+	assert.Equal(t, "0x5", logs[2]["logIndex"])
+}
+
+func TestRemovedEthLog(t *testing.T) {
+	ethLog := testEthLog(testTokenAddress, "0x0", testTransferTopic)
+
+	removed := removedEthLog(ethLog)
+	assert.Equal(t, true, removed["removed"])
+	assert.Equal(t, "0x0", removed["logIndex"])
+	// the log of the receipt is shared by all subscriptions and is left unchanged
+	assert.Equal(t, false, ethLog["removed"])
+}
@@ -0,0 +1,309 @@
+package servers
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// filterKind is the type of value a filter field holds
+type filterKind int
+
+const (
+	filterKindNumber filterKind = iota
+	filterKindHex
+	filterKindBool
+	filterKindString
+)
+
+func (k filterKind) String() string {
+	switch k {
+	case filterKindNumber:
+		return "number"
+	case filterKindBool:
+		return "bool"
+	case filterKindString:
+		return "string"
+	default:
+		return "hex"
+	}
+}
+
+// filterField describes a field that can be referenced in a filter expression
+type filterField struct {
+	name string
+	kind filterKind
+	// variants lists the variants (e.g. tx types) for which the field is defined, nil means all of them
+	variants []int64
+}
+
+func (f *filterField) definedFor(variant int64) bool {
+	if f.variants == nil {
+		return true
+	}
+	for _, v := range f.variants {
+		if v == variant {
+			return true
+		}
+	}
+	return false
+}
+
+// filterValue is a literal converted to the kind of the field it is compared with
+type filterValue struct {
+	kind    filterKind
+	number  *big.Int
+	str     string
+	boolean bool
+}
+
+func (v filterValue) String() string {
+	switch v.kind {
+	case filterKindNumber:
+		return v.number.String()
+	case filterKindBool:
+		return strconv.FormatBool(v.boolean)
+	default:
+		return "'" + v.str + "'"
+	}
+}
+
+// filterNode is a node of a compiled filter expression
+type filterNode interface {
+	// eval evaluates the node against the field values of a notification, and/or short-circuit
+	eval(values map[string]interface{}) bool
+	// satisfiable reports whether the node can be true for a notification of the given variant
+	satisfiable(schema *filterSchema, variant int64) bool
+	// fields appends the names of the referenced fields
+	fields(names []string) []string
+	pos() int
+	String() string
+}
+
+type filterAnd struct {
+	lhs, rhs filterNode
+}
+
+func (n *filterAnd) eval(values map[string]interface{}) bool {
+	return n.lhs.eval(values) && n.rhs.eval(values)
+}
+
+func (n *filterAnd) satisfiable(schema *filterSchema, variant int64) bool {
+	return n.lhs.satisfiable(schema, variant) && n.rhs.satisfiable(schema, variant)
+}
+
+func (n *filterAnd) fields(names []string) []string {
+	return n.rhs.fields(n.lhs.fields(names))
+}
+
+func (n *filterAnd) pos() int { return n.lhs.pos() }
+
+func (n *filterAnd) String() string {
+	return n.lhs.String() + " and " + n.rhs.String()
+}
+
+type filterOr struct {
+	lhs, rhs filterNode
+}
+
+func (n *filterOr) eval(values map[string]interface{}) bool {
+	return n.lhs.eval(values) || n.rhs.eval(values)
+}
+
+func (n *filterOr) satisfiable(schema *filterSchema, variant int64) bool {
+	return n.lhs.satisfiable(schema, variant) || n.rhs.satisfiable(schema, variant)
+}
+
+func (n *filterOr) fields(names []string) []string {
+	return n.rhs.fields(n.lhs.fields(names))
+}
+
+func (n *filterOr) pos() int { return n.lhs.pos() }
+
+func (n *filterOr) String() string {
+	return n.lhs.String() + " or " + n.rhs.String()
+}
+
+type filterParen struct {
+	inner filterNode
+	start int
+}
+
+func (n *filterParen) eval(values map[string]interface{}) bool {
+	return n.inner.eval(values)
+}
+
+func (n *filterParen) satisfiable(schema *filterSchema, variant int64) bool {
+	return n.inner.satisfiable(schema, variant)
+}
+
+func (n *filterParen) fields(names []string) []string {
+	return n.inner.fields(names)
+}
+
+func (n *filterParen) pos() int { return n.start }
+
+func (n *filterParen) String() string {
+	return "(" + n.inner.String() + ")"
+}
+
+// filterCompare compares a field with a single value. A field missing from the notification (e.g. gas_price for a
+// dynamic fee tx, or to for a contract creation) is not equal to any value: != matches it, all the other operators don't
+type filterCompare struct {
+	field *filterField
+	op    string
+	value filterValue
+	start int
+}
+
+func (n *filterCompare) eval(values map[string]interface{}) bool {
+	v, ok := values[n.field.name]
+	if !ok {
+		return n.op == "!="
+	}
+
+	if n.field.kind == filterKindNumber {
+		number, ok := filterNumber(v)
+		if !ok {
+			return false
+		}
+		c := number.Cmp(n.value.number)
+		switch n.op {
+		case "==":
+			return c == 0
+		case "!=":
+			return c != 0
+		case ">":
+			return c > 0
+		case ">=":
+			return c >= 0
+		case "<":
+			return c < 0
+		case "<=":
+			return c <= 0
+		}
+		return false
+	}
+
+	var equal bool
+	switch n.field.kind {
+	case filterKindBool:
+		b, ok := v.(bool)
+		if !ok {
+			return false
+		}
+		equal = b == n.value.boolean
+	case filterKindString:
+		s, ok := v.(string)
+		if !ok {
+			return false
+		}
+		equal = s == n.value.str
+	default:
+		s, ok := v.(string)
+		if !ok {
+			return false
+		}
+		equal = strings.EqualFold(s, n.value.str)
+	}
+	if n.op == "!=" {
+		return !equal
+	}
+	return equal
+}
+
+func (n *filterCompare) satisfiable(schema *filterSchema, variant int64) bool {
+	if !n.field.definedFor(variant) {
+		return n.op == "!="
+	}
+	if n.field.name == schema.variantField {
+		return n.eval(map[string]interface{}{n.field.name: big.NewInt(variant)})
+	}
+	return true
+}
+
+func (n *filterCompare) fields(names []string) []string {
+	return appendFilterField(names, n.field.name)
+}
+
+func (n *filterCompare) pos() int { return n.start }
+
+func (n *filterCompare) String() string {
+	return "({" + n.field.name + "} " + n.op + " " + n.value.String() + ")"
+}
+
+// filterIn checks whether a field is (or with negate, is not) one of a list of values. A field missing from the
+// notification is in no list, so it only matches not in
+type filterIn struct {
+	field  *filterField
+	values []filterValue
+	negate bool
+	start  int
+}
+
+func (n *filterIn) eval(values map[string]interface{}) bool {
+	if _, ok := values[n.field.name]; !ok {
+		return n.negate
+	}
+	for _, value := range n.values {
+		eq := filterCompare{field: n.field, op: "==", value: value}
+		if eq.eval(values) {
+			return !n.negate
+		}
+	}
+	return n.negate
+}
+
+func (n *filterIn) satisfiable(schema *filterSchema, variant int64) bool {
+	if !n.field.definedFor(variant) {
+		return n.negate
+	}
+	if n.field.name == schema.variantField {
+		return n.eval(map[string]interface{}{n.field.name: big.NewInt(variant)})
+	}
+	return true
+}
+
+func (n *filterIn) fields(names []string) []string {
+	return appendFilterField(names, n.field.name)
+}
+
+func (n *filterIn) pos() int { return n.start }
+
+func (n *filterIn) String() string {
+	values := make([]string, 0, len(n.values))
+	for _, v := range n.values {
+		values = append(values, v.String())
+	}
+	op := " in "
+	if n.negate {
+		op = " not in "
+	}
+	return "({" + n.field.name + "}" + op + "[" + strings.Join(values, ",") + "])"
+}
+
+func appendFilterField(names []string, name string) []string {
+	for _, n := range names {
+		if n == name {
+			return names
+		}
+	}
+	return append(names, name)
+}
+
+// filterNumber converts a numeric notification field value to big.Int
+func filterNumber(v interface{}) (*big.Int, bool) {
+	switch n := v.(type) {
+	case *big.Int:
+		return n, n != nil
+	case uint64:
+		return new(big.Int).SetUint64(n), true
+	case int64:
+		return big.NewInt(n), true
+	case int:
+		return big.NewInt(int64(n)), true
+	case uint8:
+		return big.NewInt(int64(n)), true
+	}
+	return nil, false
+}
@@ -0,0 +1,394 @@
+package servers
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+type filterTokenKind int
+
+const (
+	filterTokenEOF filterTokenKind = iota
+	filterTokenLParen
+	filterTokenRParen
+	filterTokenLBracket
+	filterTokenRBracket
+	filterTokenComma
+	filterTokenOperator
+	filterTokenAnd
+	filterTokenOr
+	filterTokenIn
+	filterTokenNot
+	filterTokenField
+	filterTokenWord
+	filterTokenString
+)
+
+type filterToken struct {
+	kind filterTokenKind
+	text string
+	pos  int
+}
+
+func (t filterToken) describe() string {
+	switch t.kind {
+	case filterTokenEOF:
+		return "end of filter"
+	case filterTokenString:
+		return fmt.Sprintf("'%v'", t.text)
+	case filterTokenField:
+		return fmt.Sprintf("{%v}", t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// filterError is a syntax or type error found while compiling a filter, pos is the 1-based offset in the filter string
+type filterError struct {
+	pos int
+	msg string
+}
+
+func (e *filterError) Error() string {
+	return fmt.Sprintf("position %v: %v", e.pos, e.msg)
+}
+
+func newFilterError(pos int, format string, args ...interface{}) *filterError {
+	return &filterError{pos: pos, msg: fmt.Sprintf(format, args...)}
+}
+
+func isFilterWordChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.'
+}
+
+// tokenizeFilter splits a filter string into tokens. Both the SQL-like (value > 10 and to = 0xaa) and
+// the {field} ({value} > 10 && {to} == '0xaa') syntaxes share the same tokens
+func tokenizeFilter(filter string) ([]filterToken, error) {
+	var tokens []filterToken
+	for i := 0; i < len(filter); {
+		c := filter[i]
+		pos := i + 1
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, filterToken{kind: filterTokenLParen, text: "(", pos: pos})
+			i++
+		case c == ')':
+			tokens = append(tokens, filterToken{kind: filterTokenRParen, text: ")", pos: pos})
+			i++
+		case c == '[':
+			tokens = append(tokens, filterToken{kind: filterTokenLBracket, text: "[", pos: pos})
+			i++
+		case c == ']':
+			tokens = append(tokens, filterToken{kind: filterTokenRBracket, text: "]", pos: pos})
+			i++
+		case c == ',':
+			tokens = append(tokens, filterToken{kind: filterTokenComma, text: ",", pos: pos})
+			i++
+		case c == '=' || c == '<' || c == '>':
+			op := string(c)
+			if i+1 < len(filter) && filter[i+1] == '=' {
+				op += "="
+			}
+			tokens = append(tokens, filterToken{kind: filterTokenOperator, text: op, pos: pos})
+			i += len(op)
+		case c == '!':
+			if i+1 >= len(filter) || filter[i+1] != '=' {
+				return nil, newFilterError(pos, "unexpected '!', did you mean '!='?")
+			}
+			tokens = append(tokens, filterToken{kind: filterTokenOperator, text: "!=", pos: pos})
+			i += 2
+		case c == '&' || c == '|':
+			if i+1 >= len(filter) || filter[i+1] != c {
+				return nil, newFilterError(pos, "unexpected '%c', did you mean '%c%c'?", c, c, c)
+			}
+			kind := filterTokenAnd
+			if c == '|' {
+				kind = filterTokenOr
+			}
+			tokens = append(tokens, filterToken{kind: kind, text: filter[i : i+2], pos: pos})
+			i += 2
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(filter[i+1:], c)
+			if end < 0 {
+				return nil, newFilterError(pos, "unterminated string")
+			}
+			tokens = append(tokens, filterToken{kind: filterTokenString, text: filter[i+1 : i+1+end], pos: pos})
+			i += end + 2
+		case c == '{':
+			end := strings.IndexByte(filter[i+1:], '}')
+			if end < 0 {
+				return nil, newFilterError(pos, "missing '}'")
+			}
+			name := strings.TrimSpace(filter[i+1 : i+1+end])
+			if name == "" {
+				return nil, newFilterError(pos, "empty field name")
+			}
+			tokens = append(tokens, filterToken{kind: filterTokenField, text: name, pos: pos})
+			i += end + 2
+		case isFilterWordChar(c):
+			start := i
+			for i < len(filter) && isFilterWordChar(filter[i]) {
+				i++
+			}
+			word := filter[start:i]
+			kind := filterTokenWord
+			switch strings.ToLower(word) {
+			case "and":
+				kind = filterTokenAnd
+			case "or":
+				kind = filterTokenOr
+			case "in":
+				kind = filterTokenIn
+			case "not":
+				kind = filterTokenNot
+			}
+			tokens = append(tokens, filterToken{kind: kind, text: word, pos: pos})
+		default:
+			return nil, newFilterError(pos, "unexpected character '%c'", c)
+		}
+	}
+
+	return append(tokens, filterToken{kind: filterTokenEOF, pos: len(filter) + 1}), nil
+}
+
+// filterParser is a recursive descent parser producing a type checked filter AST. Precedence from low to high is
+// or, and, comparison; parentheses group explicitly
+type filterParser struct {
+	tokens []filterToken
+	next   int
+	schema *filterSchema
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.next]
+}
+
+func (p *filterParser) advance() filterToken {
+	t := p.tokens[p.next]
+	if t.kind != filterTokenEOF {
+		p.next++
+	}
+	return t
+}
+
+func (p *filterParser) expect(kind filterTokenKind, what string) (filterToken, error) {
+	t := p.advance()
+	if t.kind != kind {
+		return t, newFilterError(t.pos, "expected %v, got %v", what, t.describe())
+	}
+	return t, nil
+}
+
+func (p *filterParser) parse() (filterNode, error) {
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != filterTokenEOF {
+		return nil, newFilterError(t.pos, "unexpected %v, expected 'and', 'or' or end of filter", t.describe())
+	}
+	return node, nil
+}
+
+func (p *filterParser) parseOr() (filterNode, error) {
+	lhs, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == filterTokenOr {
+		p.advance()
+		rhs, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		lhs = &filterOr{lhs: lhs, rhs: rhs}
+	}
+	return lhs, nil
+}
+
+func (p *filterParser) parseAnd() (filterNode, error) {
+	lhs, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == filterTokenAnd {
+		p.advance()
+		rhs, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		lhs = &filterAnd{lhs: lhs, rhs: rhs}
+	}
+	return lhs, nil
+}
+
+func (p *filterParser) parseUnary() (filterNode, error) {
+	t := p.peek()
+	if t.kind == filterTokenLParen {
+		p.advance()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err = p.expect(filterTokenRParen, "')'"); err != nil {
+			return nil, err
+		}
+		return &filterParen{inner: inner, start: t.pos}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (filterNode, error) {
+	t := p.advance()
+	if t.kind != filterTokenField && t.kind != filterTokenWord {
+		return nil, newFilterError(t.pos, "expected a field name, got %v", t.describe())
+	}
+	name, err := p.parseFieldName(t)
+	if err != nil {
+		return nil, err
+	}
+	field, err := p.schema.lookup(name, t.pos)
+	if err != nil {
+		return nil, err
+	}
+
+	op := p.advance()
+	switch op.kind {
+	case filterTokenOperator:
+		if op.text == "=" {
+			op.text = "=="
+		}
+		if (op.text != "==" && op.text != "!=") && field.kind != filterKindNumber {
+			return nil, newFilterError(op.pos, "operator %v is not supported for %v field %v", op.text, field.kind, field.name)
+		}
+		value, err := p.parseValue(field)
+		if err != nil {
+			return nil, err
+		}
+		return &filterCompare{field: field, op: op.text, value: value, start: t.pos}, nil
+	case filterTokenNot:
+		if in := p.advance(); in.kind != filterTokenIn {
+			return nil, newFilterError(in.pos, "expected 'in' after 'not', got %v", in.describe())
+		}
+		values, err := p.parseList(field)
+		if err != nil {
+			return nil, err
+		}
+		return &filterIn{field: field, values: values, negate: true, start: t.pos}, nil
+	case filterTokenIn:
+		values, err := p.parseList(field)
+		if err != nil {
+			return nil, err
+		}
+		return &filterIn{field: field, values: values, start: t.pos}, nil
+	default:
+		return nil, newFilterError(op.pos, "expected an operator after %v, got %v", field.name, op.describe())
+	}
+}
+
+// parseFieldName reads the element and component selectors following a field name, e.g. swap.path[0] or fill.orders[1].maker
+func (p *filterParser) parseFieldName(t filterToken) (string, error) {
+	name := t.text
+	if t.kind == filterTokenField {
+		return name, nil
+	}
+	for {
+		next := p.peek()
+		switch {
+		case next.kind == filterTokenLBracket:
+			p.advance()
+			index, err := p.expect(filterTokenWord, "an index")
+			if err != nil {
+				return "", err
+			}
+			if _, err = p.expect(filterTokenRBracket, "']'"); err != nil {
+				return "", err
+			}
+			name += "[" + index.text + "]"
+		case next.kind == filterTokenWord && strings.HasPrefix(next.text, "."):
+			p.advance()
+			name += next.text
+		default:
+			return name, nil
+		}
+	}
+}
+
+func (p *filterParser) parseList(field *filterField) ([]filterValue, error) {
+	if _, err := p.expect(filterTokenLBracket, "'['"); err != nil {
+		return nil, err
+	}
+	var values []filterValue
+	for {
+		value, err := p.parseValue(field)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		t := p.advance()
+		if t.kind == filterTokenRBracket {
+			return values, nil
+		}
+		if t.kind != filterTokenComma {
+			return nil, newFilterError(t.pos, "expected ',' or ']', got %v", t.describe())
+		}
+	}
+}
+
+// parseValue reads a literal and converts it to the type of the field it is compared with
+func (p *filterParser) parseValue(field *filterField) (filterValue, error) {
+	t := p.advance()
+	if t.kind != filterTokenWord && t.kind != filterTokenString {
+		return filterValue{}, newFilterError(t.pos, "expected a value for %v, got %v", field.name, t.describe())
+	}
+
+	switch field.kind {
+	case filterKindNumber:
+		n, ok := parseFilterNumber(t.text)
+		if !ok {
+			return filterValue{}, newFilterError(t.pos, "%v is not a valid number for field %v", t.describe(), field.name)
+		}
+		return filterValue{kind: filterKindNumber, number: n}, nil
+	case filterKindBool:
+		b, err := strconv.ParseBool(strings.ToLower(t.text))
+		if err != nil {
+			return filterValue{}, newFilterError(t.pos, "%v is not a valid bool for field %v", t.describe(), field.name)
+		}
+		return filterValue{kind: filterKindBool, boolean: b}, nil
+	case filterKindString:
+		return filterValue{kind: filterKindString, str: t.text}, nil
+	default:
+		s := strings.ToLower(strings.TrimSpace(t.text))
+		if !strings.HasPrefix(s, "0x") {
+			s = "0x" + s
+		}
+		for _, c := range s[2:] {
+			if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+				return filterValue{}, newFilterError(t.pos, "%v is not a valid hex value for field %v", t.describe(), field.name)
+			}
+		}
+		return filterValue{kind: filterKindHex, str: s}, nil
+	}
+}
+
+// parseFilterNumber accepts decimal, 0x-prefixed hex and integral scientific notation (1e18, 1.5e9)
+func parseFilterNumber(s string) (*big.Int, bool) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(strings.ToLower(s), "0x") {
+		return new(big.Int).SetString(s[2:], 16)
+	}
+	if n, ok := new(big.Int).SetString(s, 10); ok {
+		return n, true
+	}
+	f, ok := new(big.Float).SetPrec(512).SetString(s)
+	if !ok || !f.IsInt() || f.Sign() < 0 {
+		return nil, false
+	}
+	n, _ := f.Int(nil)
+	return n, true
+}
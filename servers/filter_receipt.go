@@ -0,0 +1,242 @@
+package servers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const (
+	logFieldPrefix     = "logs."
+	logAddressField    = "logs.address"
+	decodedLogResult   = "decoded"
+	maxLogTopicsFilter = 4
+)
+
+// receiptFilterSchema describes the fields of txReceipts filters. Fields prefixed with logs. are matched against
+// each log of the receipt separately
+var receiptFilterSchema = newReceiptFilterSchema()
+
+func newReceiptFilterSchema() *filterSchema {
+	fields := []*filterField{
+		{name: "to", kind: filterKindHex},
+		{name: "from", kind: filterKindHex},
+		{name: "contract_address", kind: filterKindHex},
+		{name: "status", kind: filterKindNumber},
+		{name: logAddressField, kind: filterKindHex},
+	}
+	for i := 0; i < maxLogTopicsFilter; i++ {
+		fields = append(fields, &filterField{name: logTopicField(i), kind: filterKindHex})
+	}
+	return newFilterSchema("", nil, fields...)
+}
+
+func logTopicField(i int) string {
+	return "logs.topics[" + strconv.Itoa(i) + "]"
+}
+
+// validateReceiptFilters compiles a txReceipts filter
+func validateReceiptFilters(filters string) (*filterExpr, error) {
+	expr, err := compileFilter(filters, receiptFilterSchema)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing Filters: %v", err)
+	}
+	return expr, nil
+}
+
+func receiptFilterValues(receipt *types.TxReceipt) map[string]interface{} {
+	values := make(map[string]interface{})
+	if to, ok := receipt.To.(string); ok {
+		values["to"] = to
+	}
+	if from, ok := receipt.From.(string); ok {
+		values["from"] = from
+	}
+	if contractAddress, ok := receipt.ContractAddress.(string); ok {
+		values["contract_address"] = contractAddress
+	}
+	if status, err := hexutil.DecodeBig(receipt.Status); err == nil {
+		values["status"] = status
+	}
+	return values
+}
+
+// matchTxReceipt evaluates the filter against a receipt. If the filter references log fields, the receipt
+// matches when the filter matches together with at least one of its logs, and only these logs are returned
+func matchTxReceipt(expr *filterExpr, receipt *types.TxReceipt) (bool, []interface{}) {
+	values := receiptFilterValues(receipt)
+
+	logFields := false
+	for _, arg := range expr.Args() {
+		if strings.HasPrefix(arg, logFieldPrefix) {
+			logFields = true
+			break
+		}
+	}
+	if !logFields {
+		return expr.Evaluate(values), receipt.Logs
+	}
+
+	var logs []interface{}
+	for _, receiptLog := range receipt.Logs {
+		logMap, ok := receiptLog.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		logValues := make(map[string]interface{}, len(values)+maxLogTopicsFilter+1)
+		for k, v := range values {
+			logValues[k] = v
+		}
+		if address, ok := logMap["address"].(string); ok {
+			logValues[logAddressField] = address
+		}
+		for i, topic := range interfaceToStringArray(logMap["topics"]) {
+			if i == maxLogTopicsFilter {
+				break
+			}
+			logValues[logTopicField(i)] = topic
+		}
+
+		if expr.Evaluate(logValues) {
+			logs = append(logs, receiptLog)
+		}
+	}
+	return len(logs) > 0, logs
+}
+
+// filterTxReceipts applies the filter and the log decoding of a txReceipts subscription to a notification
+func filterTxReceipts(clientReq *clientReq, notification *types.TxReceiptsNotification) *types.TxReceiptsNotification {
+	if clientReq.expr == nil && clientReq.events == nil {
+		return notification.WithFields(clientReq.includes).(*types.TxReceiptsNotification)
+	}
+
+	decodeLogs := clientReq.events != nil && utils.Exists("logs", clientReq.includes)
+	filtered := &types.TxReceiptsNotification{Receipts: make([]*types.TxReceipt, 0, len(notification.Receipts))}
+	for _, receipt := range notification.Receipts {
+		logs := receipt.Logs
+		if clientReq.expr != nil {
+			var matched bool
+			matched, logs = matchTxReceipt(clientReq.expr, receipt)
+			if !matched {
+				continue
+			}
+		}
+		if decodeLogs {
+			logs = clientReq.events.decodeLogs(logs)
+		}
+
+		r := *receipt
+		r.Logs = logs
+		filtered.Receipts = append(filtered.Receipts, &r)
+	}
+	return filtered.WithFields(clientReq.includes).(*types.TxReceiptsNotification)
+}
+
+// eventABI decodes receipt logs with the events of a txReceipts subscription ABI
+type eventABI struct {
+	abi *abi.ABI
+}
+
+func newEventABI(option json.RawMessage, registry *abiRegistry) (*eventABI, error) {
+	contractABI, err := subscriptionABI(option, registry)
+	if err != nil || contractABI == nil {
+		return nil, err
+	}
+	if len(contractABI.Events) == 0 {
+		return nil, errors.New("abi does not contain any events")
+	}
+	return &eventABI{abi: contractABI}, nil
+}
+
+// decodeLogs returns copies of the logs with the decoded event added to the ones emitted by an event of the ABI.
+// The logs of the notification are shared by all subscriptions, so they are never modified
+func (e *eventABI) decodeLogs(logs []interface{}) []interface{} {
+	decodedLogs := make([]interface{}, 0, len(logs))
+	for _, receiptLog := range logs {
+		logMap, ok := receiptLog.(map[string]interface{})
+		if !ok {
+			decodedLogs = append(decodedLogs, receiptLog)
+			continue
+		}
+		decoded := e.decode(logMap)
+		if decoded == nil {
+			decodedLogs = append(decodedLogs, receiptLog)
+			continue
+		}
+
+		decodedLog := make(map[string]interface{}, len(logMap)+1)
+		for k, v := range logMap {
+			decodedLog[k] = v
+		}
+		decodedLog[decodedLogResult] = decoded
+		decodedLogs = append(decodedLogs, decodedLog)
+	}
+	return decodedLogs
+}
+
+// decode returns the event name and the JSON friendly arguments of a log, nil if it is not emitted by an event of the ABI
+func (e *eventABI) decode(logMap map[string]interface{}) map[string]interface{} {
+	topics := interfaceToStringArray(logMap["topics"])
+	if len(topics) == 0 {
+		return nil
+	}
+	event, err := e.abi.EventByID(common.HexToHash(topics[0]))
+	if err != nil {
+		return nil
+	}
+
+	data, err := hexutil.Decode(interfaceToString(logMap["data"]))
+	if err != nil {
+		return nil
+	}
+	nonIndexed, err := event.Inputs.NonIndexed().Unpack(data)
+	if err != nil {
+		return nil
+	}
+
+	args := make(map[string]interface{}, len(event.Inputs))
+	topic, next := 1, 0
+	for i, arg := range event.Inputs {
+		name := arg.Name
+		if name == "" {
+			name = strconv.Itoa(i)
+		}
+		if arg.Indexed {
+			if topic >= len(topics) {
+				return nil
+			}
+			args[name] = indexedLogValue(arg.Type, common.HexToHash(topics[topic]))
+			topic++
+			continue
+		}
+		args[name] = abiJSONValue(arg.Type, reflect.ValueOf(nonIndexed[next]))
+		next++
+	}
+	return map[string]interface{}{
+		"event": event.Name,
+		"args":  args,
+	}
+}
+
+// indexedLogValue decodes an indexed event argument. Topics of dynamic and composite types only hold the hash of the value
+func indexedLogValue(t abi.Type, topic common.Hash) interface{} {
+	switch t.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+		return topic.Hex()
+	}
+	values, err := abi.Arguments{{Type: t}}.Unpack(topic.Bytes())
+	if err != nil || len(values) == 0 {
+		return topic.Hex()
+	}
+	return abiJSONValue(t, reflect.ValueOf(values[0]))
+}
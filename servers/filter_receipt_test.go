@@ -0,0 +1,179 @@
+package servers
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testPairABI = `[
+		{"anonymous":false,"inputs":[{"indexed":true,"name":"sender","type":"address"},{"indexed":false,"name":"amount0In","type":"uint256"},{"indexed":false,"name":"amount1In","type":"uint256"},{"indexed":false,"name":"amount0Out","type":"uint256"},{"indexed":false,"name":"amount1Out","type":"uint256"},{"indexed":true,"name":"to","type":"address"}],"name":"Swap","type":"event"},
+		{"anonymous":false,"inputs":[{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"address"},{"indexed":false,"name":"value","type":"uint256"}],"name":"Transfer","type":"event"}
+	]`
+	testPairAddress   = "0xb4e16d0168e52d35cacd2c6185b44281ec28c9dc"
+	testTokenAddress  = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	testSenderAddress = "0x7a250d5630b4cf539739df2c5dacb4c659f2488d"
+	testToAddress     = "0x3328f7f4a1d1c57c35df56bbf0c9dcafca309c49"
+)
+
+func testAddressTopic(address string) string {
+	return common.BytesToHash(common.HexToAddress(address).Bytes()).Hex()
+}
+
+func testReceiptLog(t *testing.T, pairABI abi.ABI, address, event string, indexed []string, args ...interface{}) map[string]interface{} {
+	data, err := pairABI.Events[event].Inputs.NonIndexed().Pack(args...)
+	require.NoError(t, err)
+
+	topics := []interface{}{pairABI.Events[event].ID.Hex()}
+	for _, topic := range indexed {
+		topics = append(topics, testAddressTopic(topic))
+	}
+	return map[string]interface{}{
+		"address": address,
+		"topics":  topics,
+		"data":    hexutil.Encode(data),
+	}
+}
+
+func testTxReceipts(t *testing.T) (*types.TxReceiptsNotification, abi.ABI) {
+	pairABI, err := abi.JSON(bytes.NewReader([]byte(testPairABI)))
+	require.NoError(t, err)
+
+	swapLog := testReceiptLog(t, pairABI, testPairAddress, "Swap", []string{testSenderAddress, testToAddress},
+		big.NewInt(0), big.NewInt(1000), big.NewInt(2000), big.NewInt(0))
+	transferLog := testReceiptLog(t, pairABI, testTokenAddress, "Transfer", []string{testPairAddress, testToAddress}, big.NewInt(2000))
+
+	swap := types.NewTxReceipt(map[string]interface{}{
+		"blockHash":       "0x01",
+		"from":            testToAddress,
+		"to":              testSenderAddress,
+		"contractAddress": nil,
+		"status":          "0x1",
+		"logs":            []interface{}{transferLog, swapLog},
+		"transactionHash": "0x02",
+	}, "0x2")
+	failed := types.NewTxReceipt(map[string]interface{}{
+		"blockHash":       "0x01",
+		"from":            testToAddress,
+		"to":              testTokenAddress,
+		"contractAddress": nil,
+		"status":          "0x0",
+		"logs":            []interface{}{},
+		"transactionHash": "0x03",
+	}, "0x2")
+	return types.NewTxReceiptsNotification([]*types.TxReceipt{swap, failed}), pairABI
+}
+
+func TestReceiptFilter(t *testing.T) {
+	notification, pairABI := testTxReceipts(t)
+	swapTopic := pairABI.Events["Swap"].ID.Hex()
+
+	tests := []struct {
+		filter   string
+		receipts []string
+		logs     []int
+	}{
+		{filter: "status = 0", receipts: []string{"0x03"}, logs: []int{0}},
+		{filter: "{status} == 1 or {to} == '" + testTokenAddress + "'", receipts: []string{"0x02", "0x03"}, logs: []int{2, 0}},
+		{filter: "from = " + testToAddress, receipts: []string{"0x02", "0x03"}, logs: []int{2, 0}},
+		{filter: "logs.topics[0] = " + swapTopic, receipts: []string{"0x02"}, logs: []int{1}},
+		{filter: "logs.address in [" + testPairAddress + ", " + testTokenAddress + "]", receipts: []string{"0x02"}, logs: []int{2}},
+		{filter: "logs.address = " + testTokenAddress + " and logs.topics[2] = " + testAddressTopic(testToAddress), receipts: []string{"0x02"}, logs: []int{1}},
+		// the conditions on log fields have to match the same log
+		{filter: "logs.address = " + testTokenAddress + " and logs.topics[0] = " + swapTopic, receipts: nil},
+		{filter: "logs.topics[3] = " + swapTopic, receipts: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			expr, err := validateReceiptFilters(tt.filter)
+			require.NoError(t, err)
+
+			req := &clientReq{includes: validTxReceiptParams, expr: expr}
+			filtered := filterTxReceipts(req, notification)
+			require.Len(t, filtered.Receipts, len(tt.receipts))
+			for i, receipt := range filtered.Receipts {
+				assert.Equal(t, tt.receipts[i], receipt.TransactionHash)
+				assert.Len(t, receipt.Logs, tt.logs[i])
+			}
+		})
+	}
+
+	// the logs of the notification are shared with the other subscriptions
+	assert.Len(t, notification.Receipts[0].Logs, 2)
+}
+
+func TestReceiptFilterErrors(t *testing.T) {
+	_, err := validateReceiptFilters("logs.topics[4] = 0x01")
+	assert.ErrorContains(t, err, "unknown field logs.topics[4]")
+
+	_, err = validateReceiptFilters("gas_price > 1")
+	assert.ErrorContains(t, err, "unknown field gas_price")
+
+	_, err = validateReceiptFilters("logs.address > 0x01")
+	assert.Error(t, err)
+}
+
+func TestReceiptLogDecoding(t *testing.T) {
+	notification, _ := testTxReceipts(t)
+
+	events, err := newEventABI(json.RawMessage(testPairABI), nil)
+	require.NoError(t, err)
+
+	expr, err := validateReceiptFilters("status = 1")
+	require.NoError(t, err)
+	req := &clientReq{includes: []string{"transaction_hash", "logs"}, expr: expr, events: events}
+
+	filtered := filterTxReceipts(req, notification)
+	require.Len(t, filtered.Receipts, 1)
+	require.Len(t, filtered.Receipts[0].Logs, 2)
+
+	transfer := filtered.Receipts[0].Logs[0].(map[string]interface{})[decodedLogResult]
+	assert.Equal(t, map[string]interface{}{
+		"event": "Transfer",
+		"args": map[string]interface{}{
+			"from":  testPairAddress,
+			"to":    testToAddress,
+			"value": "2000",
+		},
+	}, transfer)
+
+	swap := filtered.Receipts[0].Logs[1].(map[string]interface{})[decodedLogResult]
+	assert.Equal(t, map[string]interface{}{
+		"event": "Swap",
+		"args": map[string]interface{}{
+			"sender":     testSenderAddress,
+			"to":         testToAddress,
+			"amount0In":  "0",
+			"amount1In":  "1000",
+			"amount0Out": "2000",
+			"amount1Out": "0",
+		},
+	}, swap)
+
+	_, decoded := notification.Receipts[0].Logs[0].(map[string]interface{})[decodedLogResult]
+	assert.False(t, decoded)
+
+	// the gRPC reply carries the decoded event as JSON
+	reply := generateTxReceiptReply(filtered.Receipts[0])
+	require.Len(t, reply.Logs, 2)
+	assert.JSONEq(t, `{"event":"Transfer","args":{"from":"`+testPairAddress+`","to":"`+testToAddress+`","value":"2000"}}`, reply.Logs[0].Decoded)
+
+	// without logs in the includes there is nothing to decode
+	req.includes = []string{"transaction_hash"}
+	filtered = filterTxReceipts(req, notification)
+	require.Len(t, filtered.Receipts, 1)
+	assert.Nil(t, filtered.Receipts[0].Logs)
+
+	_, err = newEventABI(json.RawMessage(testRouterABI), nil)
+	assert.EqualError(t, err, "abi does not contain any events")
+}
@@ -1,10 +1,20 @@
 package servers
 
 import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path"
 	"strings"
 	"testing"
 
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // pythonFiltersToGoFilters - contains available filters in python format and theirs go format filters
@@ -77,76 +87,251 @@ func TestFilter(t *testing.T) {
 
 	for pythonFormat, expectedGoFormat := range pythonFiltersToGoFilters {
 		s.Filters = pythonFormat
-		goFormatResult, exp, err := parseFilter(s.Filters)
-		assert.Equal(t, strings.ToLower(expectedGoFormat), strings.ToLower(goFormatResult))
-		assert.NoError(t, err)
-		assert.Nil(t, evaluateFilters(exp))
+		exp, err := parseFilter(s.Filters)
+		require.NoError(t, err, pythonFormat)
+		assert.Equal(t, strings.ToLower(expectedGoFormat), strings.ToLower(exp.String()))
+
+		// the canonical form is valid filter syntax as well
+		_, err = parseFilter(exp.String())
+		assert.NoError(t, err, exp.String())
 	}
 
 	for _, invalidFilters := range invalidPythonFilters {
 		s.Filters = invalidFilters
-		_, _, err := parseFilter(s.Filters)
-		assert.NotNil(t, err)
+		_, err := parseFilter(s.Filters)
+		assert.NotNil(t, err, invalidFilters)
+	}
+}
+
+func TestFilterErrorPosition(t *testing.T) {
+	tests := []struct {
+		filter string
+		pos    int
+	}{
+		{filter: "value > = 10000", pos: 9},
+		{filter: "value ! = 10000", pos: 7},
+		{filter: "(from = 0xaa", pos: 13},
+		{filter: "value > 100 and foo = 1", pos: 17},
+		{filter: "value > 0xzz", pos: 9},
+		{filter: "to > 0xaa", pos: 4},
+		{filter: "{value} > 1 && ({to} == '0xaa' || {method_id} ==)", pos: 49},
+		{filter: "value > 1.5", pos: 9},
+		{filter: "gas_price > 1 and max_fee_per_gas > 1", pos: 1},
+		{filter: "type = 2 and gas_price > 1", pos: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			_, err := parseFilter(tt.filter)
+			require.Error(t, err)
+			var filterErr *filterError
+			require.True(t, errors.As(err, &filterErr), err.Error())
+			assert.Equal(t, tt.pos, filterErr.pos, err.Error())
+		})
+	}
+}
+
+func TestFilterEvaluate(t *testing.T) {
+	to := common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+	legacyTx := ethtypes.NewTx(&ethtypes.LegacyTx{GasPrice: big.NewInt(30e9), Gas: 21000, To: &to, Value: big.NewInt(2e18), Data: []byte{0xa9, 0x05, 0x9c, 0xbb, 0x01}})
+	dynamicFeeTx := ethtypes.NewTx(&ethtypes.DynamicFeeTx{ChainID: big.NewInt(1), GasTipCap: big.NewInt(2e9), GasFeeCap: big.NewInt(50e9), Gas: 21000, To: &to, Value: big.NewInt(1)})
+
+	tests := []struct {
+		filter  string
+		legacy  bool
+		dynamic bool
+	}{
+		{filter: "gas_price > 21000000000", legacy: true, dynamic: false},
+		{filter: "max_priority_fee_per_gas >= 2e9", legacy: false, dynamic: true},
+		{filter: "(type == '0' && gas_price > 21000000000) || (type == '2' && max_priority_fee_per_gas > 1000000000)", legacy: true, dynamic: true},
+		{filter: "({type} == '0' && {gas_price} > 50000000000) || ({type} == '2' && {max_priority_fee_per_gas} > 1000000000)", legacy: false, dynamic: true},
+		{filter: "gas_price > 21000000000 or max_fee_per_gas > 60e9", legacy: true, dynamic: false},
+		{filter: "value > 1e18", legacy: true, dynamic: false},
+		{filter: "value = 1 and to = 0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", legacy: false, dynamic: true},
+		{filter: "method_id = a9059cbb", legacy: true, dynamic: false},
+		{filter: "method_id not in [a9059cbb, 095ea7b3]", legacy: false, dynamic: true},
+		{filter: "type in [0, 1] or chain_id = 5", legacy: true, dynamic: false},
+		{filter: "gas = 21000 AND to != 0xaa", legacy: true, dynamic: true},
+		// a field missing for the tx type is not equal to any value
+		{filter: "max_fee_per_gas != 5", legacy: true, dynamic: true},
+		{filter: "max_fee_per_gas = 5", legacy: false, dynamic: false},
+		{filter: "gas_price not in [1, 2]", legacy: true, dynamic: true},
+		{filter: "gas_price in [30e9]", legacy: true, dynamic: false},
+		{filter: "max_priority_fee_per_gas < 1 or gas_price != 30e9", legacy: false, dynamic: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			expr, err := parseFilter(tt.filter)
+			require.NoError(t, err)
+
+			legacy, err := types.NewEthTransaction(types.SHA256Hash{}, legacyTx, types.EmptySender)
+			require.NoError(t, err)
+			assert.Equal(t, tt.legacy, expr.Evaluate(legacy.Filters(expr.Args())))
+
+			dynamic, err := types.NewEthTransaction(types.SHA256Hash{}, dynamicFeeTx, types.EmptySender)
+			require.NoError(t, err)
+			assert.Equal(t, tt.dynamic, expr.Evaluate(dynamic.Filters(expr.Args())))
+		})
 	}
 }
 
-func TestIsCorrectGasPriceFilters(t *testing.T) {
+func TestFilterContractCreation(t *testing.T) {
+	contractCreation, err := types.NewEthTransaction(types.SHA256Hash{}, ethtypes.NewTx(&ethtypes.LegacyTx{GasPrice: big.NewInt(30e9), Gas: 100000}), types.EmptySender)
+	require.NoError(t, err)
+
+	for filter, expected := range map[string]bool{
+		"to = 0x0":          false,
+		"to != 0xaa":        true,
+		"to in [0x0, 0xaa]": false,
+		"to not in [0xaa]":  true,
+	} {
+		expr, err := parseFilter(filter)
+		require.NoError(t, err)
+		assert.Equal(t, expected, expr.Evaluate(contractCreation.Filters(expr.Args())), filter)
+	}
+}
+
+func TestValidateFiltersTxFrom(t *testing.T) {
+	_, err := validateFilters("from = 0xaa", true, nil)
+	assert.NoError(t, err)
+
+	_, err = validateFilters("value > 1 and from = 0xaa", false, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "position 15: unknown field from")
+}
+
+const testRouterABI = `[
+	{"name":"swapExactTokensForTokens","type":"function","stateMutability":"nonpayable","inputs":[
+		{"name":"amountIn","type":"uint256"},{"name":"amountOutMin","type":"uint256"},{"name":"path","type":"address[]"},
+		{"name":"to","type":"address"},{"name":"deadline","type":"uint256"}],"outputs":[]},
+	{"name":"transfer","type":"function","stateMutability":"nonpayable","inputs":[
+		{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"name":"fill","type":"function","stateMutability":"nonpayable","inputs":[
+		{"name":"order","type":"tuple","components":[{"name":"maker","type":"address"},{"name":"partial","type":"bool"}]}],"outputs":[]}
+]`
+
+func TestFilterCalldata(t *testing.T) {
+	contractABI, err := abi.JSON(strings.NewReader(testRouterABI))
+	require.NoError(t, err)
+
+	weth := common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+	usdc := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	swapInput, err := contractABI.Pack("swapExactTokensForTokens", big.NewInt(5e18), big.NewInt(1), []common.Address{weth, usdc}, weth, big.NewInt(1))
+	require.NoError(t, err)
+	transferInput, err := contractABI.Pack("transfer", usdc, new(big.Int).Mul(big.NewInt(2), big.NewInt(1e18)))
+	require.NoError(t, err)
+	fillInput, err := contractABI.Pack("fill", struct {
+		Maker   common.Address
+		Partial bool
+	}{Maker: usdc, Partial: true})
+	require.NoError(t, err)
+
 	tests := []struct {
-		name     string
-		filters  []string
-		expected bool
+		filter   string
+		swap     bool
+		transfer bool
+		fill     bool
 	}{
-		{
-			name:     "gas_price and max_fee_per_gas exist, txType does not",
-			filters:  []string{"gas_price", "max_fee_per_gas"},
-			expected: false,
-		},
-		{
-			name:     "gas_price and max_priority_fee_per_gas exist, txType does not",
-			filters:  []string{"gas_price", "max_priority_fee_per_gas"},
-			expected: false,
-		},
-		{
-			name:     "gas_price and max_priority_fee_per_gas exist, txType does not",
-			filters:  []string{"gas_price", "max_priority_fee_per_gas", "type"},
-			expected: true,
-		},
-		{
-			name:     "gas_price exists, max_fee_per_gas and txType do not",
-			filters:  []string{"gas_price"},
-			expected: true,
-		},
-		{
-			name:     "gas_price exists, max_priority_fee_per_gas and txType do not",
-			filters:  []string{"gas_price"},
-			expected: true,
-		},
-		{
-			name:     "gas_price and txType exist, max_fee_per_gas does not",
-			filters:  []string{"gas_price", "type"},
-			expected: true,
-		},
-		{
-			name:     "gas_price and txType exist, max_priority_fee_per_gas does not",
-			filters:  []string{"gas_price", "type"},
-			expected: true,
-		},
-		{
-			name:     "no gas price filters",
-			filters:  []string{"type"},
-			expected: true,
-		},
-		{
-			name:     "empty filters",
-			filters:  []string{},
-			expected: true,
-		},
+		{filter: "swapExactTokensForTokens.path[0] in [0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2]", swap: true},
+		{filter: "swapExactTokensForTokens.path[1] = 0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", swap: false},
+		{filter: "swapExactTokensForTokens.path[5] = 0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", swap: false},
+		{filter: "transfer.amount > 1e18 or swapExactTokensForTokens.amountIn >= 5e18", swap: true, transfer: true},
+		{filter: "{transfer.amount} > 1e18 && {transfer.to} == '0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48'", transfer: true},
+		{filter: "fill.order.partial = true and fill.order.maker = 0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", fill: true},
+		{filter: "value > 0 and transfer.amount > 0", transfer: true},
 	}
+
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := IsCorrectGasPriceFilters(tt.filters); got != tt.expected {
-				t.Errorf("IsCorrectGasPriceFilters() = %v, expected %v", got, tt.expected)
+		t.Run(tt.filter, func(t *testing.T) {
+			calldata, err := newCalldataABI(json.RawMessage(testRouterABI), nil)
+			require.NoError(t, err)
+			expr, err := validateFilters(tt.filter, true, calldata)
+			require.NoError(t, err)
+
+			for input, expected := range map[string]bool{string(swapInput): tt.swap, string(transferInput): tt.transfer, string(fillInput): tt.fill} {
+				ethTx, err := types.NewEthTransaction(types.SHA256Hash{}, ethtypes.NewTx(&ethtypes.LegacyTx{To: &weth, Value: big.NewInt(1), Data: []byte(input)}), types.EmptySender)
+				require.NoError(t, err)
+				values := ethTx.Filters(expr.Args())
+				calldata.addValues(values, ethTx.Data(), expr.Args())
+				assert.Equal(t, expected, expr.Evaluate(values))
 			}
 		})
 	}
+
+	decoded := func() map[string]interface{} {
+		calldata, err := newCalldataABI(json.RawMessage(testRouterABI), nil)
+		require.NoError(t, err)
+		return calldata.decode(swapInput)
+	}()
+	require.NotNil(t, decoded)
+	assert.Equal(t, "swapExactTokensForTokens", decoded["method"])
+	args := decoded["args"].(map[string]interface{})
+	assert.Equal(t, "5000000000000000000", args["amountIn"])
+	assert.Equal(t, []interface{}{"0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"}, args["path"])
+}
+
+func TestFilterCalldataErrors(t *testing.T) {
+	tests := []struct {
+		filter string
+		err    string
+	}{
+		{filter: "swap.amount > 1", err: "position 1: method swap is not found in abi"},
+		{filter: "value > 1 and transfer.value > 1", err: "position 15: method transfer has no argument value"},
+		{filter: "swapExactTokensForTokens.path > 1", err: "position 1: field swapexacttokensfortokens.path of type address[] can not be filtered"},
+		{filter: "transfer.amount = 0xaa[0]", err: "position 23: unexpected \"[\""},
+		{filter: "transfer.to > 0xaa", err: "position 13: operator > is not supported for hex field transfer.to"},
+		{filter: "fill.order.partial = maybe", err: "position 22: \"maybe\" is not a valid bool"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			calldata, err := newCalldataABI(json.RawMessage(testRouterABI), nil)
+			require.NoError(t, err)
+			_, err = validateFilters(tt.filter, true, calldata)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func TestABIRegistry(t *testing.T) {
+	datadir := t.TempDir()
+	require.NoError(t, os.MkdirAll(path.Join(datadir, abiDirName), 0755))
+	require.NoError(t, os.WriteFile(path.Join(datadir, abiDirName, "UniswapV2Router.json"), []byte(testRouterABI), 0644))
+
+	registry, err := loadABIRegistry(datadir)
+	require.NoError(t, err)
+
+	calldata, err := newCalldataABI(json.RawMessage(`"uniswapv2router"`), registry)
+	require.NoError(t, err)
+	require.NotNil(t, calldata)
+	_, err = validateFilters("transfer.amount > 1", true, calldata)
+	assert.NoError(t, err)
+
+	_, err = newCalldataABI(json.RawMessage(`"unknown"`), registry)
+	assert.EqualError(t, err, "abi unknown is not found")
+
+	calldata, err = newCalldataABI(nil, registry)
+	assert.NoError(t, err)
+	assert.Nil(t, calldata)
+}
+
+func TestGRPCABIOption(t *testing.T) {
+	datadir := t.TempDir()
+	require.NoError(t, os.MkdirAll(path.Join(datadir, abiDirName), 0755))
+	require.NoError(t, os.WriteFile(path.Join(datadir, abiDirName, "UniswapV2Router.json"), []byte(testRouterABI), 0644))
+
+	registry, err := loadABIRegistry(datadir)
+	require.NoError(t, err)
+
+	for _, option := range []string{testRouterABI, "UniswapV2Router"} {
+		calldata, err := newCalldataABI(grpcABIOption(option), registry)
+		require.NoError(t, err)
+		require.NotNil(t, calldata)
+	}
+
+	calldata, err := newCalldataABI(grpcABIOption(""), registry)
+	assert.NoError(t, err)
+	assert.Nil(t, calldata)
 }
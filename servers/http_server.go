@@ -110,7 +110,7 @@ func (s *HTTPServer) httpRPCHandler(w http.ResponseWriter, r *http.Request) {
 			EnforcePayout:   bundlePayload[0].EnforcePayout,
 		}
 
-		mevBundle, bundleHash, err := mevBundleFromRequest(&payload, s.feedManager.networkNum)
+		mevBundle, bundleHash, err := mevBundleFromRequest(&payload, s.feedManager.networkNum, s.feedManager.accountModel.AccountID)
 		var result interface{}
 		if payload.UUID == "" {
 			result = GatewayBundleResponse{BundleHash: bundleHash}
@@ -165,7 +165,7 @@ func (s *HTTPServer) httpRPCHandler(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
-		mevBundle, bundleHash, err := mevBundleFromRequest(&params, s.feedManager.networkNum)
+		mevBundle, bundleHash, err := mevBundleFromRequest(&params, s.feedManager.networkNum, s.feedManager.accountModel.AccountID)
 		var result interface{}
 		if params.UUID == "" {
 			result = GatewayBundleResponse{BundleHash: bundleHash}
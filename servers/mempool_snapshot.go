@@ -0,0 +1,301 @@
+package servers
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/bloXroute-Labs/gateway/v2/protobuf"
+	"github.com/bloXroute-Labs/gateway/v2/services"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// mempoolSnapshotTTL is how long a snapshot is kept after its last page was requested
+	mempoolSnapshotTTL = time.Minute
+	// maxMempoolSnapshots bounds the snapshots kept for paging, each of them references all the matching transactions
+	maxMempoolSnapshots = 16
+	// maxAccountMempoolSnapshots bounds the snapshots kept for paging for an account
+	maxAccountMempoolSnapshots = 2
+	defaultMempoolPageSize     = 100
+	maxMempoolPageSize         = 1000
+)
+
+var (
+	errMempoolCursorNotFound   = errors.New("cursor is unknown or expired, request a new snapshot without a cursor")
+	errTooManyMempoolSnapshots = errors.New("too many mempool snapshots are paged at the same time, try again later")
+	errTooManyAccountSnapshots = fmt.Errorf("the account already pages %v mempool snapshots, try again once they expire", maxAccountMempoolSnapshots)
+)
+
+// MempoolTx is a pending transaction of a mempool snapshot
+type MempoolTx struct {
+	Transaction *types.NewTransactionNotification
+	// EffectiveTip is the tip per gas paid to the block producer at the base fee of the latest block
+	EffectiveTip *big.Int
+}
+
+// MempoolPage is a page of a mempool snapshot
+type MempoolPage struct {
+	Txs []MempoolTx
+	// NextCursor requests the following page, empty on the last page
+	NextCursor   string
+	Total        int
+	SnapshotTime time.Time
+}
+
+type mempoolSnapshot struct {
+	id         string
+	accountID  types.AccountID
+	txs        []MempoolTx
+	createdAt  time.Time
+	lastAccess time.Time
+}
+
+// MempoolSnapshots serves the pending transactions of the gateway matching a filter, sorted by effective tip. The
+// matching transactions are captured when the first page is requested, so the following pages are stable while the
+// mempool changes. A snapshot is kept for the account which requested it, only if it has more than one page
+type MempoolSnapshots struct {
+	clock                 utils.Clock
+	txStore               services.TxStore
+	pendingNonces         *services.PendingNonceTracker
+	txFromFieldIncludable bool
+
+	lock      sync.Mutex
+	baseFee   *big.Int
+	snapshots map[string]*mempoolSnapshot
+}
+
+// NewMempoolSnapshots creates MempoolSnapshots of the transactions of txStore. The transactions whose nonce is
+// confirmed by the pending nonces are left out, the pending nonces can be nil
+func NewMempoolSnapshots(clock utils.Clock, txStore services.TxStore, pendingNonces *services.PendingNonceTracker, txFromFieldIncludable bool) *MempoolSnapshots {
+	return &MempoolSnapshots{
+		clock:                 clock,
+		txStore:               txStore,
+		pendingNonces:         pendingNonces,
+		txFromFieldIncludable: txFromFieldIncludable,
+		snapshots:             make(map[string]*mempoolSnapshot),
+	}
+}
+
+// OnBlock records the base fee of the block, which the effective tips of the following snapshots are computed with
+func (m *MempoolSnapshots) OnBlock(block *ethtypes.Block) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.baseFee = block.BaseFee()
+}
+
+// Page returns the page of the snapshot of the account the cursor points to, or the first page of a new snapshot of the
+// transactions matching the filters if the cursor is empty. The filters of a snapshot apply to all of its pages
+func (m *MempoolSnapshots) Page(accountID types.AccountID, filters string, cursor string, limit int) (MempoolPage, error) {
+	if limit <= 0 {
+		limit = defaultMempoolPageSize
+	}
+	if limit > maxMempoolPageSize {
+		return MempoolPage{}, fmt.Errorf("limit %v exceeds the maximum page size %v", limit, maxMempoolPageSize)
+	}
+
+	var (
+		snapshot *mempoolSnapshot
+		offset   int
+		err      error
+	)
+	if cursor == "" {
+		snapshot, err = m.newSnapshot(accountID, filters)
+	} else {
+		snapshot, offset, err = m.snapshot(accountID, cursor)
+	}
+	if err != nil {
+		return MempoolPage{}, err
+	}
+
+	end := offset + limit
+	if end > len(snapshot.txs) {
+		end = len(snapshot.txs)
+	}
+	page := MempoolPage{
+		Txs:          snapshot.txs[offset:end],
+		Total:        len(snapshot.txs),
+		SnapshotTime: snapshot.createdAt,
+	}
+	if end < len(snapshot.txs) {
+		if cursor == "" {
+			if err = m.store(snapshot); err != nil {
+				return MempoolPage{}, err
+			}
+		}
+		page.NextCursor = snapshot.id + ":" + strconv.Itoa(end)
+	}
+	return page, nil
+}
+
+// PageProtobuf returns the page as a gRPC reply
+func (m *MempoolSnapshots) PageProtobuf(accountID types.AccountID, filters string, cursor string, limit int) (*pb.MempoolSnapshotReply, error) {
+	page, err := m.Page(accountID, filters, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &pb.MempoolSnapshotReply{
+		Txs:          make([]*pb.MempoolTx, 0, len(page.Txs)),
+		NextCursor:   page.NextCursor,
+		Total:        uint32(page.Total),
+		SnapshotTime: page.SnapshotTime.UnixNano(),
+	}
+	for _, tx := range page.Txs {
+		pbTx := makeTransaction(tx.Transaction, m.txFromFieldIncludable)
+		pbTx.Time = tx.Transaction.AddTime().UnixNano()
+		reply.Txs = append(reply.Txs, &pb.MempoolTx{Tx: pbTx, EffectiveTip: tx.EffectiveTip.String()})
+	}
+	return reply, nil
+}
+
+// newSnapshot captures the transactions matching the filters, the snapshot is stored only once it has a next page
+func (m *MempoolSnapshots) newSnapshot(accountID types.AccountID, filters string) (*mempoolSnapshot, error) {
+	clientReq := &clientReq{feed: types.PendingTxsFeed}
+	if filters != "" {
+		expr, err := validateFilters(filters, m.txFromFieldIncludable, nil)
+		if err != nil {
+			return nil, err
+		}
+		clientReq.expr = expr
+	}
+
+	now := m.clock.Now()
+	m.lock.Lock()
+	m.cleanExpired(now)
+	if err := m.checkCapacity(accountID); err != nil {
+		m.lock.Unlock()
+		return nil, err
+	}
+	baseFee := m.baseFee
+	m.lock.Unlock()
+
+	var txs []MempoolTx
+	for bxTx := range m.txStore.Iter() {
+		if !bxTx.HasContent() || bxTx.Flags().IsValidatorsOnly() || bxTx.Flags().IsNextValidator() {
+			continue
+		}
+		tx := types.CreateNewTransactionNotification(bxTx)
+		if err := tx.MakeBlockchainTransaction(); err != nil {
+			continue
+		}
+		ethTx, ok := tx.BlockchainTransaction.(*types.EthTransaction)
+		if !ok || m.confirmed(ethTx) {
+			continue
+		}
+		if !shouldSendTx(clientReq, tx, "", "") {
+			continue
+		}
+		txs = append(txs, MempoolTx{Transaction: tx, EffectiveTip: effectiveTip(ethTx, baseFee)})
+	}
+	sort.Slice(txs, func(i, j int) bool {
+		if c := txs[i].EffectiveTip.Cmp(txs[j].EffectiveTip); c != 0 {
+			return c > 0
+		}
+		if !txs[i].Transaction.AddTime().Equal(txs[j].Transaction.AddTime()) {
+			return txs[i].Transaction.AddTime().Before(txs[j].Transaction.AddTime())
+		}
+		return txs[i].Transaction.GetHash() < txs[j].Transaction.GetHash()
+	})
+
+	return &mempoolSnapshot{id: utils.GenerateUUID(), accountID: accountID, txs: txs, createdAt: now, lastAccess: now}, nil
+}
+
+// store keeps the snapshot for its following pages, the capacity is checked again since other snapshots may have been
+// stored while it was captured
+func (m *MempoolSnapshots) store(snapshot *mempoolSnapshot) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.cleanExpired(m.clock.Now())
+	if err := m.checkCapacity(snapshot.accountID); err != nil {
+		return err
+	}
+	m.snapshots[snapshot.id] = snapshot
+	return nil
+}
+
+func (m *MempoolSnapshots) checkCapacity(accountID types.AccountID) error {
+	if len(m.snapshots) >= maxMempoolSnapshots {
+		return errTooManyMempoolSnapshots
+	}
+	accountSnapshots := 0
+	for _, snapshot := range m.snapshots {
+		if snapshot.accountID == accountID {
+			accountSnapshots++
+		}
+	}
+	if accountSnapshots >= maxAccountMempoolSnapshots {
+		return errTooManyAccountSnapshots
+	}
+	return nil
+}
+
+func (m *MempoolSnapshots) snapshot(accountID types.AccountID, cursor string) (*mempoolSnapshot, int, error) {
+	id, offsetString, ok := strings.Cut(cursor, ":")
+	if !ok {
+		return nil, 0, fmt.Errorf("invalid cursor %v", cursor)
+	}
+	offset, err := strconv.Atoi(offsetString)
+	if err != nil || offset < 0 {
+		return nil, 0, fmt.Errorf("invalid cursor %v", cursor)
+	}
+
+	now := m.clock.Now()
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.cleanExpired(now)
+	snapshot, ok := m.snapshots[id]
+	if !ok || snapshot.accountID != accountID {
+		return nil, 0, errMempoolCursorNotFound
+	}
+	if offset > len(snapshot.txs) {
+		return nil, 0, fmt.Errorf("invalid cursor %v", cursor)
+	}
+	snapshot.lastAccess = now
+	return snapshot, offset, nil
+}
+
+// confirmed returns true if a block already included the nonce of the transaction
+func (m *MempoolSnapshots) confirmed(ethTx *types.EthTransaction) bool {
+	if m.pendingNonces == nil {
+		return false
+	}
+	from, err := ethTx.From()
+	if err != nil {
+		return false
+	}
+	pending, ok := m.pendingNonces.PendingNonces(common.Address(*from))
+	return ok && pending.HasConfirmedNonce && ethTx.Nonce() < pending.ConfirmedNonce
+}
+
+func (m *MempoolSnapshots) cleanExpired(now time.Time) {
+	for id, snapshot := range m.snapshots {
+		if now.Sub(snapshot.lastAccess) > mempoolSnapshotTTL {
+			delete(m.snapshots, id)
+		}
+	}
+}
+
+// effectiveTip returns the tip per gas of the transaction at the base fee, negative if the fee cap of the transaction
+// is below the base fee. The tip cap is returned if the base fee is unknown
+func effectiveTip(ethTx *types.EthTransaction, baseFee *big.Int) *big.Int {
+	tip := new(big.Int).Set(ethTx.EffectiveGasTipCap())
+	if baseFee == nil {
+		return tip
+	}
+	maxTip := new(big.Int).Sub(ethTx.EffectiveGasFeeCap(), baseFee)
+	if maxTip.Cmp(tip) < 0 {
+		return maxTip
+	}
+	return tip
+}
@@ -0,0 +1,129 @@
+package servers
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2"
+	"github.com/bloXroute-Labs/gateway/v2/services"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func addMempoolTx(t *testing.T, txStore services.TxStore, rawTx string) *ethtypes.Transaction {
+	tx, err := ParseRawTransaction(rawTx)
+	require.NoError(t, err)
+	content, err := rlp.EncodeToBytes(tx)
+	require.NoError(t, err)
+	hash := types.SHA256Hash(tx.Hash())
+	txStore.Add(hash, content, types.ShortIDEmpty, bxgateway.MainnetNum, false, types.TFPaidTx, time.Now(), 0, types.EmptySender)
+	return tx
+}
+
+func mempoolPageHashes(page MempoolPage) []string {
+	hashes := make([]string, 0, len(page.Txs))
+	for _, tx := range page.Txs {
+		hashes = append(hashes, tx.Transaction.GetHash())
+	}
+	return hashes
+}
+
+func TestMempoolSnapshots(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	txStore := services.NewBxTxStore(time.Minute, time.Minute, time.Minute, services.NewEmptyShortIDAssigner(), services.NewHashHistory("seenTxs", time.Minute), nil, 30*time.Minute, services.NoOpBloomFilter{})
+	pendingNonces := services.NewPendingNonceTracker(utils.RealClock{})
+	clock := &utils.MockClock{}
+	clock.SetTime(time.Now())
+	snapshots := NewMempoolSnapshots(clock, &txStore, pendingNonces, true)
+
+	low := addMempoolTx(t, &txStore, newFeeBumpTx(t, key, 1, 100, 10))
+	high := addMempoolTx(t, &txStore, newFeeBumpTx(t, key, 2, 100, 30))
+	capped := addMempoolTx(t, &txStore, newFeeBumpTx(t, key, 3, 25, 20))
+
+	page, err := snapshots.Page("account", "", "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{mempoolTxHash(high), mempoolTxHash(capped), mempoolTxHash(low)}, mempoolPageHashes(page))
+	assert.Equal(t, int64(20), page.Txs[1].EffectiveTip.Int64())
+
+	// the fee cap of the capped transaction leaves it a tip of 15 at a base fee of 10
+	snapshots.OnBlock(ethtypes.NewBlockWithHeader(&ethtypes.Header{Number: big.NewInt(1), BaseFee: big.NewInt(10)}))
+	page, err = snapshots.Page("account", "", "", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{mempoolTxHash(high), mempoolTxHash(capped)}, mempoolPageHashes(page))
+	assert.Equal(t, int64(15), page.Txs[1].EffectiveTip.Int64())
+	assert.Equal(t, 3, page.Total)
+	require.NotEmpty(t, page.NextCursor)
+	cursor := page.NextCursor
+
+	// the following pages are stable while the mempool changes
+	addMempoolTx(t, &txStore, newFeeBumpTx(t, key, 4, 100, 50))
+	next, err := snapshots.Page("account", "", cursor, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{mempoolTxHash(low)}, mempoolPageHashes(next))
+	assert.Empty(t, next.NextCursor)
+
+	// filters select the transactions of a snapshot, and confirmed nonces are left out
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	pendingNonces.Track(from, low.Nonce(), types.SHA256Hash(low.Hash()))
+	pendingNonces.OnBlock(types.NewRawBxBlock(types.SHA256Hash{}, types.SHA256Hash{}, types.BxBlockTypeEth, nil,
+		[]*types.BxBlockTransaction{types.NewBxBlockTransaction(types.SHA256Hash(low.Hash()), nil)}, nil, nil, big.NewInt(1), 0))
+	page, err = snapshots.Page("account", "{max_priority_fee_per_gas} < 40", "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{mempoolTxHash(high), mempoolTxHash(capped)}, mempoolPageHashes(page))
+
+	_, err = snapshots.Page("account", "{unknown_field} < 40", "", 0)
+	assert.Error(t, err)
+	_, err = snapshots.Page("account", "", "", maxMempoolPageSize+1)
+	assert.Error(t, err)
+
+	clock.IncTime(mempoolSnapshotTTL + time.Second)
+	_, err = snapshots.Page("account", "", cursor, 0)
+	assert.ErrorIs(t, err, errMempoolCursorNotFound)
+	_, err = snapshots.Page("account", "", "invalid", 0)
+	assert.Error(t, err)
+}
+
+func TestMempoolSnapshotsLimit(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	txStore := services.NewBxTxStore(time.Minute, time.Minute, time.Minute, services.NewEmptyShortIDAssigner(), services.NewHashHistory("seenTxs", time.Minute), nil, 30*time.Minute, services.NoOpBloomFilter{})
+	clock := &utils.MockClock{}
+	clock.SetTime(time.Now())
+	snapshots := NewMempoolSnapshots(clock, &txStore, nil, true)
+	addMempoolTx(t, &txStore, newFeeBumpTx(t, key, 1, 100, 10))
+	addMempoolTx(t, &txStore, newFeeBumpTx(t, key, 2, 100, 10))
+
+	// a snapshot without a next page is not kept
+	for i := 0; i < maxAccountMempoolSnapshots+1; i++ {
+		page, err := snapshots.Page("account", "", "", 0)
+		require.NoError(t, err)
+		assert.Empty(t, page.NextCursor)
+	}
+	assert.Empty(t, snapshots.snapshots)
+
+	var cursor string
+	for i := 0; i < maxAccountMempoolSnapshots; i++ {
+		page, err := snapshots.Page("account", "", "", 1)
+		require.NoError(t, err)
+		cursor = page.NextCursor
+	}
+	_, err = snapshots.Page("account", "", "", 1)
+	assert.ErrorIs(t, err, errTooManyAccountSnapshots)
+
+	// the snapshots are paged by the account which requested them only
+	_, err = snapshots.Page("other", "", cursor, 1)
+	assert.ErrorIs(t, err, errMempoolCursorNotFound)
+	_, err = snapshots.Page("other", "", "", 1)
+	require.NoError(t, err)
+}
+
+func mempoolTxHash(tx *ethtypes.Transaction) string {
+	return tx.Hash().Hex()
+}
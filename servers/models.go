@@ -1,9 +1,10 @@
 package servers
 
 import (
+	"encoding/json"
+
 	"github.com/bloXroute-Labs/gateway/v2/types"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
-	"github.com/zhouzhuojie/conditions"
 )
 
 // MultiTransactions - response for MultiTransactions subscription
@@ -39,20 +40,29 @@ type TxResultWithEthTx struct {
 // BlockResponse - response of the jsonrpc params
 type BlockResponse struct {
 	Subscription string             `json:"subscription"`
+	Seq          uint64             `json:"seq,omitempty"`
 	Result       types.Notification `json:"result"`
 }
 
 type txReceiptResponse struct {
 	Subscription string           `json:"subscription"`
+	Seq          uint64           `json:"seq,omitempty"`
 	Result       *types.TxReceipt `json:"result"`
 }
 
 type clientReq struct {
 	includes []string
 	feed     types.FeedType
-	expr     conditions.Expr
+	expr     *filterExpr
+	calldata *calldataABI
+	events   *eventABI
 	calls    *map[string]*RPCCall
 	MultiTxs bool
+	// backpressure is what is done with the notifications the client is too slow to receive
+	backpressure types.BackpressurePolicy
+	// resumeFrom is the sequence number of the last notification the client received, the following ones
+	// are replayed from the feed history and the live notifications up to it are skipped
+	resumeFrom *uint64
 }
 
 type subscriptionRequest struct {
@@ -66,6 +76,13 @@ type subscriptionOptions struct {
 	Filters    string              `json:"Filters"`
 	CallParams []map[string]string `json:"Call-Params"`
 	MultiTxs   bool                `json:"MultiTxs"`
+	// ABI is either an inline contract ABI or the name of an ABI loaded from the data dir. It decodes the
+	// tx input of newTxs/pendingTxs and the logs of txReceipts
+	ABI json.RawMessage `json:"ABI"`
+	// Backpressure is one of disconnect (default), drop-oldest, drop-newest and coalesce
+	Backpressure string `json:"backpressure"`
+	// ResumeFrom is the seq of the last notification received before reconnecting
+	ResumeFrom *uint64 `json:"resume_from"`
 }
 
 type rpcPingResponse struct {
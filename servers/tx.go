@@ -7,11 +7,13 @@ import (
 	"github.com/bloXroute-Labs/gateway/v2"
 	"github.com/bloXroute-Labs/gateway/v2/connections"
 	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/services/tracing"
 	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
 	"github.com/bloXroute-Labs/gateway/v2/utils/orderedmap"
 	"github.com/bloXroute-Labs/gateway/v2/utils/syncmap"
 	"github.com/ethereum/go-ethereum/common/hexutil"
-	"github.com/zhouzhuojie/conditions"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // HandleSingleTransaction handles a single tx, returns txHash, a boolean value indicating if it was successfully or not and an error only if we need to send it back to the caller
@@ -29,18 +31,23 @@ func HandleSingleTransaction(
 	validatorStatusMap *syncmap.SyncMap[string, bool],
 ) (string, bool, error) {
 
+	start := time.Now()
 	feedManager.LockPendingNextValidatorTxs()
 
 	txContent, err := types.DecodeHex(transaction)
 	if err != nil {
 		return "", false, err
 	}
-	tx, pendingReevaluation, err := validateTxFromExternalSource(transaction, txContent, validatorsOnly, feedManager.chainID, nextValidator, fallback, nextValidatorMap, validatorStatusMap, feedManager.networkNum, conn.GetAccountID(), nodeValidationRequested, feedManager.nodeWSManager, conn, feedManager.pendingBSCNextValidatorTxHashToInfo, frontRunningProtection)
+	tx, pendingReevaluation, err := validateTxFromExternalSource(transaction, txContent, validatorsOnly, feedManager.chainID, nextValidator, fallback, nextValidatorMap, validatorStatusMap, feedManager.networkNum, conn.GetAccountID(), nodeValidationRequested, feedManager.nodeWSManager, conn, feedManager.pendingBSCNextValidatorTxHashToInfo, frontRunningProtection, feedManager.cfg.BlockSanctionedTxs)
 	feedManager.UnlockPendingNextValidatorTxs()
 	if err != nil {
 		return "", false, err
 	}
 
+	tracing.StartTx(tx.Hash(), start, tracing.AccountIDKey.String(string(conn.GetAccountID())),
+		tracing.SourceKey.String(conn.GetConnectionType().String()))
+	tracing.StartSpan(tx.Hash(), "validate", trace.WithTimestamp(start)).End()
+
 	// This is an option to assign the sender of the tx manually in order to save time from tx processing
 	if txSender != nil {
 		var sender types.Sender
@@ -85,25 +92,15 @@ func shouldSendTx(clientReq *clientReq, tx *types.NewTransactionNotification, re
 		return true
 	}
 
-	filters := clientReq.expr.Args()
-	txFilters := tx.Filters(filters)
-
-	// should be done after tx.Filters() to avoid nil pointer dereference
-	txType := tx.BlockchainTransaction.(*types.EthTransaction).Type()
-
-	if !isFiltersSupportedByTxType(txType, filters) {
-		return false
-	}
-
-	// Evaluate if we should send the tx
-	shouldSend, err := conditions.Evaluate(clientReq.expr, txFilters)
-	if err != nil {
-		log.Errorf("error evaluate Filters. feed: %v. filters: %s. remote address: %v. account id: %v error - %v tx: %v",
-			clientReq.feed, clientReq.expr, remoteAddress, accountID, err.Error(), txFilters)
-		return false
+	// txFilters is nil for an invalid tx, which the expression never matches
+	txFilters := tx.Filters(clientReq.expr.Args())
+	if clientReq.calldata != nil {
+		if ethTx, ok := tx.BlockchainTransaction.(*types.EthTransaction); ok {
+			clientReq.calldata.addValues(txFilters, ethTx.Data(), clientReq.expr.Args())
+		}
 	}
 
-	return shouldSend
+	return clientReq.expr.Evaluate(txFilters)
 }
 
 func includeTx(clientReq *clientReq, tx *types.NewTransactionNotification) *TxResult {
@@ -136,6 +133,13 @@ func includeTx(clientReq *clientReq, tx *types.NewTransactionNotification) *TxRe
 			log.Errorf("Got nil from tx.Fields - need to be checked")
 			return nil
 		}
+		if clientReq.calldata != nil && utils.Exists(decodedInputField, clientReq.includes) {
+			if ethTx, ok := tx.BlockchainTransaction.(*types.EthTransaction); ok {
+				if decoded := clientReq.calldata.decode(ethTx.Data()); decoded != nil {
+					fields[decodedInputResult] = decoded
+				}
+			}
+		}
 		response.TxContents = fields
 	}
 
@@ -0,0 +1,148 @@
+package servers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bloXroute-Labs/gateway/v2/blockchain"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	simulateCallMethod  = "eth_call"
+	simulateTraceMethod = "debug_traceCall"
+)
+
+// simulationRPCOptions does not retry, a reverting call returns no response and would be retried by CallRPC
+var simulationRPCOptions = blockchain.RPCOptions{RetryAttempts: 1}
+
+var errSimulationUnavailable = errors.New("cannot simulate transaction, the gateway has no synced blockchain node websocket connection")
+
+// TxRevertedError is returned when the simulation of a transaction reverts
+type TxRevertedError struct {
+	TxHash string
+	Reason string
+}
+
+func (e *TxRevertedError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("transaction %v reverted", e.TxHash)
+	}
+	return fmt.Sprintf("transaction %v reverted: %v", e.TxHash, e.Reason)
+}
+
+// SimulateTransaction runs the transaction with eth_call at the latest block of a synced blockchain node, and returns
+// a TxRevertedError with the decoded revert reason if it reverts
+func SimulateTransaction(feedManager *FeedManager, transaction string) error {
+	tx, err := ParseRawTransaction(transaction)
+	if err != nil {
+		return err
+	}
+	ws, ok := feedManager.nodeWSManager.SyncedProvider()
+	if !ok {
+		return errSimulationUnavailable
+	}
+	return simulateTransaction(ws, tx)
+}
+
+func simulateTransaction(ws blockchain.WSProvider, tx *ethtypes.Transaction) error {
+	args, err := txCallArgs(tx)
+	if err != nil {
+		return err
+	}
+
+	_, err = ws.CallRPC(simulateCallMethod, []interface{}{args, "latest"}, simulationRPCOptions)
+	if err == nil {
+		return nil
+	}
+
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) {
+		// the node could not be reached, which says nothing about the transaction
+		return fmt.Errorf("failed to simulate transaction %v: %v", tx.Hash(), err)
+	}
+
+	reverted := &TxRevertedError{TxHash: tx.Hash().Hex()}
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			reverted.Reason = decodeRevertReason(data)
+		}
+	}
+	if reverted.Reason != "" {
+		return reverted
+	}
+	if !strings.Contains(err.Error(), "reverted") {
+		// rejected before execution, e.g. insufficient funds or intrinsic gas too low
+		return fmt.Errorf("transaction %v failed simulation: %v", tx.Hash(), err)
+	}
+	reverted.Reason = traceRevertReason(ws, args)
+	return reverted
+}
+
+// txCallArgs returns the eth_call arguments executing the transaction
+func txCallArgs(tx *ethtypes.Transaction) (map[string]interface{}, error) {
+	from, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract sender: %v", err)
+	}
+
+	args := map[string]interface{}{
+		"from":  from.Hex(),
+		"gas":   hexutil.Uint64(tx.Gas()),
+		"value": (*hexutil.Big)(tx.Value()),
+		"input": hexutil.Bytes(tx.Data()),
+	}
+	if to := tx.To(); to != nil {
+		args["to"] = to.Hex()
+	}
+	if tx.Type() == ethtypes.DynamicFeeTxType {
+		args["maxFeePerGas"] = (*hexutil.Big)(tx.GasFeeCap())
+		args["maxPriorityFeePerGas"] = (*hexutil.Big)(tx.GasTipCap())
+	} else {
+		args["gasPrice"] = (*hexutil.Big)(tx.GasPrice())
+	}
+	if len(tx.AccessList()) > 0 {
+		args["accessList"] = tx.AccessList()
+	}
+	return args, nil
+}
+
+// decodeRevertReason returns the message of Error(string) reverts, or the raw data of custom errors
+func decodeRevertReason(data string) string {
+	b, err := hexutil.Decode(data)
+	if err != nil || len(b) == 0 {
+		return ""
+	}
+	if reason, err := abi.UnpackRevert(b); err == nil {
+		return reason
+	}
+	return data
+}
+
+// traceRevertReason asks the node for the revert reason with the call tracer, for nodes which do not return the
+// revert data with eth_call. Nodes without the debug namespace leave the reason empty
+func traceRevertReason(ws blockchain.WSProvider, args map[string]interface{}) string {
+	response, err := ws.CallRPC(simulateTraceMethod, []interface{}{args, "latest", map[string]interface{}{"tracer": "callTracer"}}, simulationRPCOptions)
+	if err != nil {
+		return ""
+	}
+	trace, ok := response.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	if reason, ok := trace["revertReason"].(string); ok && reason != "" {
+		return reason
+	}
+	if output, ok := trace["output"].(string); ok {
+		if reason := decodeRevertReason(output); reason != "" {
+			return reason
+		}
+	}
+	reason, _ := trace["error"].(string)
+	return reason
+}
@@ -0,0 +1,108 @@
+package servers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bloXroute-Labs/gateway/v2/blockchain"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRPCError struct {
+	message string
+	data    interface{}
+}
+
+func (e *testRPCError) Error() string          { return e.message }
+func (e *testRPCError) ErrorCode() int         { return 3 }
+func (e *testRPCError) ErrorData() interface{} { return e.data }
+
+// testSimulationProvider answers the simulation calls of the node
+type testSimulationProvider struct {
+	blockchain.WSProvider
+	methods   []string
+	responses map[string]interface{}
+	errors    map[string]error
+}
+
+func (p *testSimulationProvider) CallRPC(method string, payload []interface{}, options blockchain.RPCOptions) (interface{}, error) {
+	p.methods = append(p.methods, method)
+	return p.responses[method], p.errors[method]
+}
+
+func testRevertData(t *testing.T, reason string) string {
+	stringType, err := abi.NewType("string", "", nil)
+	require.NoError(t, err)
+	packed, err := abi.Arguments{{Type: stringType}}.Pack(reason)
+	require.NoError(t, err)
+	return hexutil.Encode(append([]byte{0x08, 0xc3, 0x79, 0xa0}, packed...))
+}
+
+func TestSimulateTransaction(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	tx, err := ParseRawTransaction(newFeeBumpTx(t, key, 0, 100, 10))
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		provider := &testSimulationProvider{responses: map[string]interface{}{simulateCallMethod: "0x"}}
+		assert.NoError(t, simulateTransaction(provider, tx))
+		assert.Equal(t, []string{simulateCallMethod}, provider.methods)
+	})
+
+	t.Run("revert reason", func(t *testing.T) {
+		provider := &testSimulationProvider{errors: map[string]error{
+			simulateCallMethod: &testRPCError{message: "execution reverted: too little received", data: testRevertData(t, "too little received")},
+		}}
+		err := simulateTransaction(provider, tx)
+		var revertedErr *TxRevertedError
+		require.True(t, errors.As(err, &revertedErr))
+		assert.Equal(t, "too little received", revertedErr.Reason)
+		assert.Equal(t, tx.Hash().Hex(), revertedErr.TxHash)
+	})
+
+	t.Run("custom error", func(t *testing.T) {
+		provider := &testSimulationProvider{errors: map[string]error{
+			simulateCallMethod: &testRPCError{message: "execution reverted", data: "0x12345678"},
+		}}
+		err := simulateTransaction(provider, tx)
+		var revertedErr *TxRevertedError
+		require.True(t, errors.As(err, &revertedErr))
+		assert.Equal(t, "0x12345678", revertedErr.Reason)
+		assert.Equal(t, []string{simulateCallMethod}, provider.methods)
+	})
+
+	t.Run("traced revert reason", func(t *testing.T) {
+		provider := &testSimulationProvider{
+			errors:    map[string]error{simulateCallMethod: &testRPCError{message: "execution reverted"}},
+			responses: map[string]interface{}{simulateTraceMethod: map[string]interface{}{"error": "execution reverted", "revertReason": "expired"}},
+		}
+		err := simulateTransaction(provider, tx)
+		var revertedErr *TxRevertedError
+		require.True(t, errors.As(err, &revertedErr))
+		assert.Equal(t, "expired", revertedErr.Reason)
+		assert.Equal(t, []string{simulateCallMethod, simulateTraceMethod}, provider.methods)
+	})
+
+	t.Run("rejected before execution", func(t *testing.T) {
+		provider := &testSimulationProvider{errors: map[string]error{
+			simulateCallMethod: &testRPCError{message: "err: insufficient funds for gas * price + value"},
+		}}
+		err := simulateTransaction(provider, tx)
+		require.Error(t, err)
+		var revertedErr *TxRevertedError
+		assert.False(t, errors.As(err, &revertedErr))
+		assert.Contains(t, err.Error(), "insufficient funds")
+	})
+
+	t.Run("node unreachable", func(t *testing.T) {
+		provider := &testSimulationProvider{errors: map[string]error{simulateCallMethod: errors.New("connection closed")}}
+		err := simulateTransaction(provider, tx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to simulate")
+	})
+}
@@ -30,6 +30,14 @@ func validateIncludeParam(feed types.FeedType, include []string, txFromFieldIncl
 			requestedFields = validOnBlockParams
 		case types.TxReceiptsFeed:
 			requestedFields = validTxReceiptParams
+		case types.ReorgFeed:
+			requestedFields = validReorgParams
+		case types.TransactionStatusFeed:
+			requestedFields = validTxStatusParams
+		case types.GasEstimateFeed:
+			requestedFields = validGasEstimateParams
+		case types.BundleStatusFeed:
+			requestedFields = validBundleStatusParams
 		}
 
 		return requestedFields, nil
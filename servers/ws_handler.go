@@ -73,8 +73,30 @@ func (h *handlerObj) Handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonr
 		h.handleRPCMevSearcher(ctx, conn, req)
 	case jsonrpc.RPCBundleSubmission:
 		h.handleRPCBundleSubmission(ctx, conn, req)
+	case jsonrpc.RPCBundleSimulation:
+		h.handleRPCBundleSimulation(ctx, conn, req)
 	case jsonrpc.RPCChangeNewPendingTxFromNode:
 		h.handleRPCNewPendingTxsSourceFromNode(ctx, conn, req)
+	case jsonrpc.RPCPendingNonces:
+		h.handleRPCPendingNonces(ctx, conn, req)
+	case jsonrpc.RPCFeeBumpTx:
+		h.handleRPCFeeBumpTx(ctx, conn, req)
+	case jsonrpc.RPCFeeBumpTxStatus:
+		h.handleRPCFeeBumpTxStatus(ctx, conn, req)
+	case jsonrpc.RPCConditionalTxs:
+		h.handleRPCConditionalTxs(ctx, conn, req)
+	case jsonrpc.RPCCancelConditionalTx:
+		h.handleRPCCancelConditionalTx(ctx, conn, req)
+	case jsonrpc.RPCMempoolSnapshot:
+		h.handleRPCMempoolSnapshot(ctx, conn, req)
+	case jsonrpc.RPCGasEstimate:
+		h.handleRPCGasEstimate(ctx, conn, req)
+	case jsonrpc.RPCBundleStatus:
+		h.handleRPCBundleStatus(ctx, conn, req)
+	case jsonrpc.RPCStartMonitoringTx:
+		h.handleRPCStartMonitorTx(ctx, conn, req)
+	case jsonrpc.RPCStopMonitoringTx:
+		h.handleRPCStopMonitorTx(ctx, conn, req)
 	default:
 		if !h.enableBlockchainRPC {
 			err := fmt.Errorf("got unsupported method name: %v", req.Method)
@@ -127,6 +149,7 @@ func (h *handlerObj) Handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonr
 func (h *handlerObj) sendNotification(ctx context.Context, subscriptionID string, clientReq *clientReq, conn *jsonrpc2.Conn, notification types.Notification) error {
 	response := BlockResponse{
 		Subscription: subscriptionID,
+		Seq:          notificationSeq(notification),
 	}
 	content := notification.WithFields(clientReq.includes)
 	response.Result = content
@@ -14,8 +14,16 @@ import (
 
 var errInvalidTransactions = "all transactions are invalid"
 
+type rpcBatchTxResult struct {
+	Idx    int    `json:"idx"`
+	TxHash string `json:"txHash,omitempty"`
+	Code   string `json:"code"`
+	Error  string `json:"error,omitempty"`
+}
+
 type rpcBatchTxResponse struct {
-	TxHashes []string `json:"txHashes"`
+	TxHashes []string           `json:"txHashes"`
+	Results  []rpcBatchTxResult `json:"results"`
 }
 
 func (h *handlerObj) handleRPCBatchTx(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
@@ -36,6 +44,11 @@ func (h *handlerObj) handleRPCBatchTx(ctx context.Context, conn *jsonrpc2.Conn,
 			jsonrpc.RPCChangeNewPendingTxFromNode, err), conn, req.ID)
 		return
 	}
+	if len(params.Transactions) > MaxBatchTxs {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("%v currently supports a maximum of %v transactions",
+			jsonrpc.RPCBatchTx, MaxBatchTxs), conn, req.ID)
+		return
+	}
 
 	var ws connections.RPCConn
 	if h.connectionAccount.AccountID == types.BloxrouteAccountID {
@@ -45,32 +58,37 @@ func (h *handlerObj) handleRPCBatchTx(ctx context.Context, conn *jsonrpc2.Conn,
 		ws = connections.NewRPCConn(h.connectionAccount.AccountID, h.remoteAddress, h.FeedManager.networkNum, utils.Websocket)
 	}
 
-	var txHashes []string
+	results := HandleBatchTransactions(h.FeedManager, params.Transactions, nil, ws, BatchTxOptions{
+		ValidatorsOnly: params.ValidatorsOnly,
+		Simulate:       params.Simulate,
+		Atomic:         params.Atomic,
+	})
 
-	for _, transaction := range params.Transactions {
-		txHash, ok, err := HandleSingleTransaction(h.FeedManager, transaction, nil, ws, params.ValidatorsOnly, false,
-			false, false, 0, nil, nil)
-		if err != nil {
-			h.log.WithField("method", jsonrpc.RPCBatchTx).Errorf("failed to handle transaction: %v", err)
+	response := rpcBatchTxResponse{Results: make([]rpcBatchTxResult, 0, len(results))}
+	for _, result := range results {
+		response.Results = append(response.Results, rpcBatchTxResult{
+			Idx:    result.Idx,
+			TxHash: result.TxHash,
+			Code:   string(result.Code),
+			Error:  result.Error,
+		})
+		if result.Sent() {
+			response.TxHashes = append(response.TxHashes, result.TxHash)
+		} else if result.Code != BatchTxNotSent {
+			h.log.WithField("method", jsonrpc.RPCBatchTx).Errorf("failed to handle transaction %v: %v", result.Idx, result.Error)
 		}
-		if !ok {
-			continue
-		}
-		txHashes = append(txHashes, txHash)
 	}
 
-	if len(txHashes) == 0 {
+	// simulated and atomic batches always report the results, the clients need them to know which transactions were
+	// rejected by the node or prevented the batch
+	if len(response.TxHashes) == 0 && !params.Atomic && !params.Simulate {
 		SendErrorMsg(ctx, jsonrpc.InvalidParams, errInvalidTransactions, conn, req.ID)
 		return
 	}
 
-	if len(txHashes) != len(params.Transactions) {
+	if len(response.TxHashes) != len(params.Transactions) {
 		h.log.WithField("method", jsonrpc.RPCBatchTx).
-			Errorf("failed to handle all transactions, successful: %d, total: %d", len(txHashes), len(params.Transactions))
-	}
-
-	response := rpcBatchTxResponse{
-		TxHashes: txHashes,
+			Errorf("failed to handle all transactions, successful: %d, total: %d", len(response.TxHashes), len(params.Transactions))
 	}
 
 	if err = conn.Reply(ctx, req.ID, response); err != nil {
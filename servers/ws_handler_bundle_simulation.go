@@ -0,0 +1,39 @@
+package servers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+func (h *handlerObj) handleRPCBundleSimulation(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	if req.Params == nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, errParamsValueIsMissing, conn, req.ID)
+		return
+	}
+
+	var params jsonrpc.RPCBundleSimulationPayload
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("failed to unmarshal params for %v request: %v",
+			jsonrpc.RPCBundleSimulation, err), conn, req.ID)
+		return
+	}
+
+	result, err := SimulateBundle(h.FeedManager, h.connectionAccount, &params)
+	if err != nil {
+		code := jsonrpc.InternalError
+		if errors.Is(err, errInvalidPayload) {
+			code = jsonrpc.InvalidParams
+		}
+		SendErrorMsg(ctx, code, err.Error(), conn, req.ID)
+		return
+	}
+
+	if err = conn.Reply(ctx, req.ID, result); err != nil {
+		h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
+	}
+}
@@ -0,0 +1,43 @@
+package servers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+func (h *handlerObj) handleRPCBundleStatus(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	if req.Params == nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, errParamsValueIsMissing, conn, req.ID)
+		return
+	}
+
+	var params jsonrpc.RPCBundleStatusPayload
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("failed to unmarshal params for %v request: %v",
+			jsonrpc.RPCBundleStatus, err), conn, req.ID)
+		return
+	}
+	if params.BundleHash == "" {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, "bundle_hash is missing in the request", conn, req.ID)
+		return
+	}
+
+	if h.FeedManager.bundleTracker == nil {
+		SendErrorMsg(ctx, jsonrpc.MethodNotFound, fmt.Sprintf("%v is not supported by the gateway", jsonrpc.RPCBundleStatus), conn, req.ID)
+		return
+	}
+
+	status, ok := h.FeedManager.bundleTracker.Status(params.BundleHash, h.connectionAccount.AccountID)
+	if !ok {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("bundle %v is not tracked by the gateway", params.BundleHash), conn, req.ID)
+		return
+	}
+
+	if err := conn.Reply(ctx, req.ID, status.Notification()); err != nil {
+		h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
+	}
+}
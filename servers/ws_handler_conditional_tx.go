@@ -0,0 +1,189 @@
+package servers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/connections"
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/services"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+var errConditionalTxsNotSupported = errors.New("conditional transactions are not supported by the gateway")
+
+type rpcConditionalTx struct {
+	TxHash      string    `json:"txHash"`
+	Condition   string    `json:"condition"`
+	ExpiryBlock uint64    `json:"expiryBlock,omitempty"`
+	AddedTime   time.Time `json:"addedTime"`
+}
+
+type rpcConditionalTxsResponse struct {
+	Txs []rpcConditionalTx `json:"txs"`
+}
+
+// HandleConditionalTransaction holds a transaction in the gateway until its condition matches a block, the
+// transaction is then simulated if requested and sent with the given options as if it was just submitted
+func HandleConditionalTransaction(
+	feedManager *FeedManager,
+	transaction string,
+	condition string,
+	expiryBlock uint64,
+	conn connections.Conn,
+	validatorsOnly,
+	nextValidator,
+	nodeValidationRequested,
+	frontRunningProtection bool,
+	fallback uint16,
+	simulate bool,
+) (string, error) {
+	if feedManager.conditionalTxs == nil {
+		return "", errConditionalTxsNotSupported
+	}
+
+	expr, err := validateConditionFilters(condition)
+	if err != nil {
+		return "", err
+	}
+	tx, err := ParseRawTransaction(transaction)
+	if err != nil {
+		return "", err
+	}
+	txContent, err := types.DecodeHex(transaction)
+	if err != nil {
+		return "", err
+	}
+	// the transaction is validated as if it was sent now, the checks of the validators and of the node are left to its release
+	if _, _, err = validateTxFromExternalSource(transaction, txContent, false, feedManager.chainID, false, fallback, nil, nil,
+		feedManager.networkNum, conn.GetAccountID(), false, feedManager.nodeWSManager, conn, nil, frontRunningProtection,
+		feedManager.cfg.BlockSanctionedTxs); err != nil {
+		return "", err
+	}
+	hash, err := types.NewSHA256Hash(tx.Hash().Bytes())
+	if err != nil {
+		return "", err
+	}
+
+	conditionalTx := &services.ConditionalTx{
+		Hash:        hash,
+		Transaction: transaction,
+		AccountID:   conn.GetAccountID(),
+		Condition:   expr.String(),
+		ExpiryBlock: expiryBlock,
+		Match: func(block *ethtypes.Block) bool {
+			return matchBlockCondition(expr, block)
+		},
+		Release: func(conditionalTx *services.ConditionalTx) error {
+			if simulate {
+				if err := SimulateTransaction(feedManager, conditionalTx.Transaction); err != nil {
+					return err
+				}
+			}
+			_, ok, err := HandleSingleTransaction(feedManager, conditionalTx.Transaction, nil, conn, validatorsOnly, nextValidator,
+				nodeValidationRequested, frontRunningProtection, fallback, feedManager.nextValidatorMap, feedManager.validatorStatusMap)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return fmt.Errorf("failed to send transaction %v", conditionalTx.Hash)
+			}
+			return nil
+		},
+	}
+	if err = feedManager.conditionalTxs.Add(conditionalTx); err != nil {
+		return "", err
+	}
+
+	log.Infof("holding conditional transaction %v until %v, expiry block %v", hash, conditionalTx.Condition, conditionalTx.ExpiryBlock)
+	return hash.String(), nil
+}
+
+func (h *handlerObj) handleRPCConditionalTxs(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	var params jsonrpc.RPCConditionalTxsPayload
+	if req.Params != nil {
+		if err := json.Unmarshal(*req.Params, &params); err != nil {
+			SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("failed to unmarshal params for %v request: %v",
+				jsonrpc.RPCConditionalTxs, err), conn, req.ID)
+			return
+		}
+	}
+
+	if h.FeedManager.conditionalTxs == nil {
+		SendErrorMsg(ctx, jsonrpc.MethodNotFound, fmt.Sprintf("%v is not supported by the gateway", jsonrpc.RPCConditionalTxs), conn, req.ID)
+		return
+	}
+
+	var txs []services.ConditionalTxInfo
+	if params.TxHash == "" {
+		txs = h.FeedManager.conditionalTxs.List(h.connectionAccount.AccountID)
+	} else {
+		hash, err := types.NewSHA256HashFromString(params.TxHash)
+		if err != nil {
+			SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("invalid tx_hash %v", params.TxHash), conn, req.ID)
+			return
+		}
+		if info, ok := h.FeedManager.conditionalTxs.Get(hash, h.connectionAccount.AccountID); ok {
+			txs = append(txs, info)
+		}
+	}
+
+	if err := conn.Reply(ctx, req.ID, newRPCConditionalTxsResponse(txs)); err != nil {
+		h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
+	}
+}
+
+func (h *handlerObj) handleRPCCancelConditionalTx(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	if req.Params == nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, errParamsValueIsMissing, conn, req.ID)
+		return
+	}
+
+	var params jsonrpc.RPCCancelConditionalTxPayload
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("failed to unmarshal params for %v request: %v",
+			jsonrpc.RPCCancelConditionalTx, err), conn, req.ID)
+		return
+	}
+	hash, err := types.NewSHA256HashFromString(params.TxHash)
+	if err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("invalid tx_hash %v", params.TxHash), conn, req.ID)
+		return
+	}
+
+	if h.FeedManager.conditionalTxs == nil {
+		SendErrorMsg(ctx, jsonrpc.MethodNotFound, fmt.Sprintf("%v is not supported by the gateway", jsonrpc.RPCCancelConditionalTx), conn, req.ID)
+		return
+	}
+
+	if err = h.FeedManager.conditionalTxs.Cancel(hash, h.connectionAccount.AccountID); err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, err.Error(), conn, req.ID)
+		return
+	}
+
+	if err = conn.Reply(ctx, req.ID, rpcTxResponse{TxHash: hash.String()}); err != nil {
+		h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
+		return
+	}
+
+	h.log.Infof("%v: hash - 0x%v", jsonrpc.RPCCancelConditionalTx, hash)
+}
+
+func newRPCConditionalTxsResponse(txs []services.ConditionalTxInfo) rpcConditionalTxsResponse {
+	response := rpcConditionalTxsResponse{Txs: make([]rpcConditionalTx, 0, len(txs))}
+	for _, tx := range txs {
+		response.Txs = append(response.Txs, rpcConditionalTx{
+			TxHash:      tx.Hash.Format(true),
+			Condition:   tx.Condition,
+			ExpiryBlock: tx.ExpiryBlock,
+			AddedTime:   tx.AddedTime,
+		})
+	}
+	return response
+}
@@ -43,7 +43,7 @@ func (h *handlerObj) handleRPCEthSendTx(ctx context.Context, conn *jsonrpc2.Conn
 	txHash, ok, err := HandleSingleTransaction(h.FeedManager, rawTxStr, nil, reqWS, false, false,
 		false, false, 0, nil, nil)
 	if err != nil {
-		SendErrorMsg(ctx, jsonrpc.InvalidParams, err.Error(), conn, req.ID)
+		SendErrorMsg(ctx, txErrorCode(err), err.Error(), conn, req.ID)
 	}
 	if !ok {
 		return
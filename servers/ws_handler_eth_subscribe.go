@@ -17,6 +17,7 @@ import (
 const (
 	feedTypeNewPendingTransactions = "newPendingTransactions"
 	feedTypeNewHeads               = "newHeads"
+	feedTypeLogs                   = "logs"
 )
 
 // EthSubscribeTxResponse - response of the jsonrpc params
@@ -51,6 +52,12 @@ func (h *handlerObj) handleRPCEthSubscribe(ctx context.Context, conn *jsonrpc2.C
 		h.handleEthSubscribeNewPendingTxs(ctx, conn, req, ci)
 	case feedTypeNewHeads:
 		h.handleEthSubscribeNewHeads(ctx, conn, req, ci)
+	case feedTypeLogs:
+		var filterParam interface{}
+		if len(rpcParams) > 1 {
+			filterParam = rpcParams[1]
+		}
+		h.handleEthSubscribeLogs(ctx, conn, req, ci, filterParam)
 	default:
 		h.handleEthSubscribeFeed(ctx, feedType, conn, req, ws, rpcParams)
 	}
@@ -169,6 +176,72 @@ func (h *handlerObj) handleEthSubscribeNewHeads(ctx context.Context, conn *jsonr
 	}
 }
 
+// handleEthSubscribeLogs serves logs subscriptions from the receipts of the canonical chain blocks, instead of
+// forwarding them to the node. The logs of the blocks dropped by a reorganization are sent again with removed set
+func (h *handlerObj) handleEthSubscribeLogs(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request, ci types.ClientInfo, filterParam interface{}) {
+	logsFilter, err := newEthLogsFilter(filterParam)
+	if err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, err.Error(), conn, req.ID)
+		return
+	}
+
+	request := &clientReq{
+		feed:     types.EthLogsFeed,
+		includes: []string{"block_hash", "block_number", "logs"},
+	}
+
+	ro := types.ReqOptions{
+		Includes: strings.Join(request.includes, ","),
+	}
+	sub, errSubscribe := h.FeedManager.Subscribe(request.feed, types.WebSocketFeed, conn, ci, ro, true)
+	if errSubscribe != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, errSubscribe.Error(), conn, req.ID)
+		return
+	}
+
+	subscriptionID := sub.SubscriptionID
+	defer h.FeedManager.Unsubscribe(subscriptionID, false, "")
+
+	if err = conn.Reply(ctx, req.ID, subscriptionID); err != nil {
+		h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
+		SendErrorMsg(ctx, jsonrpc.InternalError, string(rune(websocket.CloseMessage)), conn, req.ID)
+		return
+	}
+
+	for {
+		select {
+		case <-conn.DisconnectNotify():
+			return
+		case errMsg := <-sub.ErrMsgChan:
+			SendErrorMsg(ctx, jsonrpc.InvalidParams, errMsg, conn, req.ID)
+			return
+		case notification, ok := <-sub.FeedChan:
+			if !ok {
+				if h.FeedManager.SubscriptionExists(subscriptionID) {
+					SendErrorMsg(ctx, jsonrpc.InternalError, string(rune(websocket.CloseMessage)), conn, req.ID)
+				}
+				return
+			}
+
+			logsNotification := notification.(*types.EthLogsNotification)
+			logs := logsFilter.matchingLogs(logsNotification.Receipts)
+			if logsNotification.Removed {
+				// the logs of a block dropped from the canonical chain are removed in reverse order
+				removed := make([]map[string]interface{}, 0, len(logs))
+				for i := len(logs) - 1; i >= 0; i-- {
+					removed = append(removed, removedEthLog(logs[i]))
+				}
+				logs = removed
+			}
+			for _, ethLog := range logs {
+				if h.sendEthSubscribeNotification(ctx, subscriptionID, conn, ethLog) != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
 func (h *handlerObj) handleEthSubscribeFeed(ctx context.Context, feedType string, conn *jsonrpc2.Conn, req *jsonrpc2.Request, ws blockchain.WSProvider, rpcParams []interface{}) {
 	var err error
 	var sub *blockchain.Subscription
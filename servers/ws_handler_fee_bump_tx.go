@@ -0,0 +1,194 @@
+package servers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bloXroute-Labs/gateway/v2/connections"
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/bloXroute-Labs/gateway/v2/services"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// minFeeBumpPercent is the fee increase of a replacement required by the nodes to replace a transaction in their pool
+const minFeeBumpPercent = 10
+
+type rpcFeeBumpTxResponse struct {
+	ReplacementID string   `json:"replacementId"`
+	TxHashes      []string `json:"txHashes"`
+}
+
+type rpcFeeBumpTxStatusResponse struct {
+	ReplacementID string `json:"replacementId"`
+	Released      int    `json:"released"`
+	Transactions  int    `json:"transactions"`
+	TxHash        string `json:"txHash"`
+}
+
+func (h *handlerObj) handleRPCFeeBumpTx(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	if h.FeedManager.accountModel.AccountID != h.connectionAccount.AccountID {
+		errDifferentAccAuth := fmt.Sprintf(errFDifferentAccAuth, jsonrpc.RPCFeeBumpTx)
+		h.log.Errorf("%v. account auth: %v, node account: %v", errDifferentAccAuth, h.connectionAccount.AccountID, h.FeedManager.accountModel.AccountID)
+		SendErrorMsg(ctx, jsonrpc.InvalidRequest, errDifferentAccAuth, conn, req.ID)
+		return
+	}
+
+	if req.Params == nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, errParamsValueIsMissing, conn, req.ID)
+		return
+	}
+
+	var params jsonrpc.RPCFeeBumpTxPayload
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("failed to unmarshal params for %v request: %v",
+			jsonrpc.RPCFeeBumpTx, err), conn, req.ID)
+		return
+	}
+
+	if h.FeedManager.txReplacements == nil {
+		SendErrorMsg(ctx, jsonrpc.MethodNotFound, fmt.Sprintf("%v is not supported by the gateway", jsonrpc.RPCFeeBumpTx), conn, req.ID)
+		return
+	}
+
+	ladder, err := newTxReplacementLadder(params)
+	if err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, err.Error(), conn, req.ID)
+		return
+	}
+	ladder.AccountID = h.connectionAccount.AccountID
+
+	ws := connections.NewRPCConn(h.connectionAccount.AccountID, h.remoteAddress, h.FeedManager.networkNum, utils.Websocket)
+	ladder.Release = func(rung services.TxReplacementRung) error {
+		_, ok, err := HandleSingleTransaction(h.FeedManager, rung.Transaction, nil, ws, false, false, false, false, 0,
+			h.FeedManager.nextValidatorMap, h.FeedManager.validatorStatusMap)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("failed to send transaction %v", rung.Hash)
+		}
+		return nil
+	}
+
+	if err = h.FeedManager.txReplacements.Add(ladder); err != nil {
+		SendErrorMsg(ctx, txErrorCode(err), err.Error(), conn, req.ID)
+		return
+	}
+
+	response := rpcFeeBumpTxResponse{
+		ReplacementID: ladder.ID,
+		TxHashes:      make([]string, 0, len(ladder.Rungs)),
+	}
+	for _, rung := range ladder.Rungs {
+		response.TxHashes = append(response.TxHashes, rung.Hash.Format(true))
+	}
+
+	if err = conn.Reply(ctx, req.ID, response); err != nil {
+		h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
+		return
+	}
+
+	h.log.Infof("%v: replacement %v with %v transactions", jsonrpc.RPCFeeBumpTx, ladder.ID, len(ladder.Rungs))
+}
+
+func (h *handlerObj) handleRPCFeeBumpTxStatus(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	if req.Params == nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, errParamsValueIsMissing, conn, req.ID)
+		return
+	}
+
+	var params jsonrpc.RPCFeeBumpTxStatusPayload
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("failed to unmarshal params for %v request: %v",
+			jsonrpc.RPCFeeBumpTxStatus, err), conn, req.ID)
+		return
+	}
+	if params.ReplacementID == "" {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, "replacementId is missing in the request", conn, req.ID)
+		return
+	}
+
+	if h.FeedManager.txReplacements == nil {
+		SendErrorMsg(ctx, jsonrpc.MethodNotFound, fmt.Sprintf("%v is not supported by the gateway", jsonrpc.RPCFeeBumpTxStatus), conn, req.ID)
+		return
+	}
+
+	status, ok := h.FeedManager.txReplacements.Status(params.ReplacementID, h.connectionAccount.AccountID)
+	if !ok {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("replacement %v is not active", params.ReplacementID), conn, req.ID)
+		return
+	}
+
+	response := rpcFeeBumpTxStatusResponse{
+		ReplacementID: status.ID,
+		Released:      status.Released,
+		Transactions:  status.Rungs,
+		TxHash:        status.TxHash.Format(true),
+	}
+	if err := conn.Reply(ctx, req.ID, response); err != nil {
+		h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
+	}
+}
+
+// newTxReplacementLadder checks that the transactions have the same sender and nonce, and that each of them raises
+// both fee caps of the previous one enough to replace it
+func newTxReplacementLadder(params jsonrpc.RPCFeeBumpTxPayload) (*services.TxReplacementLadder, error) {
+	if len(params.Transactions) < 2 {
+		return nil, errors.New("at least 2 transactions are required")
+	}
+
+	schedule := params.Schedule
+	if len(schedule) == 0 {
+		if params.BlockInterval == 0 {
+			return nil, errors.New("block_interval or schedule is required")
+		}
+		schedule = []uint64{params.BlockInterval}
+	}
+
+	ladder := &services.TxReplacementLadder{
+		Rungs:    make([]services.TxReplacementRung, 0, len(params.Transactions)),
+		Schedule: schedule,
+	}
+	var previous *ethtypes.Transaction
+	var from string
+	for i, rawTx := range params.Transactions {
+		tx, err := ParseRawTransaction(rawTx)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %v: %v", i, err)
+		}
+		sender, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(tx.ChainId()), tx)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %v: failed to extract sender: %v", i, err)
+		}
+
+		if previous != nil {
+			if sender.Hex() != from || tx.Nonce() != previous.Nonce() {
+				return nil, fmt.Errorf("transaction %v: sender %v and nonce %v do not match the first transaction", i, sender, tx.Nonce())
+			}
+			if !isFeeBump(previous.GasFeeCap(), tx.GasFeeCap()) || !isFeeBump(previous.GasTipCap(), tx.GasTipCap()) {
+				return nil, fmt.Errorf("transaction %v: fee cap and tip cap must be at least %v%% higher than the previous transaction", i, minFeeBumpPercent)
+			}
+		}
+
+		hash, err := types.NewSHA256Hash(tx.Hash().Bytes())
+		if err != nil {
+			return nil, err
+		}
+		ladder.Rungs = append(ladder.Rungs, services.TxReplacementRung{Hash: hash, Transaction: rawTx})
+		previous = tx
+		from = sender.Hex()
+	}
+	ladder.ID = ladder.Rungs[0].Hash.Format(true)
+	return ladder, nil
+}
+
+func isFeeBump(previous, next *big.Int) bool {
+	minimum := new(big.Int).Mul(previous, big.NewInt(100+minFeeBumpPercent))
+	return next.Cmp(previous) > 0 && new(big.Int).Mul(next, big.NewInt(100)).Cmp(minimum) >= 0
+}
@@ -0,0 +1,70 @@
+package servers
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFeeBumpTx(t *testing.T, key *ecdsa.PrivateKey, nonce uint64, feeCap, tipCap int64) string {
+	chainID := big.NewInt(1)
+	tx, err := ethtypes.SignNewTx(key, ethtypes.NewLondonSigner(chainID), &ethtypes.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasFeeCap: big.NewInt(feeCap),
+		GasTipCap: big.NewInt(tipCap),
+		Gas:       21000,
+		Value:     big.NewInt(1),
+	})
+	require.NoError(t, err)
+	b, err := tx.MarshalBinary()
+	require.NoError(t, err)
+	return hexutil.Encode(b)
+}
+
+func TestNewTxReplacementLadder(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	otherKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	params := jsonrpc.RPCFeeBumpTxPayload{
+		Transactions: []string{newFeeBumpTx(t, key, 7, 100, 10), newFeeBumpTx(t, key, 7, 110, 11), newFeeBumpTx(t, key, 7, 200, 20)},
+		Schedule:     []uint64{2, 3},
+	}
+	ladder, err := newTxReplacementLadder(params)
+	require.NoError(t, err)
+	require.Len(t, ladder.Rungs, 3)
+	assert.Equal(t, ladder.Rungs[0].Hash.Format(true), ladder.ID)
+	assert.Equal(t, params.Transactions[1], ladder.Rungs[1].Transaction)
+	assert.Equal(t, []uint64{2, 3}, ladder.Schedule)
+
+	params = jsonrpc.RPCFeeBumpTxPayload{
+		Transactions:  []string{newFeeBumpTx(t, key, 7, 100, 10), newFeeBumpTx(t, key, 7, 110, 11)},
+		BlockInterval: 1,
+	}
+	ladder, err = newTxReplacementLadder(params)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{1}, ladder.Schedule)
+
+	invalid := map[string]jsonrpc.RPCFeeBumpTxPayload{
+		"single transaction": {Transactions: []string{newFeeBumpTx(t, key, 7, 100, 10)}, BlockInterval: 1},
+		"no schedule":        {Transactions: []string{newFeeBumpTx(t, key, 7, 100, 10), newFeeBumpTx(t, key, 7, 200, 20)}},
+		"other nonce":        {Transactions: []string{newFeeBumpTx(t, key, 7, 100, 10), newFeeBumpTx(t, key, 8, 200, 20)}, BlockInterval: 1},
+		"other sender":       {Transactions: []string{newFeeBumpTx(t, key, 7, 100, 10), newFeeBumpTx(t, otherKey, 7, 200, 20)}, BlockInterval: 1},
+		"low fee bump":       {Transactions: []string{newFeeBumpTx(t, key, 7, 100, 10), newFeeBumpTx(t, key, 7, 109, 20)}, BlockInterval: 1},
+		"low tip bump":       {Transactions: []string{newFeeBumpTx(t, key, 7, 100, 10), newFeeBumpTx(t, key, 7, 200, 10)}, BlockInterval: 1},
+		"invalid hex":        {Transactions: []string{newFeeBumpTx(t, key, 7, 100, 10), "0xzz"}, BlockInterval: 1},
+	}
+	for name, params := range invalid {
+		_, err = newTxReplacementLadder(params)
+		assert.Error(t, err, name)
+	}
+}
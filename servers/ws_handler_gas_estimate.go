@@ -0,0 +1,26 @@
+package servers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+func (h *handlerObj) handleRPCGasEstimate(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	if h.FeedManager.gasOracle == nil {
+		SendErrorMsg(ctx, jsonrpc.MethodNotFound, fmt.Sprintf("%v is not supported by the gateway", jsonrpc.RPCGasEstimate), conn, req.ID)
+		return
+	}
+
+	estimate, err := h.FeedManager.gasOracle.Estimate()
+	if err != nil {
+		SendErrorMsg(ctx, jsonrpc.InternalError, err.Error(), conn, req.ID)
+		return
+	}
+
+	if err = conn.Reply(ctx, req.ID, estimate); err != nil {
+		h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
+	}
+}
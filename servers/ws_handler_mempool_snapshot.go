@@ -0,0 +1,102 @@
+package servers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bloXroute-Labs/gateway/v2"
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+type rpcMempoolTx struct {
+	TxResult
+	EffectiveTip string `json:"effectiveTip"`
+}
+
+type rpcMempoolSnapshotResponse struct {
+	Txs          []rpcMempoolTx `json:"txs"`
+	NextCursor   string         `json:"nextCursor,omitempty"`
+	Total        int            `json:"total"`
+	SnapshotTime string         `json:"snapshotTime"`
+}
+
+func (h *handlerObj) handleRPCMempoolSnapshot(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	var params jsonrpc.RPCMempoolSnapshotPayload
+	if req.Params != nil {
+		if err := json.Unmarshal(*req.Params, &params); err != nil {
+			SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("failed to unmarshal params for %v request: %v",
+				jsonrpc.RPCMempoolSnapshot, err), conn, req.ID)
+			return
+		}
+	}
+
+	if h.FeedManager.mempoolSnapshots == nil {
+		SendErrorMsg(ctx, jsonrpc.MethodNotFound, fmt.Sprintf("%v is not supported by the gateway", jsonrpc.RPCMempoolSnapshot), conn, req.ID)
+		return
+	}
+
+	if err := validateMempoolSnapshotFeed(h.connectionAccount.PendingTransactionStreaming, params.Filters, params.Include); err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, err.Error(), conn, req.ID)
+		return
+	}
+
+	response, err := newRPCMempoolSnapshotResponse(h.FeedManager.mempoolSnapshots, h.connectionAccount.AccountID, params)
+	if err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, err.Error(), conn, req.ID)
+		return
+	}
+
+	if err = conn.Reply(ctx, req.ID, response); err != nil {
+		h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
+	}
+}
+
+// ValidateMempoolSnapshotAccount checks that the account is entitled to the pendingTxs feed, which the mempool
+// snapshots are served from
+func ValidateMempoolSnapshotAccount(account *sdnmessage.Account, filters string) error {
+	return validateMempoolSnapshotFeed(account.PendingTransactionStreaming, filters, nil)
+}
+
+func validateMempoolSnapshotFeed(feedStreaming sdnmessage.BDNFeedService, filters string, includes []string) error {
+	var filterArgs []string
+	if filters != "" {
+		filterArgs = []string{filters}
+	}
+	return validateFeed(types.PendingTxsFeed, feedStreaming, includes, filterArgs)
+}
+
+// newRPCMempoolSnapshotResponse returns the requested page of a mempool snapshot of the account with the fields of the
+// pendingTxs feed selected by the include param
+func newRPCMempoolSnapshotResponse(snapshots *MempoolSnapshots, accountID types.AccountID, params jsonrpc.RPCMempoolSnapshotPayload) (rpcMempoolSnapshotResponse, error) {
+	includes, err := validateIncludeParam(types.PendingTxsFeed, params.Include, snapshots.txFromFieldIncludable)
+	if err != nil {
+		return rpcMempoolSnapshotResponse{}, err
+	}
+
+	page, err := snapshots.Page(accountID, params.Filters, params.Cursor, params.Limit)
+	if err != nil {
+		return rpcMempoolSnapshotResponse{}, err
+	}
+
+	clientReq := &clientReq{includes: includes, feed: types.PendingTxsFeed}
+	response := rpcMempoolSnapshotResponse{
+		Txs:          make([]rpcMempoolTx, 0, len(page.Txs)),
+		NextCursor:   page.NextCursor,
+		Total:        page.Total,
+		SnapshotTime: page.SnapshotTime.Format(bxgateway.MicroSecTimeFormat),
+	}
+	for _, tx := range page.Txs {
+		result := includeTx(clientReq, tx.Transaction)
+		if result.Time != nil {
+			// the time the gateway received the transaction rather than the time of the response
+			addTime := tx.Transaction.AddTime().Format(bxgateway.MicroSecTimeFormat)
+			result.Time = &addTime
+		}
+		response.Txs = append(response.Txs, rpcMempoolTx{TxResult: *result, EffectiveTip: tx.EffectiveTip.String()})
+	}
+	return response, nil
+}
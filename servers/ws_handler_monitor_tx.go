@@ -0,0 +1,113 @@
+package servers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/bloXroute-Labs/gateway/v2/services"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+type rpcTxStatus struct {
+	TxHash      string       `json:"txHash"`
+	Status      types.Status `json:"status"`
+	BlockNumber uint64       `json:"blockNumber,omitempty"`
+	ReplacedBy  string       `json:"replacedBy,omitempty"`
+}
+
+type rpcMonitorTxResponse struct {
+	Txs []rpcTxStatus `json:"txs"`
+}
+
+type rpcStopMonitorTxResponse struct {
+	TxHashes []string `json:"txHashes"`
+}
+
+func (h *handlerObj) handleRPCStartMonitorTx(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	hashes, ok := h.monitorTxHashes(ctx, conn, req, jsonrpc.RPCStartMonitoringTx)
+	if !ok {
+		return
+	}
+
+	statuses, err := h.FeedManager.txStatuses.Monitor(hashes, h.connectionAccount.AccountID)
+	if err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, err.Error(), conn, req.ID)
+		return
+	}
+	response := rpcMonitorTxResponse{Txs: make([]rpcTxStatus, 0, len(statuses))}
+	for _, status := range statuses {
+		response.Txs = append(response.Txs, newRPCTxStatus(status))
+	}
+
+	if err = conn.Reply(ctx, req.ID, response); err != nil {
+		h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
+	}
+}
+
+func (h *handlerObj) handleRPCStopMonitorTx(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	hashes, ok := h.monitorTxHashes(ctx, conn, req, jsonrpc.RPCStopMonitoringTx)
+	if !ok {
+		return
+	}
+
+	response := rpcStopMonitorTxResponse{TxHashes: make([]string, 0, len(hashes))}
+	for _, hash := range hashes {
+		if h.FeedManager.txStatuses.Unmonitor(hash, h.connectionAccount.AccountID) {
+			response.TxHashes = append(response.TxHashes, hash.Format(true))
+		}
+	}
+
+	if err := conn.Reply(ctx, req.ID, response); err != nil {
+		h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
+	}
+}
+
+// monitorTxHashes parses the hashes of start_monitor_transaction and stop_monitor_transaction requests, and replies
+// with an error if the request is invalid
+func (h *handlerObj) monitorTxHashes(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request, method jsonrpc.RPCRequestType) ([]types.SHA256Hash, bool) {
+	if req.Params == nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, errParamsValueIsMissing, conn, req.ID)
+		return nil, false
+	}
+
+	var params jsonrpc.RPCMonitorTxPayload
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("failed to unmarshal params for %v request: %v", method, err), conn, req.ID)
+		return nil, false
+	}
+	if len(params.TransactionHashes) == 0 {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, "transaction_hashes is missing in the request", conn, req.ID)
+		return nil, false
+	}
+
+	hashes := make([]types.SHA256Hash, 0, len(params.TransactionHashes))
+	for _, txHash := range params.TransactionHashes {
+		hash, err := types.NewSHA256HashFromString(txHash)
+		if err != nil {
+			SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("invalid transaction hash %v", txHash), conn, req.ID)
+			return nil, false
+		}
+		hashes = append(hashes, hash)
+	}
+
+	if h.FeedManager.txStatuses == nil {
+		SendErrorMsg(ctx, jsonrpc.MethodNotFound, fmt.Sprintf("%v is not supported by the gateway", method), conn, req.ID)
+		return nil, false
+	}
+	return hashes, true
+}
+
+func newRPCTxStatus(status services.TxStatus) rpcTxStatus {
+	result := rpcTxStatus{
+		TxHash:      status.Hash.Format(true),
+		Status:      status.Status,
+		BlockNumber: status.BlockNumber,
+	}
+	if status.ReplacedBy != (types.SHA256Hash{}) {
+		result.ReplacedBy = status.ReplacedBy.Format(true)
+	}
+	return result
+}
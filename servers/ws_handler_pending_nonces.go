@@ -0,0 +1,83 @@
+package servers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/bloXroute-Labs/gateway/v2/services"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+type rpcPendingNonceTx struct {
+	Nonce    uint64    `json:"nonce"`
+	TxHash   string    `json:"txHash"`
+	SeenTime time.Time `json:"seenTime"`
+}
+
+type rpcPendingNoncesResponse struct {
+	Address        string              `json:"address"`
+	ConfirmedNonce *uint64             `json:"confirmedNonce,omitempty"`
+	LowestNonce    *uint64             `json:"lowestNonce,omitempty"`
+	HighestNonce   *uint64             `json:"highestNonce,omitempty"`
+	NextNonce      *uint64             `json:"nextNonce,omitempty"`
+	Txs            []rpcPendingNonceTx `json:"txs"`
+	Gaps           []uint64            `json:"gaps"`
+}
+
+func (h *handlerObj) handleRPCPendingNonces(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	if req.Params == nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, errParamsValueIsMissing, conn, req.ID)
+		return
+	}
+
+	var params jsonrpc.RPCPendingNoncesPayload
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("failed to unmarshal params for %v request: %v",
+			jsonrpc.RPCPendingNonces, err), conn, req.ID)
+		return
+	}
+	if !common.IsHexAddress(params.Address) {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("invalid address %v", params.Address), conn, req.ID)
+		return
+	}
+
+	if h.FeedManager.pendingNonces == nil {
+		SendErrorMsg(ctx, jsonrpc.MethodNotFound, fmt.Sprintf("%v is not supported by the gateway", jsonrpc.RPCPendingNonces), conn, req.ID)
+		return
+	}
+
+	pending, _ := h.FeedManager.pendingNonces.PendingNonces(common.HexToAddress(params.Address))
+	if err := conn.Reply(ctx, req.ID, newRPCPendingNoncesResponse(pending)); err != nil {
+		h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
+	}
+}
+
+// newRPCPendingNoncesResponse leaves out the nonces which are unknown to the gateway
+func newRPCPendingNoncesResponse(pending services.PendingNonces) rpcPendingNoncesResponse {
+	response := rpcPendingNoncesResponse{
+		Address: pending.Address.Hex(),
+		Txs:     make([]rpcPendingNonceTx, 0, len(pending.Txs)),
+		Gaps:    pending.Gaps,
+	}
+	if response.Gaps == nil {
+		response.Gaps = []uint64{}
+	}
+	if pending.HasConfirmedNonce {
+		response.ConfirmedNonce = &pending.ConfirmedNonce
+	}
+	if len(pending.Txs) > 0 {
+		response.LowestNonce = &pending.LowestNonce
+		response.HighestNonce = &pending.HighestNonce
+	}
+	if len(pending.Txs) > 0 || pending.HasConfirmedNonce {
+		response.NextNonce = &pending.NextNonce
+	}
+	for _, tx := range pending.Txs {
+		response.Txs = append(response.Txs, rpcPendingNonceTx{Nonce: tx.Nonce, TxHash: tx.Hash.Format(true), SeenTime: tx.SeenTime})
+	}
+	return response
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/bloXroute-Labs/gateway/v2"
 	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
@@ -44,8 +45,9 @@ func (h *handlerObj) handleRPCSubscribe(ctx context.Context, conn *jsonrpc2.Conn
 		filters = request.expr.String()
 	}
 	ro := types.ReqOptions{
-		Filters:  filters,
-		Includes: strings.Join(request.includes, ","),
+		Filters:      filters,
+		Includes:     strings.Join(request.includes, ","),
+		Backpressure: request.backpressure,
 	}
 	ci := types.ClientInfo{
 		RemoteAddress: h.remoteAddress,
@@ -78,6 +80,13 @@ func (h *handlerObj) handleRPCSubscribe(ctx context.Context, conn *jsonrpc2.Conn
 		filters,
 		"")
 
+	if request.resumeFrom != nil {
+		if err = h.replayFeedHistory(ctx, conn, subscriptionID, request); err != nil {
+			SendErrorMsg(ctx, jsonrpc.InternalError, err.Error(), conn, req.ID)
+			return
+		}
+	}
+
 	if request.MultiTxs {
 		if feedName != types.NewTxsFeed && feedName != types.PendingTxsFeed {
 			log.Debugf("multi tx support only in new txs or pending txs, subscription id %v, account id %v, remote addr %v", subscriptionID, h.connectionAccount.AccountID, h.remoteAddress)
@@ -111,6 +120,11 @@ func (h *handlerObj) handleRPCSubscribeNotify(ctx context.Context, conn *jsonrpc
 				}
 				return
 			}
+			if request.resumeFrom != nil && notificationSeq(notification) <= *request.resumeFrom {
+				// already replayed from the feed history
+				continue
+			}
+			start := time.Now()
 
 			switch feedName {
 			case types.NewTxsFeed:
@@ -123,7 +137,7 @@ func (h *handlerObj) handleRPCSubscribeNotify(ctx context.Context, conn *jsonrpc
 				if h.sendTxNotification(ctx, subscriptionID, request, conn, &tx.NewTransactionNotification) != nil {
 					return
 				}
-			case types.BDNBlocksFeed, types.NewBlocksFeed, types.NewBeaconBlocksFeed, types.BDNBeaconBlocksFeed:
+			case types.BDNBlocksFeed, types.NewBlocksFeed, types.NewBeaconBlocksFeed, types.BDNBeaconBlocksFeed, types.ReorgFeed, types.TransactionStatusFeed, types.GasEstimateFeed, types.BundleStatusFeed:
 				if h.sendNotification(ctx, subscriptionID, request, conn, notification) != nil {
 					return
 				}
@@ -144,6 +158,7 @@ func (h *handlerObj) handleRPCSubscribeNotify(ctx context.Context, conn *jsonrpc
 					return
 				}
 			}
+			sub.observeSend(start)
 		}
 	}
 }
@@ -171,8 +186,9 @@ func (h *handlerObj) sendTxNotification(ctx context.Context, subscriptionID stri
 func (h *handlerObj) sendTxReceiptNotification(ctx context.Context, subscriptionID string, clientReq *clientReq, conn *jsonrpc2.Conn, notification types.Notification) error {
 	response := txReceiptResponse{
 		Subscription: subscriptionID,
+		Seq:          notificationSeq(notification),
 	}
-	content := notification.WithFields(clientReq.includes).(*types.TxReceiptsNotification)
+	content := filterTxReceipts(clientReq, notification.(*types.TxReceiptsNotification))
 	for _, receipt := range content.Receipts {
 		response.Result = receipt
 		err := conn.Notify(ctx, "subscribe", response)
@@ -185,6 +201,39 @@ func (h *handlerObj) sendTxReceiptNotification(ctx context.Context, subscription
 	return nil
 }
 
+// replayFeedHistory sends the notifications following the resume_from cursor of the request, and moves the cursor
+// to the last one sent. The subscription is already registered, so the live notifications it skips are buffered
+func (h *handlerObj) replayFeedHistory(ctx context.Context, conn *jsonrpc2.Conn, subscriptionID string, request *clientReq) error {
+	history, ok := h.FeedManager.history[request.feed]
+	if !ok {
+		return fmt.Errorf("%v feed has no history", request.feed)
+	}
+	notifications, err := history.since(*request.resumeFrom)
+	if err != nil {
+		h.log.Errorf("failed to read %v history for subscriptionID %v: %v", request.feed, subscriptionID, err)
+		return fmt.Errorf("failed to read %v history", request.feed)
+	}
+	if len(notifications) > 0 && notifications[0].Seq() > *request.resumeFrom+1 {
+		h.log.Debugf("%v history of subscriptionID %v starts at %v, notifications after %v are missing", request.feed, subscriptionID, notifications[0].Seq(), *request.resumeFrom)
+	}
+
+	for _, notification := range notifications {
+		if !notificationForAccount(notification, h.connectionAccount.AccountID) {
+			continue
+		}
+		if request.feed == types.TxReceiptsFeed {
+			err = h.sendTxReceiptNotification(ctx, subscriptionID, request, conn, notification)
+		} else {
+			err = h.sendNotification(ctx, subscriptionID, request, conn, notification)
+		}
+		if err != nil {
+			return err
+		}
+		*request.resumeFrom = notification.Seq()
+	}
+	return nil
+}
+
 func (h *handlerObj) subscribeMultiTxs(ctx context.Context, feedChan chan types.Notification, subscriptionID string, clientReq *clientReq, conn *jsonrpc2.Conn, req *jsonrpc2.Request, feedName types.FeedType) error {
 	for {
 		select {
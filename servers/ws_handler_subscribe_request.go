@@ -11,12 +11,12 @@ import (
 	"github.com/bloXroute-Labs/gateway/v2/types"
 	"github.com/bloXroute-Labs/gateway/v2/utils"
 	"github.com/sourcegraph/jsonrpc2"
-	"github.com/zhouzhuojie/conditions"
 )
 
 var (
 	availableFeeds = []types.FeedType{types.NewTxsFeed, types.NewBlocksFeed, types.BDNBlocksFeed, types.PendingTxsFeed,
-		types.OnBlockFeed, types.TxReceiptsFeed, types.NewBeaconBlocksFeed, types.BDNBeaconBlocksFeed}
+		types.OnBlockFeed, types.TxReceiptsFeed, types.NewBeaconBlocksFeed, types.BDNBeaconBlocksFeed, types.ReorgFeed, types.TransactionStatusFeed,
+		types.GasEstimateFeed, types.BundleStatusFeed}
 
 	txContentFields = []string{"tx_contents.nonce", "tx_contents.tx_hash",
 		"tx_contents.gas_price", "tx_contents.gas", "tx_contents.to", "tx_contents.value", "tx_contents.input",
@@ -27,13 +27,18 @@ var (
 
 	txContentFieldsWithFrom = append(txContentFields, "tx_contents.from")
 
-	validTxParams        = append(txContentFields, "tx_contents", "tx_contents.from", "tx_hash", "local_region", "time", "raw_tx")
+	validTxParams        = append(txContentFields, "tx_contents", "tx_contents.from", decodedInputField, "tx_hash", "local_region", "time", "raw_tx")
 	validBlockParams     = append(txContentFields, "tx_contents.from", "hash", "header", "transactions", "uncles", "future_validator_info", "withdrawals")
 	validTxReceiptParams = []string{"block_hash", "block_number", "contract_address",
 		"cumulative_gas_used", "effective_gas_price", "from", "gas_used", "logs", "logs_bloom",
 		"status", "to", "transaction_hash", "transaction_index", "type", "txs_count"}
-	validOnBlockParams     = []string{"name", "response", "block_height", "tag"}
-	validBeaconBlockParams = []string{"hash", "header", "slot", "body"}
+	validOnBlockParams      = []string{"name", "response", "block_height", "tag"}
+	validBeaconBlockParams  = []string{"hash", "header", "slot", "body"}
+	validReorgParams        = []string{"dropped", "added"}
+	validTxStatusParams     = []string{"transaction_hash", "status", "block_number", "replaced_by"}
+	validGasEstimateParams  = []string{"block_number", "block_hash", "base_fee", "next_base_fee", "tiers", "mempool_tips"}
+	validBundleStatusParams = []string{"bundle_hash", "status", "target_block_number", "block_number", "block_hash", "builder",
+		"fee_recipient", "included_txs"}
 
 	availableFeedsMap = make(map[types.FeedType]struct{})
 	validParamsMap    = make(map[types.FeedType]map[string]struct{})
@@ -46,14 +51,18 @@ func init() {
 	}
 
 	validParamsMap = map[types.FeedType]map[string]struct{}{
-		types.NewTxsFeed:          stringSliceToSet(validTxParams),
-		types.PendingTxsFeed:      stringSliceToSet(validTxParams),
-		types.BDNBlocksFeed:       stringSliceToSet(validBlockParams),
-		types.NewBlocksFeed:       stringSliceToSet(validBlockParams),
-		types.OnBlockFeed:         stringSliceToSet(validOnBlockParams),
-		types.TxReceiptsFeed:      stringSliceToSet(validTxReceiptParams),
-		types.NewBeaconBlocksFeed: stringSliceToSet(validBeaconBlockParams),
-		types.BDNBeaconBlocksFeed: stringSliceToSet(validBeaconBlockParams),
+		types.NewTxsFeed:            stringSliceToSet(validTxParams),
+		types.PendingTxsFeed:        stringSliceToSet(validTxParams),
+		types.BDNBlocksFeed:         stringSliceToSet(validBlockParams),
+		types.NewBlocksFeed:         stringSliceToSet(validBlockParams),
+		types.OnBlockFeed:           stringSliceToSet(validOnBlockParams),
+		types.TxReceiptsFeed:        stringSliceToSet(validTxReceiptParams),
+		types.NewBeaconBlocksFeed:   stringSliceToSet(validBeaconBlockParams),
+		types.BDNBeaconBlocksFeed:   stringSliceToSet(validBeaconBlockParams),
+		types.ReorgFeed:             stringSliceToSet(validReorgParams),
+		types.TransactionStatusFeed: stringSliceToSet(validTxStatusParams),
+		types.GasEstimateFeed:       stringSliceToSet(validGasEstimateParams),
+		types.BundleStatusFeed:      stringSliceToSet(validBundleStatusParams),
 	}
 }
 
@@ -107,9 +116,28 @@ func (h *handlerObj) createClientReq(req *jsonrpc2.Request) (*clientReq, error)
 
 	request.options.Include = requestedFields
 
-	var expr conditions.Expr
+	var calldata *calldataABI
+	var events *eventABI
+	switch request.feed {
+	case types.NewTxsFeed, types.PendingTxsFeed:
+		calldata, err = newCalldataABI(request.options.ABI, h.FeedManager.abiRegistry)
+	case types.TxReceiptsFeed:
+		events, err = newEventABI(request.options.ABI, h.FeedManager.abiRegistry)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error creating ABI: %w", err)
+	}
+	if calldata == nil && utils.Exists(decodedInputField, request.options.Include) {
+		return nil, fmt.Errorf("including %v requires the abi option", decodedInputField)
+	}
+
+	var expr *filterExpr
 	if request.options.Filters != "" {
-		expr, err = validateFilters(request.options.Filters, h.txFromFieldIncludable)
+		if request.feed == types.TxReceiptsFeed {
+			expr, err = validateReceiptFilters(request.options.Filters)
+		} else {
+			expr, err = validateFilters(request.options.Filters, h.txFromFieldIncludable, calldata)
+		}
 		if err != nil {
 			h.log.Debugf("error when creating filters. request id: %v. method: %v. params: %s. remote address: %v account id: %v error - %v",
 				req.ID, req.Method, *req.Params, h.remoteAddress, h.connectionAccount.AccountID, err.Error())
@@ -129,19 +157,32 @@ func (h *handlerObj) createClientReq(req *jsonrpc2.Request) (*clientReq, error)
 		feedStreaming = h.connectionAccount.NewTransactionStreaming
 	case types.PendingTxsFeed:
 		feedStreaming = h.connectionAccount.PendingTransactionStreaming
-	case types.BDNBlocksFeed, types.NewBlocksFeed, types.NewBeaconBlocksFeed, types.BDNBeaconBlocksFeed:
+	case types.BDNBlocksFeed, types.NewBlocksFeed, types.NewBeaconBlocksFeed, types.BDNBeaconBlocksFeed, types.ReorgFeed, types.GasEstimateFeed, types.BundleStatusFeed:
 		feedStreaming = h.connectionAccount.NewBlockStreaming
 	case types.OnBlockFeed:
 		feedStreaming = h.connectionAccount.OnBlockFeed
 	case types.TxReceiptsFeed:
 		feedStreaming = h.connectionAccount.TransactionReceiptFeed
+	case types.TransactionStatusFeed:
+		feedStreaming = h.connectionAccount.TransactionStateFeed
 	}
 
-	err = h.validateFeed(request.feed, feedStreaming, request.options.Include, filters)
+	err = validateFeed(request.feed, feedStreaming, request.options.Include, filters)
 	if err != nil {
 		return nil, err
 	}
 
+	backpressure, err := parseBackpressurePolicy(request.feed, request.options.Backpressure)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.options.ResumeFrom != nil {
+		if err = h.FeedManager.validateResumeFrom(request.feed, *request.options.ResumeFrom); err != nil {
+			return nil, err
+		}
+	}
+
 	calls := make(map[string]*RPCCall)
 	if request.feed == types.OnBlockFeed {
 		for idx, callParams := range request.options.CallParams {
@@ -156,15 +197,19 @@ func (h *handlerObj) createClientReq(req *jsonrpc2.Request) (*clientReq, error)
 	}
 
 	return &clientReq{
-		includes: request.options.Include,
-		feed:     request.feed,
-		expr:     expr,
-		calls:    &calls,
-		MultiTxs: request.options.MultiTxs,
+		includes:     request.options.Include,
+		feed:         request.feed,
+		expr:         expr,
+		calldata:     calldata,
+		events:       events,
+		calls:        &calls,
+		MultiTxs:     request.options.MultiTxs,
+		backpressure: backpressure,
+		resumeFrom:   request.options.ResumeFrom,
 	}, nil
 }
 
-func (h *handlerObj) validateFeed(feedName types.FeedType, feedStreaming sdnmessage.BDNFeedService, includes, filters []string) error {
+func validateFeed(feedName types.FeedType, feedStreaming sdnmessage.BDNFeedService, includes, filters []string) error {
 	expireDateTime, _ := time.Parse(bxgateway.TimeDateLayoutISO, feedStreaming.ExpireDate)
 	if time.Now().UTC().After(expireDateTime) {
 		return fmt.Errorf("%v is not allowed or date has been expired", feedName)
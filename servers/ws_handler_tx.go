@@ -57,11 +57,33 @@ func (h *handlerObj) handleRPCTx(ctx context.Context, conn *jsonrpc2.Conn, req *
 		ws = connections.NewRPCConn(h.connectionAccount.AccountID, h.remoteAddress, h.FeedManager.networkNum, utils.Websocket)
 	}
 
+	if params.Condition != "" {
+		txHash, err := HandleConditionalTransaction(h.FeedManager, params.Transaction, params.Condition, params.ConditionExpiryBlock, ws,
+			params.ValidatorsOnly, params.NextValidator, params.NodeValidation, params.FrontRunningProtection, params.Fallback, params.Simulate)
+		if err != nil {
+			SendErrorMsg(ctx, txErrorCode(err), err.Error(), conn, req.ID)
+			return
+		}
+		if err = conn.Reply(ctx, req.ID, rpcTxResponse{TxHash: txHash}); err != nil {
+			h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
+			return
+		}
+		h.log.Infof("blxr_tx: hash - 0x%v held until %v", txHash, params.Condition)
+		return
+	}
+
+	if params.Simulate {
+		if err = SimulateTransaction(h.FeedManager, params.Transaction); err != nil {
+			SendErrorMsg(ctx, txErrorCode(err), err.Error(), conn, req.ID)
+			return
+		}
+	}
+
 	txHash, ok, err := HandleSingleTransaction(h.FeedManager, params.Transaction, nil, ws, params.ValidatorsOnly,
 		params.NextValidator, params.NodeValidation, params.FrontRunningProtection, params.Fallback,
 		h.FeedManager.nextValidatorMap, h.FeedManager.validatorStatusMap)
 	if err != nil {
-		SendErrorMsg(ctx, jsonrpc.InvalidParams, err.Error(), conn, req.ID)
+		SendErrorMsg(ctx, txErrorCode(err), err.Error(), conn, req.ID)
 	}
 	if !ok {
 		return
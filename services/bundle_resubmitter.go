@@ -0,0 +1,143 @@
+package services
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
+	"github.com/bloXroute-Labs/gateway/v2/connections"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// maxResubmittedBundles bounds the bundles the gateway resubmits for the next blocks
+const maxResubmittedBundles = 1000
+
+// ErrTooManyResubmittedBundles is returned when the gateway already resubmits maxResubmittedBundles bundles
+var ErrTooManyResubmittedBundles = errors.New("too many resubmitted bundles")
+
+type resubmittedBundle struct {
+	bundle      bxmessage.MEVBundle
+	source      connections.Conn
+	txHashes    []common.Hash
+	targetBlock uint64
+}
+
+// BundleResubmitter submits the bundles targeting several blocks again for each new block, until a block includes
+// their transactions or their max block is reached. The bundles keep their UUID, so the builders replace the bundle
+// submitted for the previous block, and a bundle included is canceled
+type BundleResubmitter struct {
+	lock    sync.Mutex
+	bundles map[string]*resubmittedBundle
+	submit  func(bundle bxmessage.MEVBundle, source connections.Conn)
+}
+
+// NewBundleResubmitter creates a BundleResubmitter submitting the bundles with submit, as if source submitted them
+func NewBundleResubmitter(submit func(bundle bxmessage.MEVBundle, source connections.Conn)) *BundleResubmitter {
+	return &BundleResubmitter{
+		bundles: make(map[string]*resubmittedBundle),
+		submit:  submit,
+	}
+}
+
+// Add starts resubmitting the bundle submitted by source for the next blocks until its max block number
+func (r *BundleResubmitter) Add(bundle bxmessage.MEVBundle, source connections.Conn) error {
+	targetBlock, err := bundleTargetBlock(&bundle)
+	if err != nil {
+		return err
+	}
+	txHashes, err := bundleTxHashes(&bundle)
+	if err != nil {
+		return err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	hash := bundleHashKey(bundle.BundleHash)
+	if _, ok := r.bundles[hash]; ok {
+		return nil
+	}
+	if len(r.bundles) >= maxResubmittedBundles {
+		return ErrTooManyResubmittedBundles
+	}
+	r.bundles[hash] = &resubmittedBundle{
+		bundle:      bundle,
+		source:      source,
+		txHashes:    txHashes,
+		targetBlock: targetBlock,
+	}
+	return nil
+}
+
+// OnBlock submits the bundles not included by the block for the next block. The bundles whose transactions the block
+// includes are canceled, and the bundles which reached their max block number or max timestamp are dropped
+func (r *BundleResubmitter) OnBlock(block *ethtypes.Block) {
+	r.lock.Lock()
+	if len(r.bundles) == 0 {
+		r.lock.Unlock()
+		return
+	}
+
+	blockTxs := make(map[common.Hash]struct{}, len(block.Transactions()))
+	for _, tx := range block.Transactions() {
+		blockTxs[tx.Hash()] = struct{}{}
+	}
+
+	var submissions []*resubmittedBundle
+	nextBlock := block.NumberU64() + 1
+	for hash, resubmitted := range r.bundles {
+		switch {
+		case resubmitted.included(blockTxs):
+			delete(r.bundles, hash)
+			// any transaction included uses the nonce of its sender, the submitted bundles can no longer be included
+			submissions = append(submissions, resubmitted.cancellation(nextBlock))
+		case nextBlock > resubmitted.bundle.MaxBlockNumber ||
+			(resubmitted.bundle.MaxTimestamp > 0 && block.Time() >= uint64(resubmitted.bundle.MaxTimestamp)):
+			delete(r.bundles, hash)
+		case nextBlock > resubmitted.targetBlock:
+			resubmitted.targetBlock = nextBlock
+			submissions = append(submissions, resubmitted.resubmission(nextBlock))
+		}
+	}
+	r.lock.Unlock()
+
+	for _, submission := range submissions {
+		r.submit(submission.bundle, submission.source)
+	}
+}
+
+// Size returns the number of bundles resubmitted for the next blocks
+func (r *BundleResubmitter) Size() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return len(r.bundles)
+}
+
+func (b *resubmittedBundle) included(blockTxs map[common.Hash]struct{}) bool {
+	for _, txHash := range b.txHashes {
+		if _, ok := blockTxs[txHash]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// resubmission returns the bundle targeting the block, it is not resubmitted again by the gateway it is submitted to
+func (b *resubmittedBundle) resubmission(blockNumber uint64) *resubmittedBundle {
+	bundle := b.bundle
+	bundle.BlockNumber = hexutil.EncodeUint64(blockNumber)
+	bundle.MaxBlockNumber = 0
+	bundle.SetHash()
+	return &resubmittedBundle{bundle: bundle, source: b.source}
+}
+
+// cancellation returns the bundle without transactions which cancels the bundles with the same UUID
+func (b *resubmittedBundle) cancellation(blockNumber uint64) *resubmittedBundle {
+	cancellation := b.resubmission(blockNumber)
+	cancellation.bundle.Transactions = nil
+	cancellation.bundle.RevertingHashes = nil
+	cancellation.bundle.SetHash()
+	return cancellation
+}
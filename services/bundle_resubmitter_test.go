@@ -0,0 +1,61 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
+	"github.com/bloXroute-Labs/gateway/v2/connections"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundleResubmitter(t *testing.T) {
+	var submitted []bxmessage.MEVBundle
+	resubmitter := NewBundleResubmitter(func(bundle bxmessage.MEVBundle, _ connections.Conn) {
+		submitted = append(submitted, bundle)
+	})
+
+	tx1 := newGasOracleTx(t, 1, 21000, 100, 10)
+	tx2 := newGasOracleTx(t, 2, 21000, 100, 10)
+	tx3 := newGasOracleTx(t, 3, 21000, 100, 10)
+
+	included := newTrackedBundle(t, "0xaa", 10, tx1, tx2)
+	included.UUID = "5f6e3e2c-9e62-4d5a-8bb0-0f1a9d1e8c11"
+	included.MaxBlockNumber = 13
+	require.NoError(t, resubmitter.Add(*included, nil))
+	expired := newTrackedBundle(t, "0xbb", 10, tx3)
+	expired.MaxBlockNumber = 11
+	require.NoError(t, resubmitter.Add(*expired, nil))
+	assert.Error(t, resubmitter.Add(bxmessage.MEVBundle{BundleHash: "0xcc", BlockNumber: "0xa"}, nil))
+
+	// the bundles are submitted again for the block following a block which does not include them
+	resubmitter.OnBlock(newBuilderBlock(9, ""))
+	assert.Empty(t, submitted)
+	resubmitter.OnBlock(newBuilderBlock(10, ""))
+	require.Len(t, submitted, 2)
+	for _, bundle := range submitted {
+		assert.Equal(t, "0xb", bundle.BlockNumber)
+		assert.Zero(t, bundle.MaxBlockNumber)
+	}
+
+	// a block seen again does not submit the bundles twice
+	resubmitter.OnBlock(newBuilderBlock(10, ""))
+	assert.Len(t, submitted, 2)
+
+	// the bundle reaching its max block is dropped
+	submitted = nil
+	resubmitter.OnBlock(newBuilderBlock(11, ""))
+	require.Len(t, submitted, 1)
+	assert.Equal(t, "0xaa", submitted[0].BundleHash)
+	assert.Equal(t, "0xc", submitted[0].BlockNumber)
+	assert.Equal(t, included.UUID, submitted[0].UUID)
+	assert.Equal(t, 1, resubmitter.Size())
+
+	// the bundle included is canceled
+	submitted = nil
+	resubmitter.OnBlock(newBuilderBlock(12, "", tx2))
+	require.Len(t, submitted, 1)
+	assert.Equal(t, included.UUID, submitted[0].UUID)
+	assert.Empty(t, submitted[0].Transactions)
+	assert.Equal(t, 0, resubmitter.Size())
+}
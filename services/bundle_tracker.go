@@ -0,0 +1,302 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// maxTrackedBundles bounds the bundles tracked by the gateway, including the settled bundles kept for the queries
+	maxTrackedBundles = 10000
+	// settledBundleRetention is how long the status of a settled bundle remains available
+	settledBundleRetention = time.Hour
+)
+
+// ErrTooManyTrackedBundles is returned when the gateway already tracks maxTrackedBundles bundles
+var ErrTooManyTrackedBundles = errors.New("too many tracked bundles")
+
+// BundleStatus is the current status of a tracked bundle
+type BundleStatus struct {
+	Hash              string
+	Status            types.BundleStatus
+	TargetBlockNumber uint64
+	// BlockNumber, BlockHash, Builder and FeeRecipient describe the latest block which included transactions of the bundle
+	BlockNumber  uint64
+	BlockHash    common.Hash
+	Builder      string
+	FeeRecipient common.Address
+	TxHashes     []common.Hash
+	IncludedTxs  []common.Hash
+}
+
+type trackedBundle struct {
+	// accounts are the accounts which submitted the bundle, the status of the bundle is reported to them only
+	accounts     []types.AccountID
+	status       types.BundleStatus
+	targetBlock  uint64
+	txHashes     []common.Hash
+	included     map[common.Hash]struct{}
+	blockNumber  uint64
+	blockHash    common.Hash
+	builder      string
+	feeRecipient common.Address
+	settledTime  time.Time
+}
+
+// BundleTracker follows the bundles submitted to the gateway through the blocks up to their target block, and
+// notifies whether their transactions were included, and by the block of which builder
+type BundleTracker struct {
+	clock   utils.Clock
+	lock    sync.RWMutex
+	bundles map[string]*trackedBundle
+	txs     map[common.Hash]map[string]struct{}
+	notify  func(notification types.Notification)
+}
+
+// NewBundleTracker creates a BundleTracker reporting the status of the bundles with notify
+func NewBundleTracker(clock utils.Clock, notify func(notification types.Notification)) *BundleTracker {
+	return &BundleTracker{
+		clock:   clock,
+		bundles: make(map[string]*trackedBundle),
+		txs:     make(map[common.Hash]map[string]struct{}),
+		notify:  notify,
+	}
+}
+
+// Track starts following the bundle submitted by the account until its target block, or its max block if it is
+// resubmitted. A bundle already tracked is not tracked again, its status is also reported to the account
+func (t *BundleTracker) Track(bundle *bxmessage.MEVBundle, accountID types.AccountID) error {
+	if bundle.BundleHash == "" {
+		return fmt.Errorf("bundle %v has no hash", bundle.UUID)
+	}
+	targetBlock, err := bundleTargetBlock(bundle)
+	if err != nil {
+		return err
+	}
+	if bundle.MaxBlockNumber > targetBlock {
+		targetBlock = bundle.MaxBlockNumber
+	}
+	txHashes, err := bundleTxHashes(bundle)
+	if err != nil {
+		return err
+	}
+	hash := bundleHashKey(bundle.BundleHash)
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if tracked, ok := t.bundles[hash]; ok {
+		if !tracked.submittedBy(accountID) {
+			tracked.accounts = append(tracked.accounts, accountID)
+			t.notifyStatus(hash, tracked, []types.AccountID{accountID})
+		}
+		return nil
+	}
+	if len(t.bundles) >= maxTrackedBundles {
+		return ErrTooManyTrackedBundles
+	}
+
+	tracked := &trackedBundle{
+		accounts:    []types.AccountID{accountID},
+		status:      types.BundlePending,
+		targetBlock: targetBlock,
+		txHashes:    txHashes,
+		included:    make(map[common.Hash]struct{}),
+	}
+	t.bundles[hash] = tracked
+	for _, txHash := range txHashes {
+		bundles, ok := t.txs[txHash]
+		if !ok {
+			bundles = make(map[string]struct{})
+			t.txs[txHash] = bundles
+		}
+		bundles[hash] = struct{}{}
+	}
+	t.notifyStatus(hash, tracked, tracked.accounts)
+	return nil
+}
+
+// Status returns the current status of the bundle, false if it is not tracked or was not submitted by the account
+func (t *BundleTracker) Status(hash string, accountID types.AccountID) (BundleStatus, bool) {
+	hash = bundleHashKey(hash)
+
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	tracked, ok := t.bundles[hash]
+	if !ok || !tracked.submittedBy(accountID) {
+		return BundleStatus{}, false
+	}
+	return tracked.bundleStatus(hash), true
+}
+
+// OnBlock records the transactions of the pending bundles included in the canonical block, and settles the bundles
+// whose transactions are all included, or whose target block is reached. A settled bundle is not updated by a later
+// block
+func (t *BundleTracker) OnBlock(block *ethtypes.Block) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if len(t.bundles) == 0 {
+		return
+	}
+
+	for _, blockTx := range block.Transactions() {
+		for hash := range t.txs[blockTx.Hash()] {
+			tracked := t.bundles[hash]
+			tracked.included[blockTx.Hash()] = struct{}{}
+			tracked.blockNumber = block.NumberU64()
+			tracked.blockHash = block.Hash()
+			tracked.builder = blockBuilder(block.Extra())
+			tracked.feeRecipient = block.Coinbase()
+		}
+	}
+
+	now := t.clock.Now()
+	for hash, tracked := range t.bundles {
+		if tracked.status != types.BundlePending {
+			if now.Sub(tracked.settledTime) > settledBundleRetention {
+				delete(t.bundles, hash)
+			}
+			continue
+		}
+
+		switch {
+		case len(tracked.included) == len(tracked.txHashes):
+			tracked.status = types.BundleIncluded
+		case block.NumberU64() < tracked.targetBlock:
+			continue
+		case len(tracked.included) > 0:
+			tracked.status = types.BundlePartiallyIncluded
+		default:
+			tracked.status = types.BundleExpired
+		}
+		tracked.settledTime = now
+		for _, txHash := range tracked.txHashes {
+			delete(t.txs[txHash], hash)
+			if len(t.txs[txHash]) == 0 {
+				delete(t.txs, txHash)
+			}
+		}
+		t.notifyStatus(hash, tracked, tracked.accounts)
+	}
+}
+
+// Size returns the number of tracked bundles
+func (t *BundleTracker) Size() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return len(t.bundles)
+}
+
+// notifyStatus reports the status of the bundle to the accounts
+func (t *BundleTracker) notifyStatus(hash string, tracked *trackedBundle, accountIDs []types.AccountID) {
+	notification := tracked.bundleStatus(hash).Notification()
+	notification.AccountIDs = append([]types.AccountID(nil), accountIDs...)
+	t.notify(notification)
+}
+
+func (b *trackedBundle) submittedBy(accountID types.AccountID) bool {
+	for _, id := range b.accounts {
+		if id == accountID {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *trackedBundle) bundleStatus(hash string) BundleStatus {
+	status := BundleStatus{
+		Hash:              hash,
+		Status:            b.status,
+		TargetBlockNumber: b.targetBlock,
+		BlockNumber:       b.blockNumber,
+		BlockHash:         b.blockHash,
+		Builder:           b.builder,
+		FeeRecipient:      b.feeRecipient,
+		TxHashes:          b.txHashes,
+		IncludedTxs:       make([]common.Hash, 0, len(b.included)),
+	}
+	for _, txHash := range b.txHashes {
+		if _, ok := b.included[txHash]; ok {
+			status.IncludedTxs = append(status.IncludedTxs, txHash)
+		}
+	}
+	return status
+}
+
+// Notification formats the status of the bundle as a bundle status feed notification
+func (s BundleStatus) Notification() *types.BundleStatusNotification {
+	notification := &types.BundleStatusNotification{
+		BundleHash:        s.Hash,
+		Status:            s.Status,
+		TargetBlockNumber: s.TargetBlockNumber,
+		BlockNumber:       s.BlockNumber,
+		Builder:           s.Builder,
+		IncludedTxs:       make([]string, 0, len(s.IncludedTxs)),
+	}
+	if s.BlockNumber != 0 {
+		notification.BlockHash = s.BlockHash.Hex()
+		notification.FeeRecipient = s.FeeRecipient.Hex()
+	}
+	for _, txHash := range s.IncludedTxs {
+		notification.IncludedTxs = append(notification.IncludedTxs, txHash.Hex())
+	}
+	return notification
+}
+
+// bundleTargetBlock returns the block the bundle is submitted for
+func bundleTargetBlock(bundle *bxmessage.MEVBundle) (uint64, error) {
+	targetBlock, err := strconv.ParseUint(strings.TrimPrefix(bundle.BlockNumber, "0x"), 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid block number %v of bundle %v: %v", bundle.BlockNumber, bundle.BundleHash, err)
+	}
+	return targetBlock, nil
+}
+
+// bundleTxHashes returns the hashes of the transactions of the bundle, the bundle must have transactions
+func bundleTxHashes(bundle *bxmessage.MEVBundle) ([]common.Hash, error) {
+	if len(bundle.Transactions) == 0 {
+		return nil, fmt.Errorf("bundle %v has no transactions", bundle.BundleHash)
+	}
+	txHashes := make([]common.Hash, 0, len(bundle.Transactions))
+	for _, rawTx := range bundle.Transactions {
+		txBytes, err := types.DecodeHex(rawTx)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transaction of bundle %v: %v", bundle.BundleHash, err)
+		}
+		var tx ethtypes.Transaction
+		if err = tx.UnmarshalBinary(txBytes); err != nil {
+			return nil, fmt.Errorf("invalid transaction of bundle %v: %v", bundle.BundleHash, err)
+		}
+		txHashes = append(txHashes, tx.Hash())
+	}
+	return txHashes, nil
+}
+
+// bundleHashKey formats the bundle hash the way the gateway returns it to the clients, lower case with the 0x prefix
+func bundleHashKey(hash string) string {
+	return "0x" + strings.TrimPrefix(strings.ToLower(hash), "0x")
+}
+
+// blockBuilder returns the extra data of the block the builders sign their blocks with, without the characters which
+// are not printable
+func blockBuilder(extra []byte) string {
+	return strings.TrimSpace(strings.Map(func(r rune) rune {
+		if r == unicode.ReplacementChar || !unicode.IsPrint(r) {
+			return -1
+		}
+		return r
+	}, string(extra)))
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTrackedBundle(t *testing.T, hash string, blockNumber uint64, txs ...*ethtypes.Transaction) *bxmessage.MEVBundle {
+	rawTxs := make([]string, 0, len(txs))
+	for _, tx := range txs {
+		rawTx, err := tx.MarshalBinary()
+		require.NoError(t, err)
+		rawTxs = append(rawTxs, hexutil.Encode(rawTx))
+	}
+	return &bxmessage.MEVBundle{BundleHash: hash, BlockNumber: hexutil.EncodeUint64(blockNumber), Transactions: rawTxs}
+}
+
+func newBuilderBlock(number uint64, builder string, txs ...*ethtypes.Transaction) *ethtypes.Block {
+	header := &ethtypes.Header{
+		Number:   new(big.Int).SetUint64(number),
+		Extra:    []byte(builder),
+		Coinbase: common.HexToAddress("0x95222290dd7278aa3ddd389cc1e1d165cc4bafe5"),
+	}
+	return ethtypes.NewBlockWithHeader(header).WithBody(txs, nil)
+}
+
+func TestBundleTracker(t *testing.T) {
+	clock := &utils.MockClock{}
+	clock.SetTime(time.Now())
+	var notifications []*types.BundleStatusNotification
+	tracker := NewBundleTracker(clock, func(notification types.Notification) {
+		notifications = append(notifications, notification.(*types.BundleStatusNotification))
+	})
+	latest := func(hash string) *types.BundleStatusNotification {
+		for i := len(notifications) - 1; i >= 0; i-- {
+			if notifications[i].BundleHash == hash {
+				return notifications[i]
+			}
+		}
+		return nil
+	}
+
+	tx1 := newGasOracleTx(t, 1, 21000, 100, 10)
+	tx2 := newGasOracleTx(t, 2, 21000, 100, 10)
+	tx3 := newGasOracleTx(t, 3, 21000, 100, 10)
+	tx4 := newGasOracleTx(t, 4, 21000, 100, 10)
+
+	require.NoError(t, tracker.Track(newTrackedBundle(t, "0xAA", 11, tx1, tx2), "account"))
+	require.NoError(t, tracker.Track(newTrackedBundle(t, "0xbb", 11, tx3, tx4), "account"))
+	require.NoError(t, tracker.Track(newTrackedBundle(t, "0xcc", 12, newGasOracleTx(t, 5, 21000, 100, 10)), "account"))
+	assert.Error(t, tracker.Track(&bxmessage.MEVBundle{BundleHash: "0xdd", BlockNumber: "0xc", Transactions: []string{"0x01"}}, "account"))
+	require.Len(t, notifications, 3)
+	assert.Equal(t, types.BundlePending, notifications[0].Status)
+	assert.Equal(t, uint64(11), notifications[0].TargetBlockNumber)
+	assert.Equal(t, []types.AccountID{"account"}, notifications[0].AccountIDs)
+
+	// a bundle already tracked is not tracked again, its status is reported to another account which submits it
+	require.NoError(t, tracker.Track(newTrackedBundle(t, "0xaa", 11, tx1, tx2), "account"))
+	assert.Len(t, notifications, 3)
+	require.NoError(t, tracker.Track(newTrackedBundle(t, "0xaa", 11, tx1, tx2), "other"))
+	require.Len(t, notifications, 4)
+	assert.Equal(t, []types.AccountID{"other"}, notifications[3].AccountIDs)
+
+	// the status is available to the accounts which submitted the bundle only
+	_, ok := tracker.Status("0xbb", "other")
+	assert.False(t, ok)
+
+	// a transaction can be part of several bundles
+	require.NoError(t, tracker.Track(newTrackedBundle(t, "0xee", 11, tx1), "other"))
+	require.Len(t, notifications, 5)
+
+	// an earlier block includes the whole bundles, the other bundle has a single transaction included
+	tracker.OnBlock(newBuilderBlock(10, "beaverbuild.org", tx1, tx2, tx3))
+	require.Len(t, notifications, 7)
+	assert.Equal(t, types.BundleIncluded, latest("0xee").Status)
+	included := latest("0xaa")
+	assert.Equal(t, types.BundleIncluded, included.Status)
+	assert.Equal(t, uint64(10), included.BlockNumber)
+	assert.Equal(t, "beaverbuild.org", included.Builder)
+	assert.Equal(t, "0x95222290DD7278Aa3Ddd389Cc1E1d165CC4BAfe5", included.FeeRecipient)
+	assert.Equal(t, []string{tx1.Hash().Hex(), tx2.Hash().Hex()}, included.IncludedTxs)
+	assert.Equal(t, []types.AccountID{"account", "other"}, included.AccountIDs)
+
+	status, ok := tracker.Status("0xBB", "account")
+	require.True(t, ok)
+	assert.Equal(t, types.BundlePending, status.Status)
+	assert.Equal(t, []common.Hash{tx3.Hash()}, status.IncludedTxs)
+
+	// the target block settles the pending bundles
+	tracker.OnBlock(newBuilderBlock(11, "Titan (titanbuilder.xyz)\x00"))
+	require.Len(t, notifications, 8)
+	assert.Equal(t, "0xbb", notifications[7].BundleHash)
+	assert.Equal(t, types.BundlePartiallyIncluded, notifications[7].Status)
+	assert.Equal(t, "beaverbuild.org", notifications[7].Builder)
+
+	tracker.OnBlock(newBuilderBlock(12, "Titan (titanbuilder.xyz)\x00"))
+	require.Len(t, notifications, 9)
+	assert.Equal(t, "0xcc", notifications[8].BundleHash)
+	assert.Equal(t, types.BundleExpired, notifications[8].Status)
+	assert.Empty(t, notifications[8].BlockHash)
+
+	// a settled bundle is not updated, and is removed after the retention
+	tracker.OnBlock(newBuilderBlock(13, "", tx4))
+	assert.Len(t, notifications, 9)
+	status, ok = tracker.Status("0xbb", "account")
+	require.True(t, ok)
+	assert.Equal(t, types.BundlePartiallyIncluded, status.Status)
+
+	clock.IncTime(settledBundleRetention + time.Second)
+	tracker.OnBlock(newBuilderBlock(14, ""))
+	_, ok = tracker.Status("0xaa", "account")
+	assert.False(t, ok)
+	assert.Equal(t, 0, tracker.Size())
+}
+
+func TestBlockBuilder(t *testing.T) {
+	assert.Equal(t, "Titan (titanbuilder.xyz)", blockBuilder([]byte("Titan (titanbuilder.xyz)\x00\x01")))
+	assert.Equal(t, "", blockBuilder([]byte{0xd8, 0x83, 0x01, 0x0b}))
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/bloXroute-Labs/gateway/v2"
 	log "github.com/bloXroute-Labs/gateway/v2/logger"
 	pbbase "github.com/bloXroute-Labs/gateway/v2/protobuf"
+	"github.com/bloXroute-Labs/gateway/v2/services/metrics"
 	"github.com/bloXroute-Labs/gateway/v2/types"
 	"github.com/bloXroute-Labs/gateway/v2/utils"
 	"github.com/bloXroute-Labs/gateway/v2/utils/syncmap"
@@ -32,6 +33,9 @@ type BxTxStore struct {
 	assigner               ShortIDAssigner
 	cleanedShortIDsChannel chan types.ShortIDsByNetwork
 	bloom                  BloomFilter
+
+	snapshotPath     string
+	snapshotInterval time.Duration
 }
 
 // NewBxTxStore creates a new BxTxStore to store and processes all relevant transactions
@@ -60,13 +64,19 @@ func newBxTxStore(clock utils.Clock, cleanupFreq time.Duration, maxTxAge time.Du
 	}
 }
 
-// Start initializes all relevant goroutines for the BxTxStore
+// Start initializes all relevant goroutines for the BxTxStore, reloading the stored transactions if persistence is enabled
 func (t *BxTxStore) Start() error {
+	metrics.TxStoreSize.SetSource(func() float64 { return float64(t.Count()) })
+	if t.snapshotPath != "" {
+		if err := t.loadFromDisk(); err != nil {
+			log.Errorf("TxStore failed to load transactions from %v: %v", t.snapshotPath, err)
+		}
+	}
 	t.cleanup()
 	return nil
 }
 
-// Stop closes all running go routines for BxTxStore
+// Stop closes all running go routines for BxTxStore, storing the transactions if persistence is enabled
 func (t *BxTxStore) Stop() {
 	t.quit <- true
 	<-t.quit
@@ -311,6 +321,8 @@ func (t *BxTxStore) clean() (cleaned int, cleanedShortIDs types.ShortIDsByNetwor
 		log.Debugf("TxStore network %v #txs before cleanup %v cleaned %v missing SID entries and %v aged entries",
 			net, len(netData.ages), netData.cleanNoSID, netData.cleanAge)
 		cleaned += netData.cleanNoSID + netData.cleanAge
+		metrics.TxStoreCleaned.WithLabelValues("no_short_id").Add(float64(netData.cleanNoSID))
+		metrics.TxStoreCleaned.WithLabelValues("age").Add(float64(netData.cleanAge))
 	}
 
 	return cleaned, cleanedShortIDs
@@ -321,6 +333,7 @@ func (t *BxTxStore) CleanNow() {
 	mapSizeBeforeClean := t.Count()
 	timeStart := t.clock.Now()
 	cleaned, cleanedShortIDs := t.clean()
+	metrics.TxStoreCleanups.Inc()
 	log.Debugf("TxStore cleaned %v entries in %v. size before clean: %v size after clean: %v",
 		cleaned, t.clock.Now().Sub(timeStart), mapSizeBeforeClean, t.Count())
 	if t.cleanedShortIDsChannel != nil && len(cleanedShortIDs) > 0 {
@@ -330,11 +343,26 @@ func (t *BxTxStore) CleanNow() {
 
 func (t *BxTxStore) cleanup() {
 	ticker := t.clock.Ticker(t.cleanupFreq)
+	var storeAlert <-chan time.Time
+	if t.snapshotPath != "" {
+		storeTicker := t.clock.Ticker(t.snapshotInterval)
+		defer storeTicker.Stop()
+		storeAlert = storeTicker.Alert()
+	}
 	for {
 		select {
 		case <-ticker.Alert():
 			t.CleanNow()
+		case <-storeAlert:
+			if err := t.storeOnDisk(); err != nil {
+				log.Errorf("TxStore failed to store transactions: %v", err)
+			}
 		case <-t.quit:
+			if t.snapshotPath != "" {
+				if err := t.storeOnDisk(); err != nil {
+					log.Errorf("TxStore failed to store transactions: %v", err)
+				}
+			}
 			t.quit <- true
 			ticker.Stop()
 			return
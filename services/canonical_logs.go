@@ -0,0 +1,115 @@
+package services
+
+import (
+	"sync"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+// canonicalLogsDepth is the number of recent blocks whose receipts are kept, to publish their logs when they become
+// canonical or as removed when a reorganization drops them
+const canonicalLogsDepth = 64
+
+type canonicalBlockLogs struct {
+	number    uint64
+	receipts  []*types.TxReceipt
+	canonical bool
+	published bool
+}
+
+// CanonicalLogs publishes the receipts of the blocks of the canonical chain for the logs subscriptions. The receipts
+// of a block are published once the block is canonical and its receipts are fetched, and published again as removed when a reorganization drops the block from the canonical chain
+type CanonicalLogs struct {
+	lock    sync.Mutex
+	blocks  map[string]*canonicalBlockLogs
+	highest uint64
+	notify  func(notification types.Notification)
+}
+
+// NewCanonicalLogs creates a CanonicalLogs publishing the receipts with notify
+func NewCanonicalLogs(notify func(notification types.Notification)) *CanonicalLogs {
+	return &CanonicalLogs{
+		blocks: make(map[string]*canonicalBlockLogs),
+		notify: notify,
+	}
+}
+
+// OnReceipts records the receipts of a block, and publishes them if the block is canonical
+func (c *CanonicalLogs) OnReceipts(blockHash string, blockNumber uint64, receipts []*types.TxReceipt) {
+	var notifications []types.Notification
+
+	if receipts == nil {
+		receipts = []*types.TxReceipt{}
+	}
+
+	c.lock.Lock()
+	if blockNumber+canonicalLogsDepth <= c.highest {
+		c.lock.Unlock()
+		return
+	}
+	block := c.block(blockHash, blockNumber)
+	block.receipts = receipts
+	if block.canonical && !block.published {
+		block.published = true
+		notifications = append(notifications, types.NewEthLogsNotification(blockHash, blockNumber, false, receipts))
+	}
+	c.lock.Unlock()
+
+	c.publish(notifications)
+}
+
+// OnChainUpdate publishes the receipts of the blocks dropped from the canonical chain as removed, the most recent
+// block first, and then the receipts of the new canonical blocks which are already fetched
+func (c *CanonicalLogs) OnChainUpdate(dropped, added []types.ReorgBlock) {
+	var notifications []types.Notification
+
+	c.lock.Lock()
+	for i := len(dropped) - 1; i >= 0; i-- {
+		block, ok := c.blocks[dropped[i].Hash]
+		if !ok {
+			continue
+		}
+		if block.published {
+			notifications = append(notifications, types.NewEthLogsNotification(dropped[i].Hash, block.number, true, block.receipts))
+		}
+		block.canonical = false
+		block.published = false
+	}
+	for _, ref := range added {
+		block := c.block(ref.Hash, ref.Height)
+		block.canonical = true
+		if block.receipts != nil && !block.published {
+			block.published = true
+			notifications = append(notifications, types.NewEthLogsNotification(ref.Hash, ref.Height, false, block.receipts))
+		}
+	}
+	c.lock.Unlock()
+
+	c.publish(notifications)
+}
+
+// block returns the entry of the block, created if needed, and forgets the blocks too old to be reorganized
+// should be called with c.lock held
+func (c *CanonicalLogs) block(blockHash string, blockNumber uint64) *canonicalBlockLogs {
+	if blockNumber > c.highest {
+		c.highest = blockNumber
+		for hash, block := range c.blocks {
+			if block.number+canonicalLogsDepth <= c.highest {
+				delete(c.blocks, hash)
+			}
+		}
+	}
+
+	block, ok := c.blocks[blockHash]
+	if !ok {
+		block = &canonicalBlockLogs{number: blockNumber}
+		c.blocks[blockHash] = block
+	}
+	return block
+}
+
+func (c *CanonicalLogs) publish(notifications []types.Notification) {
+	for _, notification := range notifications {
+		c.notify(notification)
+	}
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalLogs(t *testing.T) {
+	var notifications []*types.EthLogsNotification
+	logs := NewCanonicalLogs(func(notification types.Notification) {
+		notifications = append(notifications, notification.(*types.EthLogsNotification))
+	})
+	receipts10 := []*types.TxReceipt{{TransactionHash: "0x1"}}
+	receipts11 := []*types.TxReceipt{{TransactionHash: "0x2"}}
+	receipts11b := []*types.TxReceipt{{TransactionHash: "0x3"}}
+
+	// the receipts are published once the block is canonical
+	logs.OnReceipts("0x10a", 10, receipts10)
+	assert.Empty(t, notifications)
+	logs.OnChainUpdate(nil, []types.ReorgBlock{{Hash: "0x10a", Height: 10}})
+	require.Len(t, notifications, 1)
+	assert.Equal(t, "0x10a", notifications[0].BlockHash)
+	assert.False(t, notifications[0].Removed)
+
+	// or as soon as they are fetched if the block is already canonical
+	logs.OnChainUpdate(nil, []types.ReorgBlock{{Hash: "0x11a", Height: 11}})
+	require.Len(t, notifications, 1)
+	logs.OnReceipts("0x11a", 11, receipts11)
+	require.Len(t, notifications, 2)
+	assert.Equal(t, receipts11, notifications[1].Receipts)
+
+	// a sibling that is not canonical is never published
+	logs.OnReceipts("0x11b", 11, receipts11b)
+	require.Len(t, notifications, 2)
+
+	// a reorg removes the dropped blocks, the most recent first, before publishing the new canonical blocks
+	logs.OnReceipts("0x10b", 10, nil)
+	logs.OnChainUpdate(
+		[]types.ReorgBlock{{Hash: "0x10a", Height: 10}, {Hash: "0x11a", Height: 11}},
+		[]types.ReorgBlock{{Hash: "0x10b", Height: 10}, {Hash: "0x11b", Height: 11}},
+	)
+	require.Len(t, notifications, 6)
+	assert.Equal(t, "0x11a", notifications[2].BlockHash)
+	assert.True(t, notifications[2].Removed)
+	assert.Equal(t, receipts11, notifications[2].Receipts)
+	assert.Equal(t, "0x10a", notifications[3].BlockHash)
+	assert.True(t, notifications[3].Removed)
+	assert.Equal(t, "0x10b", notifications[4].BlockHash)
+	assert.Empty(t, notifications[4].Receipts)
+	assert.False(t, notifications[4].Removed)
+	assert.Equal(t, "0x11b", notifications[5].BlockHash)
+
+	// a block is published once
+	logs.OnReceipts("0x11b", 11, receipts11b)
+	require.Len(t, notifications, 6)
+
+	// blocks too old to be reorganized are forgotten
+	logs.OnChainUpdate(nil, []types.ReorgBlock{{Hash: "0xfff", Height: 11 + canonicalLogsDepth}})
+	_, ok := logs.blocks["0x10b"]
+	assert.False(t, ok)
+	logs.OnReceipts("0x10c", 10, receipts10)
+	_, ok = logs.blocks["0x10c"]
+	assert.False(t, ok)
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// maxConditionalTxs bounds the transactions held by the gateway for an account, the condition of each of them is
+	// evaluated on every block
+	maxConditionalTxs = 100
+	// conditionalTxDefaultExpiryBlocks is how many blocks a transaction is held if no expiry block is requested
+	conditionalTxDefaultExpiryBlocks = 7200
+)
+
+var (
+	// ErrConditionalTxExists is returned when the transaction is already held
+	ErrConditionalTxExists = errors.New("conditional transaction already exists")
+	// ErrConditionalTxNotFound is returned when the transaction is not held, or is held for another account
+	ErrConditionalTxNotFound = errors.New("conditional transaction not found")
+)
+
+// ConditionalTx is a signed transaction held until its condition matches a block
+type ConditionalTx struct {
+	Hash        types.SHA256Hash
+	Transaction string
+	AccountID   types.AccountID
+	// Condition is the canonical form of the condition, as reported to the clients
+	Condition string
+	// ExpiryBlock is the last block the condition is evaluated on, 0 until known if the gateway did not see a block yet
+	ExpiryBlock uint64
+	// Match evaluates the condition against a block
+	Match func(block *ethtypes.Block) bool
+	// Release sends the transaction through the transaction processing of the gateway
+	Release func(tx *ConditionalTx) error
+
+	addedTime  time.Time
+	addedBlock uint64
+}
+
+// ConditionalTxInfo describes a held transaction
+type ConditionalTxInfo struct {
+	Hash        types.SHA256Hash
+	AccountID   types.AccountID
+	Condition   string
+	ExpiryBlock uint64
+	AddedTime   time.Time
+}
+
+// ConditionalTxStore holds transactions and releases them once their condition matches a block of the canonical chain,
+// or drops them at expiry
+type ConditionalTxStore struct {
+	clock       utils.Clock
+	lock        sync.Mutex
+	txs         map[types.SHA256Hash]*ConditionalTx
+	accountTxs  map[types.AccountID]int
+	blockNumber uint64
+	notify      func(notification types.Notification)
+}
+
+// NewConditionalTxStore creates a ConditionalTxStore reporting the status of the transactions with notify
+func NewConditionalTxStore(clock utils.Clock, notify func(notification types.Notification)) *ConditionalTxStore {
+	return &ConditionalTxStore{
+		clock:      clock,
+		txs:        make(map[types.SHA256Hash]*ConditionalTx),
+		accountTxs: make(map[types.AccountID]int),
+		notify:     notify,
+	}
+}
+
+// Add holds the transaction until its condition matches a block following the current one
+func (s *ConditionalTxStore) Add(tx *ConditionalTx) error {
+	if tx.Match == nil || tx.Release == nil {
+		return errors.New("conditional transaction requires a condition and a release")
+	}
+
+	s.lock.Lock()
+	if _, ok := s.txs[tx.Hash]; ok {
+		s.lock.Unlock()
+		return ErrConditionalTxExists
+	}
+	if s.accountTxs[tx.AccountID] >= maxConditionalTxs {
+		s.lock.Unlock()
+		return fmt.Errorf("gateway already holds %v conditional transactions for the account", maxConditionalTxs)
+	}
+	if s.blockNumber != 0 {
+		if tx.ExpiryBlock == 0 {
+			tx.ExpiryBlock = s.blockNumber + conditionalTxDefaultExpiryBlocks
+		} else if tx.ExpiryBlock <= s.blockNumber {
+			s.lock.Unlock()
+			return fmt.Errorf("expiry block %v is not after the current block %v", tx.ExpiryBlock, s.blockNumber)
+		}
+	}
+	tx.addedTime = s.clock.Now()
+	tx.addedBlock = s.blockNumber
+	s.txs[tx.Hash] = tx
+	s.accountTxs[tx.AccountID]++
+	s.lock.Unlock()
+
+	s.notifyStatus(tx, types.Held)
+	return nil
+}
+
+// OnBlock releases the transactions whose condition matches the canonical block, and drops the ones which expired
+func (s *ConditionalTxStore) OnBlock(block *ethtypes.Block) {
+	number := block.NumberU64()
+
+	s.lock.Lock()
+	if number > s.blockNumber {
+		s.blockNumber = number
+	}
+
+	var due, expired []*ConditionalTx
+	for _, tx := range s.txs {
+		if tx.ExpiryBlock == 0 {
+			// held before the gateway saw a block, the expiry starts now
+			tx.ExpiryBlock = number + conditionalTxDefaultExpiryBlocks
+		}
+		if number > tx.ExpiryBlock {
+			expired = append(expired, tx)
+			s.removeLocked(tx)
+			continue
+		}
+		if number > tx.addedBlock && tx.Match(block) {
+			due = append(due, tx)
+			s.removeLocked(tx)
+		}
+	}
+	s.lock.Unlock()
+
+	for _, tx := range expired {
+		log.Infof("conditional transaction %v expired at block %v, condition %v", tx.Hash, tx.ExpiryBlock, tx.Condition)
+		s.notifyStatus(tx, types.Expired)
+	}
+	if len(due) > 0 {
+		// the transactions go through the transaction processing, which should not hold the block processing
+		go s.release(due, block.Hash())
+	}
+}
+
+func (s *ConditionalTxStore) release(due []*ConditionalTx, blockHash common.Hash) {
+	for _, tx := range due {
+		if err := tx.Release(tx); err != nil {
+			log.Errorf("failed to release conditional transaction %v: %v", tx.Hash, err)
+			s.notifyStatus(tx, types.Failed)
+			continue
+		}
+		log.Infof("conditional transaction %v released, condition %v matched block %v", tx.Hash, tx.Condition, blockHash)
+		s.notifyStatus(tx, types.TxPool)
+	}
+}
+
+// Cancel drops a transaction held for the account
+func (s *ConditionalTxStore) Cancel(hash types.SHA256Hash, accountID types.AccountID) error {
+	s.lock.Lock()
+	tx, ok := s.txs[hash]
+	if !ok || tx.AccountID != accountID {
+		s.lock.Unlock()
+		return ErrConditionalTxNotFound
+	}
+	s.removeLocked(tx)
+	s.lock.Unlock()
+
+	s.notifyStatus(tx, types.Canceled)
+	return nil
+}
+
+// Get returns a transaction held for the account
+func (s *ConditionalTxStore) Get(hash types.SHA256Hash, accountID types.AccountID) (ConditionalTxInfo, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	tx, ok := s.txs[hash]
+	if !ok || tx.AccountID != accountID {
+		return ConditionalTxInfo{}, false
+	}
+	return tx.info(), true
+}
+
+// List returns the transactions held for the account, oldest first
+func (s *ConditionalTxStore) List(accountID types.AccountID) []ConditionalTxInfo {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	txs := make([]ConditionalTxInfo, 0)
+	for _, tx := range s.txs {
+		if tx.AccountID == accountID {
+			txs = append(txs, tx.info())
+		}
+	}
+	sort.Slice(txs, func(i, j int) bool { return txs[i].AddedTime.Before(txs[j].AddedTime) })
+	return txs
+}
+
+// Size returns the number of held transactions
+func (s *ConditionalTxStore) Size() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.txs)
+}
+
+func (s *ConditionalTxStore) removeLocked(tx *ConditionalTx) {
+	delete(s.txs, tx.Hash)
+	if s.accountTxs[tx.AccountID] <= 1 {
+		delete(s.accountTxs, tx.AccountID)
+	} else {
+		s.accountTxs[tx.AccountID]--
+	}
+}
+
+// notifyStatus reports the status of the transaction to the account holding it only
+func (s *ConditionalTxStore) notifyStatus(tx *ConditionalTx, status types.Status) {
+	s.notify(&types.TransactionStatusNotification{
+		TransactionHash:      tx.Hash.Format(true),
+		Status:               status,
+		NotificationAccounts: types.NotificationAccounts{AccountIDs: []types.AccountID{tx.AccountID}},
+	})
+}
+
+func (tx *ConditionalTx) info() ConditionalTxInfo {
+	return ConditionalTxInfo{
+		Hash:        tx.Hash,
+		AccountID:   tx.AccountID,
+		Condition:   tx.Condition,
+		ExpiryBlock: tx.ExpiryBlock,
+		AddedTime:   tx.addedTime,
+	}
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConditionalBlock(number int64) *ethtypes.Block {
+	return ethtypes.NewBlockWithHeader(&ethtypes.Header{Number: big.NewInt(number)})
+}
+
+func newTestConditionalTx(hash types.SHA256Hash, minBlock uint64, released chan types.SHA256Hash) *ConditionalTx {
+	return &ConditionalTx{
+		Hash:      hash,
+		AccountID: "account",
+		Condition: "({block_number} >= minBlock)",
+		Match: func(block *ethtypes.Block) bool {
+			return block.NumberU64() >= minBlock
+		},
+		Release: func(tx *ConditionalTx) error {
+			released <- tx.Hash
+			return nil
+		},
+	}
+}
+
+func newTestConditionalTxStore() (*ConditionalTxStore, *testTxReplacementNotifications) {
+	clock := utils.MockClock{}
+	clock.SetTime(time.Now())
+	notifications := &testTxReplacementNotifications{statuses: make(map[string]types.Status)}
+	return NewConditionalTxStore(&clock, notifications.notify), notifications
+}
+
+func TestConditionalTxStore_Release(t *testing.T) {
+	store, notifications := newTestConditionalTxStore()
+	released := make(chan types.SHA256Hash, 1)
+	store.OnBlock(testConditionalBlock(100))
+
+	require.NoError(t, store.Add(newTestConditionalTx(types.SHA256Hash{1}, 100, released)))
+	assert.ErrorIs(t, store.Add(newTestConditionalTx(types.SHA256Hash{1}, 100, released)), ErrConditionalTxExists)
+	assert.Equal(t, types.Held, notifications.status(types.SHA256Hash{1}))
+
+	info, ok := store.Get(types.SHA256Hash{1}, "account")
+	require.True(t, ok)
+	assert.Equal(t, uint64(100+conditionalTxDefaultExpiryBlocks), info.ExpiryBlock)
+	_, ok = store.Get(types.SHA256Hash{1}, "other")
+	assert.False(t, ok)
+
+	// the condition already matches the current block, only the following blocks are evaluated
+	store.OnBlock(testConditionalBlock(100))
+	assert.Equal(t, 1, store.Size())
+
+	store.OnBlock(testConditionalBlock(101))
+	select {
+	case hash := <-released:
+		assert.Equal(t, types.SHA256Hash{1}, hash)
+	case <-time.After(time.Second):
+		require.Fail(t, "conditional transaction was not released")
+	}
+	assert.Equal(t, 0, store.Size())
+	assert.Eventually(t, func() bool { return notifications.status(types.SHA256Hash{1}) == types.TxPool }, time.Second, 10*time.Millisecond)
+}
+
+func TestConditionalTxStore_Expiry(t *testing.T) {
+	store, notifications := newTestConditionalTxStore()
+	released := make(chan types.SHA256Hash, 1)
+
+	// held before any block, the default expiry starts with the first block
+	require.NoError(t, store.Add(newTestConditionalTx(types.SHA256Hash{1}, 1000, released)))
+	tx := newTestConditionalTx(types.SHA256Hash{2}, 1000, released)
+	tx.ExpiryBlock = 102
+	require.NoError(t, store.Add(tx))
+
+	store.OnBlock(testConditionalBlock(100))
+	info, ok := store.Get(types.SHA256Hash{1}, "account")
+	require.True(t, ok)
+	assert.Equal(t, uint64(100+conditionalTxDefaultExpiryBlocks), info.ExpiryBlock)
+
+	store.OnBlock(testConditionalBlock(102))
+	assert.Equal(t, 2, store.Size())
+	store.OnBlock(testConditionalBlock(103))
+	assert.Equal(t, 1, store.Size())
+	assert.Equal(t, types.Expired, notifications.status(types.SHA256Hash{2}))
+	assert.Empty(t, released)
+
+	expired := newTestConditionalTx(types.SHA256Hash{3}, 1000, released)
+	expired.ExpiryBlock = 103
+	assert.Error(t, store.Add(expired))
+}
+
+func TestConditionalTxStore_Cancel(t *testing.T) {
+	store, notifications := newTestConditionalTxStore()
+	released := make(chan types.SHA256Hash, 1)
+	store.OnBlock(testConditionalBlock(100))
+
+	require.NoError(t, store.Add(newTestConditionalTx(types.SHA256Hash{1}, 101, released)))
+	require.NoError(t, store.Add(newTestConditionalTx(types.SHA256Hash{2}, 101, released)))
+	assert.Len(t, store.List("account"), 2)
+	assert.Empty(t, store.List("other"))
+
+	assert.ErrorIs(t, store.Cancel(types.SHA256Hash{1}, "other"), ErrConditionalTxNotFound)
+	require.NoError(t, store.Cancel(types.SHA256Hash{1}, "account"))
+	assert.ErrorIs(t, store.Cancel(types.SHA256Hash{1}, "account"), ErrConditionalTxNotFound)
+	assert.Equal(t, types.Canceled, notifications.status(types.SHA256Hash{1}))
+
+	txs := store.List("account")
+	require.Len(t, txs, 1)
+	assert.Equal(t, types.SHA256Hash{2}, txs[0].Hash)
+}
+
+func TestConditionalTxStore_Limit(t *testing.T) {
+	store, notifications := newTestConditionalTxStore()
+	released := make(chan types.SHA256Hash, 1)
+	store.OnBlock(testConditionalBlock(100))
+
+	for i := 0; i < maxConditionalTxs; i++ {
+		require.NoError(t, store.Add(newTestConditionalTx(types.SHA256Hash{byte(i), 1}, 1000, released)))
+	}
+	assert.Error(t, store.Add(newTestConditionalTx(types.SHA256Hash{1, 2}, 1000, released)))
+
+	// the limit is per account
+	other := newTestConditionalTx(types.SHA256Hash{1, 3}, 101, released)
+	other.AccountID = "other"
+	other.Release = func(*ConditionalTx) error { return errors.New("failed to send") }
+	require.NoError(t, store.Add(other))
+
+	require.NoError(t, store.Cancel(types.SHA256Hash{0, 1}, "account"))
+	require.NoError(t, store.Add(newTestConditionalTx(types.SHA256Hash{1, 2}, 1000, released)))
+
+	// a transaction which fails to be released is reported as failed
+	store.OnBlock(testConditionalBlock(101))
+	assert.Eventually(t, func() bool { return notifications.status(types.SHA256Hash{1, 3}) == types.Failed }, time.Second, 10*time.Millisecond)
+}
@@ -116,6 +116,7 @@ func (t *EthTxStore) add(hash types.SHA256Hash, content types.TxContent, shortID
 
 	// if reuseNonce is disabled or network disables sender extraction, we can leave
 	if !t.isReuseNonceActive(network) || sender == types.EmptySender {
+		t.trackPendingNonce(result.Transaction, ethTx, sender)
 		return result
 	}
 
@@ -134,9 +135,26 @@ func (t *EthTxStore) add(hash types.SHA256Hash, content types.TxContent, shortID
 		return result
 	}
 
+	t.trackPendingNonce(result.Transaction, ethTx, sender)
 	return result
 }
 
+// trackPendingNonce adds the transaction to the pending nonces of its sender, if the sender is known. The transactions
+// submitted to the gateway are tracked even if the network does not require the sender
+func (t *EthTxStore) trackPendingNonce(transaction *types.BxTransaction, ethTx *types.EthTransaction, sender types.Sender) {
+	if sender == types.EmptySender && transaction.Flags().IsPaidTx() {
+		sender, _ = ethTx.Sender()
+	}
+	if sender != types.EmptySender {
+		t.pending.Track(common.Address(sender), ethTx.Nonce(), transaction.Hash())
+	}
+}
+
+// PendingNonceTracker returns the tracker of the pending nonces of the senders
+func (t *EthTxStore) PendingNonceTracker() *PendingNonceTracker {
+	return t.pending
+}
+
 // Stop halts the nonce tracker in addition to regular tx service cleanup
 func (t *EthTxStore) Stop() {
 	t.BxTxStore.Stop()
@@ -159,6 +177,7 @@ type nonceTracker struct {
 	addressNonceToTx *syncmap.SyncMap[string, trackedTx]
 	cleanInterval    time.Duration
 	networkConfig    sdnmessage.BlockchainNetworks
+	pending          *PendingNonceTracker
 	quit             chan bool
 }
 
@@ -176,6 +195,7 @@ func newNonceTracker(clock utils.Clock, networkConfig sdnmessage.BlockchainNetwo
 		networkConfig:    networkConfig,
 		addressNonceToTx: syncmap.NewStringMapOf[trackedTx](),
 		cleanInterval:    cleanInterval,
+		pending:          NewPendingNonceTracker(clock),
 		quit:             make(chan bool),
 	}
 	go nt.cleanLoop()
@@ -246,8 +266,10 @@ func (nt *nonceTracker) cleanLoop() {
 		select {
 		case <-ticker.Alert():
 			nt.clean()
+			nt.pending.clean()
 		case <-nt.quit:
 			ticker.Stop()
+			nt.quit <- true
 			return
 		}
 	}
@@ -0,0 +1,316 @@
+package services
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	// gasOracleBlocks is the number of recent blocks the estimates are computed from
+	gasOracleBlocks = 20
+	// gasOracleMempoolTTL is how long the tips of the pending transactions are reused, reading them iterates the whole tx store
+	gasOracleMempoolTTL = time.Second
+	// blockInclusionPercentile selects the tip a block required to include a transaction. The lowest tips of a block are
+	// left out, they are usually paid by the transactions of the block producer or by private order flow
+	blockInclusionPercentile = 0.1
+	// baseFeeChangeDenominator and elasticityMultiplier are the EIP-1559 parameters of the base fee
+	baseFeeChangeDenominator = 8
+	elasticityMultiplier     = 2
+)
+
+// ErrGasOracleNoBlocks is returned by the gas oracle until it sees a block with a base fee
+var ErrGasOracleNoBlocks = errors.New("no block with a base fee was seen yet, the gas estimate is not available")
+
+type gasEstimateTier struct {
+	probability float64
+	// baseFeeBlocks is the number of blocks of maximum base fee increase the max fee per gas covers, so the transaction
+	// stays includable if it is not included right away
+	baseFeeBlocks int
+}
+
+var gasEstimateTiers = []gasEstimateTier{
+	{probability: 0.5, baseFeeBlocks: 1},
+	{probability: 0.7, baseFeeBlocks: 2},
+	{probability: 0.9, baseFeeBlocks: 3},
+	{probability: 0.99, baseFeeBlocks: 6},
+}
+
+// gasTipBucketsGwei are the lower bounds of the buckets of the mempool tip histogram
+var gasTipBucketsGwei = []float64{0, 0.1, 0.5, 1, 1.5, 2, 3, 5, 10, 20, 50, 100}
+
+type gasOracleBlock struct {
+	number   uint64
+	hash     common.Hash
+	baseFee  *big.Int
+	gasUsed  uint64
+	gasLimit uint64
+	// inclusionTip is nil if the block has no transactions
+	inclusionTip *big.Int
+}
+
+type pendingTip struct {
+	tip *big.Int
+	gas uint64
+}
+
+// GasOracle recommends the fees of a transaction from the base fees and tips of the recent blocks, and the tips of the
+// pending transactions of the tx store
+type GasOracle struct {
+	clock         utils.Clock
+	txStore       TxStore
+	pendingNonces *PendingNonceTracker
+
+	lock   sync.Mutex
+	blocks []gasOracleBlock
+
+	// mempoolLock guards the tips of the pending transactions, which are read without holding the recorded blocks
+	mempoolLock sync.Mutex
+	mempool     []pendingTip
+	mempoolHead common.Hash
+	mempoolTime time.Time
+}
+
+// NewGasOracle creates a GasOracle reading the pending transactions of txStore. The transactions whose nonce is
+// confirmed by the pending nonces are left out, the pending nonces can be nil
+func NewGasOracle(clock utils.Clock, txStore TxStore, pendingNonces *PendingNonceTracker) *GasOracle {
+	return &GasOracle{
+		clock:         clock,
+		txStore:       txStore,
+		pendingNonces: pendingNonces,
+	}
+}
+
+// OnBlock records the base fee and the tips of the block. A block replaces the recorded blocks at the same or higher
+// height, which were dropped by a reorganization
+func (o *GasOracle) OnBlock(block *ethtypes.Block) {
+	baseFee := block.BaseFee()
+	if baseFee == nil {
+		return
+	}
+
+	tips := make([]*big.Int, 0, len(block.Transactions()))
+	for _, tx := range block.Transactions() {
+		tip, err := tx.EffectiveGasTip(baseFee)
+		if err != nil {
+			continue
+		}
+		tips = append(tips, tip)
+	}
+	oracleBlock := gasOracleBlock{
+		number:   block.NumberU64(),
+		hash:     block.Hash(),
+		baseFee:  baseFee,
+		gasUsed:  block.GasUsed(),
+		gasLimit: block.GasLimit(),
+	}
+	if len(tips) > 0 {
+		sort.Slice(tips, func(i, j int) bool { return tips[i].Cmp(tips[j]) < 0 })
+		oracleBlock.inclusionTip = tips[int(float64(len(tips)-1)*blockInclusionPercentile)]
+	}
+
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	i := len(o.blocks)
+	for i > 0 && o.blocks[i-1].number >= oracleBlock.number {
+		i--
+	}
+	o.blocks = append(o.blocks[:i], oracleBlock)
+	if len(o.blocks) > gasOracleBlocks {
+		o.blocks = o.blocks[len(o.blocks)-gasOracleBlocks:]
+	}
+}
+
+// Estimate returns the fees recommended for each inclusion probability tier, and the histogram of the tips of the
+// pending transactions. The max priority fee per gas of a tier is the tip the share of the recent blocks given by
+// the probability would have included, raised to the tip of the pending transactions which would fill the next blocks
+// before the transaction
+func (o *GasOracle) Estimate() (*types.GasEstimateNotification, error) {
+	o.lock.Lock()
+	blocks := append([]gasOracleBlock(nil), o.blocks...)
+	o.lock.Unlock()
+
+	if len(blocks) == 0 {
+		return nil, ErrGasOracleNoBlocks
+	}
+	head := blocks[len(blocks)-1]
+	next := nextBaseFee(head)
+
+	var (
+		inclusionTips []*big.Int
+		gasUsed       uint64
+	)
+	for _, block := range blocks {
+		if block.inclusionTip != nil {
+			inclusionTips = append(inclusionTips, block.inclusionTip)
+		}
+		gasUsed += block.gasUsed
+	}
+	sort.Slice(inclusionTips, func(i, j int) bool { return inclusionTips[i].Cmp(inclusionTips[j]) < 0 })
+	averageGasUsed := gasUsed / uint64(len(blocks))
+
+	mempool := o.pendingTips(head.hash, next)
+
+	estimate := &types.GasEstimateNotification{
+		BlockNumber: head.number,
+		BlockHash:   head.hash.Hex(),
+		BaseFee:     head.baseFee.String(),
+		NextBaseFee: next.String(),
+		Tiers:       make([]types.GasEstimateTier, 0, len(gasEstimateTiers)),
+		MempoolTips: tipHistogram(mempool),
+	}
+	for _, tier := range gasEstimateTiers {
+		priorityFee := new(big.Int)
+		if len(inclusionTips) > 0 {
+			priorityFee.Set(inclusionTips[int(float64(len(inclusionTips)-1)*tier.probability)])
+		}
+		// with a lower probability the transaction can wait for more blocks of pending transactions to be included
+		if competing := competingTip(mempool, uint64(float64(averageGasUsed)/tier.probability)); competing.Cmp(priorityFee) > 0 {
+			priorityFee = competing
+		}
+
+		maxFee := new(big.Int).Set(next)
+		for i := 1; i < tier.baseFeeBlocks; i++ {
+			maxFee.Add(maxFee, new(big.Int).Div(maxFee, big.NewInt(baseFeeChangeDenominator)))
+		}
+		maxFee.Add(maxFee, priorityFee)
+
+		estimate.Tiers = append(estimate.Tiers, types.GasEstimateTier{
+			Probability:          tier.probability,
+			MaxPriorityFeePerGas: priorityFee.String(),
+			MaxFeePerGas:         maxFee.String(),
+		})
+	}
+	return estimate, nil
+}
+
+// pendingTips returns the effective tips of the pending transactions at the base fee of the block following the head
+// sorted from the highest, the transactions whose fee cap is below the base fee are left out. The tips are reused for
+// the same head within gasOracleMempoolTTL
+func (o *GasOracle) pendingTips(head common.Hash, baseFee *big.Int) []pendingTip {
+	o.mempoolLock.Lock()
+	defer o.mempoolLock.Unlock()
+
+	now := o.clock.Now()
+	if o.mempool != nil && o.mempoolHead == head && now.Sub(o.mempoolTime) < gasOracleMempoolTTL {
+		return o.mempool
+	}
+
+	mempool := make([]pendingTip, 0)
+	for bxTx := range o.txStore.Iter() {
+		if !bxTx.HasContent() || bxTx.Flags().IsValidatorsOnly() || bxTx.Flags().IsNextValidator() {
+			continue
+		}
+		blockchainTx, err := bxTx.BlockchainTransaction(bxTx.Sender())
+		if err != nil {
+			continue
+		}
+		ethTx, ok := blockchainTx.(*types.EthTransaction)
+		if !ok || o.confirmed(bxTx.Sender(), ethTx) {
+			continue
+		}
+		tip := new(big.Int).Sub(ethTx.EffectiveGasFeeCap(), baseFee)
+		if tip.Sign() < 0 {
+			continue
+		}
+		if tipCap := ethTx.EffectiveGasTipCap(); tipCap.Cmp(tip) < 0 {
+			tip.Set(tipCap)
+		}
+		mempool = append(mempool, pendingTip{tip: tip, gas: ethTx.Gas()})
+	}
+	sort.Slice(mempool, func(i, j int) bool { return mempool[i].tip.Cmp(mempool[j].tip) > 0 })
+
+	o.mempool = mempool
+	o.mempoolHead = head
+	o.mempoolTime = now
+	return mempool
+}
+
+// confirmed returns true if a block already included the nonce of the transaction. The sender is not recovered from
+// the signature of the transaction if the tx store does not know it, as all the pending transactions are read
+func (o *GasOracle) confirmed(sender types.Sender, ethTx *types.EthTransaction) bool {
+	if o.pendingNonces == nil || sender == types.EmptySender {
+		return false
+	}
+	pending, ok := o.pendingNonces.PendingNonces(common.Address(sender))
+	return ok && pending.HasConfirmedNonce && ethTx.Nonce() < pending.ConfirmedNonce
+}
+
+// competingTip returns the tip of the pending transaction which fills the gas with the transactions paying more, or
+// zero if the pending transactions do not fill it
+func competingTip(mempool []pendingTip, gas uint64) *big.Int {
+	var cumulativeGas uint64
+	for _, pending := range mempool {
+		cumulativeGas += pending.gas
+		if cumulativeGas >= gas {
+			return new(big.Int).Set(pending.tip)
+		}
+	}
+	return new(big.Int)
+}
+
+// tipHistogram returns the number and gas of the pending transactions by tip
+func tipHistogram(mempool []pendingTip) []types.GasTipBucket {
+	bounds := make([]*big.Int, 0, len(gasTipBucketsGwei))
+	for _, gwei := range gasTipBucketsGwei {
+		bounds = append(bounds, big.NewInt(int64(gwei*params.GWei)))
+	}
+
+	buckets := make([]types.GasTipBucket, len(bounds))
+	for i, bound := range bounds {
+		buckets[i].MinTip = bound.String()
+		if i+1 < len(bounds) {
+			buckets[i].MaxTip = bounds[i+1].String()
+		}
+	}
+	for _, pending := range mempool {
+		i := sort.Search(len(bounds), func(i int) bool { return bounds[i].Cmp(pending.tip) > 0 }) - 1
+		buckets[i].Count++
+		buckets[i].Gas += pending.gas
+	}
+	return buckets
+}
+
+// nextBaseFee returns the base fee of the block following the block per EIP-1559
+func nextBaseFee(block gasOracleBlock) *big.Int {
+	return NextBaseFee(block.baseFee, block.gasUsed, block.gasLimit)
+}
+
+// NextBaseFee returns the base fee of the block following a block with the base fee, gas used and gas limit per EIP-1559
+func NextBaseFee(baseFee *big.Int, gasUsed, gasLimit uint64) *big.Int {
+	target := gasLimit / elasticityMultiplier
+	if target == 0 || gasUsed == target {
+		return new(big.Int).Set(baseFee)
+	}
+
+	var delta *big.Int
+	if gasUsed > target {
+		delta = new(big.Int).SetUint64(gasUsed - target)
+	} else {
+		delta = new(big.Int).SetUint64(target - gasUsed)
+	}
+	delta.Mul(delta, baseFee)
+	delta.Div(delta, new(big.Int).SetUint64(target))
+	delta.Div(delta, big.NewInt(baseFeeChangeDenominator))
+
+	if gasUsed > target {
+		if delta.Sign() == 0 {
+			delta.SetInt64(1)
+		}
+		return delta.Add(baseFee, delta)
+	}
+	next := delta.Sub(baseFee, delta)
+	if next.Sign() < 0 {
+		next.SetInt64(0)
+	}
+	return next
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testGasOracleGasLimit = 30_000_000
+
+func newGasOracleTx(t *testing.T, nonce uint64, gas uint64, feeCap, tipCap int64) *ethtypes.Transaction {
+	tx, err := ethtypes.SignNewTx(privateKey, ethtypes.LatestSignerForChainID(big.NewInt(1)), &ethtypes.DynamicFeeTx{
+		ChainID:   big.NewInt(1),
+		Nonce:     nonce,
+		Gas:       gas,
+		GasFeeCap: big.NewInt(feeCap),
+		GasTipCap: big.NewInt(tipCap),
+	})
+	require.NoError(t, err)
+	return tx
+}
+
+func newGasOracleBlock(t *testing.T, number uint64, baseFee int64, gasUsed uint64, tips ...int64) *ethtypes.Block {
+	txs := make([]*ethtypes.Transaction, 0, len(tips))
+	for i, tip := range tips {
+		txs = append(txs, newGasOracleTx(t, uint64(i), 21000, baseFee+tip, tip))
+	}
+	header := &ethtypes.Header{
+		Number:   new(big.Int).SetUint64(number),
+		BaseFee:  big.NewInt(baseFee),
+		GasLimit: testGasOracleGasLimit,
+		GasUsed:  gasUsed,
+	}
+	return ethtypes.NewBlockWithHeader(header).WithBody(txs, nil)
+}
+
+func addGasOracleTx(t *testing.T, txStore TxStore, tx *ethtypes.Transaction, sender types.Sender) {
+	content, err := rlp.EncodeToBytes(tx)
+	require.NoError(t, err)
+	txStore.Add(types.SHA256Hash(tx.Hash()), content, types.ShortIDEmpty, bxgateway.MainnetNum, false, types.TFPaidTx, time.Now(), 0, sender)
+}
+
+func testGasEstimateFees(estimate *types.GasEstimateNotification) (priorityFees []string, maxFees []string) {
+	for _, tier := range estimate.Tiers {
+		priorityFees = append(priorityFees, tier.MaxPriorityFeePerGas)
+		maxFees = append(maxFees, tier.MaxFeePerGas)
+	}
+	return priorityFees, maxFees
+}
+
+func TestGasOracle(t *testing.T) {
+	txStore := NewBxTxStore(time.Minute, time.Minute, time.Minute, NewEmptyShortIDAssigner(), NewHashHistory("seenTxs", time.Minute), nil, 30*time.Minute, NoOpBloomFilter{})
+	pendingNonces := NewPendingNonceTracker(utils.RealClock{})
+	clock := &utils.MockClock{}
+	clock.SetTime(time.Now())
+	oracle := NewGasOracle(clock, &txStore, pendingNonces)
+
+	_, err := oracle.Estimate()
+	assert.ErrorIs(t, err, ErrGasOracleNoBlocks)
+
+	// the blocks are half full, so the base fee does not change, and the cheapest transaction of block k pays a tip of k
+	for k := int64(1); k <= 10; k++ {
+		tips := make([]int64, 0, 10)
+		for i := int64(1); i <= 10; i++ {
+			tips = append(tips, k*i)
+		}
+		oracle.OnBlock(newGasOracleBlock(t, uint64(k), 100, testGasOracleGasLimit/2, tips...))
+	}
+
+	estimate, err := oracle.Estimate()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), estimate.BlockNumber)
+	assert.Equal(t, "100", estimate.NextBaseFee)
+	priorityFees, maxFees := testGasEstimateFees(estimate)
+	assert.Equal(t, []string{"5", "7", "9", "9"}, priorityFees)
+	// the max fees cover 1, 2, 3 and 6 blocks of maximum base fee increase
+	assert.Equal(t, []string{"105", "119", "135", "186"}, maxFees)
+
+	// the pending transactions paying more fill the next blocks
+	addGasOracleTx(t, &txStore, newGasOracleTx(t, 1, 10_000_000, 200, 50), types.EmptySender)
+	addGasOracleTx(t, &txStore, newGasOracleTx(t, 2, 10_000_000, 200, 40), types.EmptySender)
+	addGasOracleTx(t, &txStore, newGasOracleTx(t, 3, 10_000_000, 130, 40), types.EmptySender)
+	// the fee cap is below the base fee
+	addGasOracleTx(t, &txStore, newGasOracleTx(t, 4, 10_000_000, 90, 90), types.EmptySender)
+	// the nonce is already confirmed
+	from := crypto.PubkeyToAddress(privateKey.PublicKey)
+	confirmed := newGasOracleTx(t, 5, 10_000_000, 1000, 1000)
+	addGasOracleTx(t, &txStore, confirmed, types.Sender(from))
+	pendingNonces.Track(from, 6, types.SHA256Hash{1})
+	pendingNonces.OnBlock(types.NewRawBxBlock(types.SHA256Hash{}, types.SHA256Hash{}, types.BxBlockTypeEth, nil,
+		[]*types.BxBlockTransaction{types.NewBxBlockTransaction(types.SHA256Hash{1}, nil)}, nil, nil, big.NewInt(11), 0))
+
+	// the tips of the pending transactions are reused for a second
+	estimate, err = oracle.Estimate()
+	require.NoError(t, err)
+	priorityFees, _ = testGasEstimateFees(estimate)
+	assert.Equal(t, []string{"5", "7", "9", "9"}, priorityFees)
+
+	clock.IncTime(gasOracleMempoolTTL)
+	estimate, err = oracle.Estimate()
+	require.NoError(t, err)
+	priorityFees, maxFees = testGasEstimateFees(estimate)
+	assert.Equal(t, []string{"30", "30", "40", "40"}, priorityFees)
+	assert.Equal(t, []string{"130", "142", "166", "217"}, maxFees)
+	require.NotEmpty(t, estimate.MempoolTips)
+	assert.Equal(t, "0", estimate.MempoolTips[0].MinTip)
+	assert.Equal(t, 3, estimate.MempoolTips[0].Count)
+	assert.Equal(t, uint64(30_000_000), estimate.MempoolTips[0].Gas)
+
+	// a reorganization replaces the blocks from its height, a full block raises the base fee by 12.5%
+	oracle.OnBlock(newGasOracleBlock(t, 5, 200, testGasOracleGasLimit, 1))
+	estimate, err = oracle.Estimate()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), estimate.BlockNumber)
+	assert.Equal(t, "200", estimate.BaseFee)
+	assert.Equal(t, "225", estimate.NextBaseFee)
+}
+
+func TestNextBaseFee(t *testing.T) {
+	tests := []struct {
+		gasUsed  uint64
+		expected int64
+	}{
+		{gasUsed: testGasOracleGasLimit / 2, expected: 1000},
+		{gasUsed: testGasOracleGasLimit, expected: 1125},
+		{gasUsed: 0, expected: 875},
+		{gasUsed: testGasOracleGasLimit / 4, expected: 938},
+	}
+	for _, test := range tests {
+		block := gasOracleBlock{baseFee: big.NewInt(1000), gasUsed: test.gasUsed, gasLimit: testGasOracleGasLimit}
+		assert.Equal(t, test.expected, nextBaseFee(block).Int64())
+	}
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
 	"github.com/bloXroute-Labs/gateway/v2/connections"
+	"github.com/bloXroute-Labs/gateway/v2/services/metrics"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 // MessageQueue - queue of messages to process
@@ -16,6 +18,8 @@ type MessageQueue struct {
 	txsCount uint64
 	ctx      context.Context
 	cancel   context.CancelFunc
+	depth    prometheus.Gauge
+	wait     prometheus.Observer
 }
 
 type msgWithSource struct {
@@ -28,13 +32,15 @@ type msgWithSource struct {
 // MessageQueueCallback - callback function for MessageQueue
 type MessageQueueCallback func(msg bxmessage.Message, source connections.Conn, waitingDuration time.Duration, workerChannelPosition int)
 
-// NewMsgQueue - create MessageQueue object, running workers and return MessageQueue
-func NewMsgQueue(numOfWorkers int, queueSize int, callBack MessageQueueCallback) MessageQueue {
+// NewMsgQueue - create MessageQueue object, running workers and return MessageQueue. The name labels the queue metrics
+func NewMsgQueue(name string, numOfWorkers int, queueSize int, callBack MessageQueueCallback) MessageQueue {
 	ctx, cancel := context.WithCancel(context.Background())
 	msgQueue := MessageQueue{
 		queue:  make(chan msgWithSource, queueSize),
 		ctx:    ctx,
 		cancel: cancel,
+		depth:  metrics.MessageQueueDepth.WithLabelValues(name),
+		wait:   metrics.MessageQueueWait.WithLabelValues(name),
 	}
 	for i := 0; i < numOfWorkers; i++ {
 		go msgQueue.work(callBack)
@@ -47,6 +53,8 @@ func (tq *MessageQueue) work(callBack MessageQueueCallback) {
 		select {
 		case tx := <-tq.queue:
 			waitDuration := time.Since(tx.waitStartTime)
+			tq.depth.Dec()
+			tq.wait.Observe(waitDuration.Seconds())
 			callBack(tx.msg, tx.source, waitDuration, tx.channelPosition)
 		case <-tq.ctx.Done():
 			return
@@ -60,10 +68,13 @@ func (tq *MessageQueue) Insert(msg bxmessage.Message, source connections.Conn) e
 	case <-tq.ctx.Done():
 		return nil
 	default:
+		// counted before the insert, a worker may pick the message up right away
+		tq.depth.Inc()
 		select {
 		case tq.queue <- msgWithSource{source: source, msg: msg, waitStartTime: time.Now(), channelPosition: len(tq.queue)}:
 			atomic.AddUint64(&tq.txsCount, 1)
 		default:
+			tq.depth.Dec()
 			return errors.New("channel is full")
 		}
 	}
@@ -9,12 +9,14 @@ import (
 	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
 	"github.com/bloXroute-Labs/gateway/v2/connections"
 	"github.com/bloXroute-Labs/gateway/v2/connections/handler"
+	"github.com/bloXroute-Labs/gateway/v2/services/metrics"
 	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 )
 
 type MockConnection struct {
-	handler.Relay
+	*handler.Relay
 }
 
 func (m MockConnection) GetNodeID() types.NodeID {
@@ -31,7 +33,7 @@ func TestMessageQueueStop(t *testing.T) {
 		wg.Done()
 	}
 
-	messageQueue := NewMsgQueue(3, 100, testAdapter)
+	messageQueue := NewMsgQueue("test", 3, 100, testAdapter)
 	wg.Add(3)
 	require.NoError(t, messageQueue.Insert(&bxmessage.Tx{}, conn))
 	require.NoError(t, messageQueue.Insert(&bxmessage.Tx{}, conn))
@@ -55,7 +57,7 @@ func TestMessageQueueInsert(t *testing.T) {
 		wg.Done()
 	}
 
-	messageQueue := NewMsgQueue(3, 100, testAdapter)
+	messageQueue := NewMsgQueue("test", 3, 100, testAdapter)
 	wg.Add(3)
 	require.NoError(t, messageQueue.Insert(&bxmessage.Tx{}, conn))
 	require.NoError(t, messageQueue.Insert(&bxmessage.Tx{}, conn))
@@ -74,12 +76,14 @@ func TestMessageQueueChannelIsFull(t *testing.T) {
 		<-syncChan
 	}
 
-	messageQueue := NewMsgQueue(1, 1, testAdapter)
+	messageQueue := NewMsgQueue("full", 1, 1, testAdapter)
 	require.NoError(t, messageQueue.Insert(&bxmessage.Tx{}, conn))
 	<-syncChan // wait until worker read the channel and blocks
 
 	require.NoError(t, messageQueue.Insert(&bxmessage.Tx{}, conn))
 	require.Error(t, messageQueue.Insert(&bxmessage.Tx{}, conn))
+	// only the waiting message is counted, in the depth of its own queue
+	require.Equal(t, float64(1), testutil.ToFloat64(metrics.MessageQueueDepth.WithLabelValues("full")))
 
 	syncChan <- struct{}{} // release worker
 }
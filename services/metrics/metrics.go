@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "gateway"
+
+// Block propagation sources, the first source which delivered the block labels its propagation delay
+const (
+	SourceBDN  = "bdn"
+	SourceNode = "node"
+)
+
+// Message queue names labelling the message queue metrics, the queues of all the peers share the peer label
+const (
+	QueueTxs      = "txs"
+	QueueTxsOrder = "txs_order"
+	QueuePeer     = "peer"
+)
+
+// Relay connection states reported by RelayConnectionState
+const (
+	RelayDisconnected = 0
+	RelayConnected    = 1
+	RelaySynced       = 2
+)
+
+var registry = prometheus.NewRegistry()
+
+var (
+	// TxStoreSize is the number of transactions in the BxTxStore
+	TxStoreSize = newSourceGauge("tx_store_size", "Number of transactions in the tx store")
+	// TxStoreCleanups counts the periodic cleanups of the BxTxStore
+	TxStoreCleanups = newCounter("tx_store_cleanups_total", "Number of tx store cleanups")
+	// TxStoreCleaned counts the transactions removed by the BxTxStore cleanups
+	TxStoreCleaned = newCounterVec("tx_store_cleaned_total", "Number of transactions removed by the tx store cleanups", "reason")
+
+	// MessageQueueDepth is the number of messages waiting in each message queue
+	MessageQueueDepth = newGaugeVec("message_queue_depth", "Number of messages waiting in the message queues by queue", "queue")
+	// MessageQueueWait is the time messages spend in each message queue
+	MessageQueueWait = newHistogramVec("message_queue_wait_seconds", "Time messages wait in the message queues by queue",
+		prometheus.ExponentialBuckets(0.0001, 4, 10), "queue")
+
+	// FeedSubscribers is the number of subscriptions of each feed
+	FeedSubscribers = newGaugeVec("feed_subscribers", "Number of subscriptions by feed", "feed")
+	// FeedFanoutDuration is the time it takes to pass a notification to all the subscriptions of its feed
+	FeedFanoutDuration = newHistogramVec("feed_fanout_seconds", "Time to pass a notification to the subscriptions of its feed",
+		prometheus.ExponentialBuckets(0.00001, 4, 10), "feed")
+
+	// RelayConnectionState is the state of each relay connection, see RelayDisconnected, RelayConnected and RelaySynced
+	RelayConnectionState = newSourceGaugeVec("relay_connection_state", "Relay connection state (0 disconnected, 1 connected, 2 tx store synced)", "relay")
+
+	// BlockchainPeers is the number of connected blockchain nodes
+	BlockchainPeers = newSourceGauge("blockchain_peers", "Number of connected blockchain nodes")
+	// BlockPropagationDelay is the time between the BDN and the blockchain node delivering the same block
+	BlockPropagationDelay = newHistogramVec("block_propagation_delay_seconds", "Time between the first and the second source delivering a block",
+		prometheus.ExponentialBuckets(0.01, 2, 12), "first")
+
+	// BurstLimiterExcess is the number of transactions rejected by the account burst limiters in the last interval
+	BurstLimiterExcess = newSourceGaugeVec("burst_limiter_excess", "Number of transactions exceeding the account burst limits by interval", "interval")
+)
+
+// Handler serves the gateway metrics in the prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+func newCounter(name, help string) prometheus.Counter {
+	c := prometheus.NewCounter(prometheus.CounterOpts{Namespace: namespace, Name: name, Help: help})
+	registry.MustRegister(c)
+	return c
+}
+
+func newCounterVec(name, help string, labels ...string) *prometheus.CounterVec {
+	c := prometheus.NewCounterVec(prometheus.CounterOpts{Namespace: namespace, Name: name, Help: help}, labels)
+	registry.MustRegister(c)
+	return c
+}
+
+func newGaugeVec(name, help string, labels ...string) *prometheus.GaugeVec {
+	g := prometheus.NewGaugeVec(prometheus.GaugeOpts{Namespace: namespace, Name: name, Help: help}, labels)
+	registry.MustRegister(g)
+	return g
+}
+
+func newHistogramVec(name, help string, buckets []float64, labels ...string) *prometheus.HistogramVec {
+	h := prometheus.NewHistogramVec(prometheus.HistogramOpts{Namespace: namespace, Name: name, Help: help, Buckets: buckets}, labels)
+	registry.MustRegister(h)
+	return h
+}
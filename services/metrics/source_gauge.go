@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// SourceGauge is a gauge whose value is read on scrape from a source set by the component owning the value
+type SourceGauge struct {
+	prometheus.GaugeFunc
+	source atomic.Pointer[func() float64]
+}
+
+func newSourceGauge(name, help string) *SourceGauge {
+	g := &SourceGauge{}
+	g.GaugeFunc = prometheus.NewGaugeFunc(prometheus.GaugeOpts{Namespace: namespace, Name: name, Help: help}, g.value)
+	registry.MustRegister(g.GaugeFunc)
+	return g
+}
+
+// SetSource sets the function reporting the gauge value, replacing the previous one
+func (g *SourceGauge) SetSource(source func() float64) {
+	g.source.Store(&source)
+}
+
+func (g *SourceGauge) value() float64 {
+	source := g.source.Load()
+	if source == nil {
+		return 0
+	}
+	return (*source)()
+}
+
+// SourceGaugeVec is a gauge with a single label whose values are read on scrape from a source per label value
+type SourceGaugeVec struct {
+	desc    *prometheus.Desc
+	lock    sync.RWMutex
+	sources map[string]func() float64
+}
+
+func newSourceGaugeVec(name, help, label string) *SourceGaugeVec {
+	g := &SourceGaugeVec{
+		desc:    prometheus.NewDesc(prometheus.BuildFQName(namespace, "", name), help, []string{label}, nil),
+		sources: make(map[string]func() float64),
+	}
+	registry.MustRegister(g)
+	return g
+}
+
+// SetSource sets the function reporting the gauge value for a label value
+func (g *SourceGaugeVec) SetSource(labelValue string, source func() float64) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.sources[labelValue] = source
+}
+
+// Delete stops reporting a label value
+func (g *SourceGaugeVec) Delete(labelValue string) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	delete(g.sources, labelValue)
+}
+
+// Describe implements prometheus.Collector
+func (g *SourceGaugeVec) Describe(ch chan<- *prometheus.Desc) {
+	ch <- g.desc
+}
+
+// Collect implements prometheus.Collector
+func (g *SourceGaugeVec) Collect(ch chan<- prometheus.Metric) {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	for labelValue, source := range g.sources {
+		ch <- prometheus.MustNewConstMetric(g.desc, prometheus.GaugeValue, source(), labelValue)
+	}
+}
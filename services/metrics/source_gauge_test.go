@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceGauge(t *testing.T) {
+	g := &SourceGauge{}
+	assert.Equal(t, float64(0), g.value())
+
+	g.SetSource(func() float64 { return 5 })
+	assert.Equal(t, float64(5), g.value())
+
+	g.SetSource(func() float64 { return 7 })
+	assert.Equal(t, float64(7), g.value())
+}
+
+func TestSourceGaugeVec(t *testing.T) {
+	RelayConnectionState.SetSource("1.1.1.1:1809", func() float64 { return RelaySynced })
+	RelayConnectionState.SetSource("2.2.2.2:1809", func() float64 { return RelayDisconnected })
+	defer RelayConnectionState.Delete("1.1.1.1:1809")
+
+	expected := `
+# HELP gateway_relay_connection_state Relay connection state (0 disconnected, 1 connected, 2 tx store synced)
+# TYPE gateway_relay_connection_state gauge
+gateway_relay_connection_state{relay="1.1.1.1:1809"} 2
+gateway_relay_connection_state{relay="2.2.2.2:1809"} 0
+`
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "gateway_relay_connection_state"))
+
+	RelayConnectionState.Delete("2.2.2.2:1809")
+	assert.Equal(t, 1, testutil.CollectAndCount(RelayConnectionState))
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// pendingNonceExpiry is how long a pending transaction is tracked if no block including it or a later nonce of its sender is seen
+	pendingNonceExpiry = time.Hour
+	// maxPendingNonceGaps bounds the gaps returned for a sender, the nonces of a sender can be far apart
+	maxPendingNonceGaps = 1000
+)
+
+// PendingNonceTx is the pending transaction occupying a nonce of a sender
+type PendingNonceTx struct {
+	Nonce    uint64
+	Hash     types.SHA256Hash
+	SeenTime time.Time
+}
+
+// PendingNonces is the view of the pending transactions of a sender observed by the gateway
+type PendingNonces struct {
+	Address common.Address
+	// ConfirmedNonce is the nonce following the last transaction of the sender seen in a block, valid if HasConfirmedNonce
+	ConfirmedNonce    uint64
+	HasConfirmedNonce bool
+	LowestNonce       uint64
+	HighestNonce      uint64
+	// NextNonce is the nonce of the next transaction of the sender, the pending count of eth_getTransactionCount
+	NextNonce uint64
+	// Txs are the pending transactions ordered by nonce
+	Txs []PendingNonceTx
+	// Gaps are the missing nonces from the confirmed nonce, or the lowest pending nonce if unknown, to the highest pending nonce
+	Gaps []uint64
+}
+
+type pendingNonceTx struct {
+	hash     types.SHA256Hash
+	seenTime time.Time
+}
+
+type senderNonces struct {
+	txs               map[uint64]pendingNonceTx
+	confirmedNonce    uint64
+	hasConfirmedNonce bool
+	lastUpdate        time.Time
+}
+
+type senderNonce struct {
+	from  common.Address
+	nonce uint64
+}
+
+// PendingNonceTracker indexes the pending transactions of the senders by nonce, and drops them once a block includes them
+type PendingNonceTracker struct {
+	clock   utils.Clock
+	lock    sync.RWMutex
+	senders map[common.Address]*senderNonces
+	hashes  map[types.SHA256Hash]senderNonce
+}
+
+// NewPendingNonceTracker creates an empty PendingNonceTracker
+func NewPendingNonceTracker(clock utils.Clock) *PendingNonceTracker {
+	return &PendingNonceTracker{
+		clock:   clock,
+		senders: make(map[common.Address]*senderNonces),
+		hashes:  make(map[types.SHA256Hash]senderNonce),
+	}
+}
+
+// Track adds a pending transaction of the sender, replacing the transaction seen with the same nonce
+func (pt *PendingNonceTracker) Track(from common.Address, nonce uint64, hash types.SHA256Hash) {
+	now := pt.clock.Now()
+
+	pt.lock.Lock()
+	defer pt.lock.Unlock()
+
+	if _, ok := pt.hashes[hash]; ok {
+		return
+	}
+
+	sender, ok := pt.senders[from]
+	if !ok {
+		sender = &senderNonces{txs: make(map[uint64]pendingNonceTx)}
+		pt.senders[from] = sender
+	}
+	if sender.hasConfirmedNonce && nonce < sender.confirmedNonce {
+		// the nonce was already used by a transaction in a block
+		return
+	}
+
+	if replaced, ok := sender.txs[nonce]; ok {
+		delete(pt.hashes, replaced.hash)
+	}
+	sender.txs[nonce] = pendingNonceTx{hash: hash, seenTime: now}
+	sender.lastUpdate = now
+	pt.hashes[hash] = senderNonce{from: from, nonce: nonce}
+}
+
+// OnBlock drops the transactions of the block and the lower nonces of their senders, which can no longer be included
+func (pt *PendingNonceTracker) OnBlock(block *types.BxBlock) {
+	pt.lock.Lock()
+	defer pt.lock.Unlock()
+
+	if len(pt.hashes) == 0 {
+		return
+	}
+
+	now := pt.clock.Now()
+	for _, blockTx := range block.Txs {
+		included, ok := pt.hashes[blockTx.Hash()]
+		if !ok {
+			continue
+		}
+
+		sender := pt.senders[included.from]
+		if !sender.hasConfirmedNonce || included.nonce >= sender.confirmedNonce {
+			sender.confirmedNonce = included.nonce + 1
+			sender.hasConfirmedNonce = true
+		}
+		sender.lastUpdate = now
+		for nonce, tx := range sender.txs {
+			if nonce < sender.confirmedNonce {
+				delete(sender.txs, nonce)
+				delete(pt.hashes, tx.hash)
+			}
+		}
+	}
+}
+
+// PendingNonces returns the pending transactions of the sender, false if none of its transactions were seen or they expired
+func (pt *PendingNonceTracker) PendingNonces(from common.Address) (PendingNonces, bool) {
+	pt.lock.RLock()
+	defer pt.lock.RUnlock()
+
+	sender, ok := pt.senders[from]
+	if !ok {
+		return PendingNonces{Address: from}, false
+	}
+
+	result := PendingNonces{
+		Address:           from,
+		ConfirmedNonce:    sender.confirmedNonce,
+		HasConfirmedNonce: sender.hasConfirmedNonce,
+		NextNonce:         sender.confirmedNonce,
+		Txs:               make([]PendingNonceTx, 0, len(sender.txs)),
+	}
+	for nonce, tx := range sender.txs {
+		result.Txs = append(result.Txs, PendingNonceTx{Nonce: nonce, Hash: tx.hash, SeenTime: tx.seenTime})
+	}
+	if len(result.Txs) == 0 {
+		return result, true
+	}
+
+	sort.Slice(result.Txs, func(i, j int) bool { return result.Txs[i].Nonce < result.Txs[j].Nonce })
+	result.LowestNonce = result.Txs[0].Nonce
+	result.HighestNonce = result.Txs[len(result.Txs)-1].Nonce
+	result.NextNonce = result.HighestNonce + 1
+
+	expected := result.LowestNonce
+	if sender.hasConfirmedNonce {
+		expected = sender.confirmedNonce
+	}
+	for _, tx := range result.Txs {
+		for ; expected < tx.Nonce && len(result.Gaps) < maxPendingNonceGaps; expected++ {
+			result.Gaps = append(result.Gaps, expected)
+		}
+		expected = tx.Nonce + 1
+	}
+	return result, true
+}
+
+// Size returns the number of tracked pending transactions
+func (pt *PendingNonceTracker) Size() int {
+	pt.lock.RLock()
+	defer pt.lock.RUnlock()
+	return len(pt.hashes)
+}
+
+// clean drops the pending transactions older than pendingNonceExpiry, and the senders without recent activity
+func (pt *PendingNonceTracker) clean() {
+	now := pt.clock.Now()
+
+	pt.lock.Lock()
+	defer pt.lock.Unlock()
+
+	sizeBefore := len(pt.hashes)
+	for from, sender := range pt.senders {
+		for nonce, tx := range sender.txs {
+			if now.Sub(tx.seenTime) > pendingNonceExpiry {
+				delete(sender.txs, nonce)
+				delete(pt.hashes, tx.hash)
+			}
+		}
+		if len(sender.txs) == 0 && now.Sub(sender.lastUpdate) > pendingNonceExpiry {
+			delete(pt.senders, from)
+		}
+	}
+
+	log.Tracef("PendingNonceTracker cleanup done. Size at start %v, cleaned %v", sizeBefore, sizeBefore-len(pt.hashes))
+}
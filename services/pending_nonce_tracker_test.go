@@ -0,0 +1,137 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
+	"github.com/bloXroute-Labs/gateway/v2/test/bxmock"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPendingNonceTracker(t *testing.T) {
+	clock := utils.NewMockClock()
+	clock.SetTime(time.Now())
+	tracker := NewPendingNonceTracker(clock)
+	from := common.Address{0xaa}
+
+	_, ok := tracker.PendingNonces(from)
+	assert.False(t, ok)
+
+	tracker.Track(from, 5, types.SHA256Hash{5})
+	tracker.Track(from, 8, types.SHA256Hash{8})
+	tracker.Track(from, 6, types.SHA256Hash{6})
+	// replacement of nonce 6
+	tracker.Track(from, 6, types.SHA256Hash{0x66})
+
+	pending, ok := tracker.PendingNonces(from)
+	require.True(t, ok)
+	assert.Equal(t, from, pending.Address)
+	assert.False(t, pending.HasConfirmedNonce)
+	assert.Equal(t, uint64(5), pending.LowestNonce)
+	assert.Equal(t, uint64(8), pending.HighestNonce)
+	assert.Equal(t, uint64(9), pending.NextNonce)
+	assert.Equal(t, []uint64{7}, pending.Gaps)
+	require.Len(t, pending.Txs, 3)
+	assert.Equal(t, types.SHA256Hash{0x66}, pending.Txs[1].Hash)
+	assert.Equal(t, 3, tracker.Size())
+
+	// the block includes nonce 5 of the sender, and a transaction which is not tracked
+	block := &types.BxBlock{Txs: []*types.BxBlockTransaction{
+		types.NewBxBlockTransaction(types.SHA256Hash{5}, nil),
+		types.NewBxBlockTransaction(types.SHA256Hash{1}, nil),
+	}}
+	tracker.OnBlock(block)
+
+	pending, ok = tracker.PendingNonces(from)
+	require.True(t, ok)
+	assert.True(t, pending.HasConfirmedNonce)
+	assert.Equal(t, uint64(6), pending.ConfirmedNonce)
+	assert.Equal(t, uint64(6), pending.LowestNonce)
+	assert.Equal(t, []uint64{7}, pending.Gaps)
+
+	// a nonce lower than the confirmed nonce is not pending
+	tracker.Track(from, 4, types.SHA256Hash{4})
+	assert.Equal(t, 2, tracker.Size())
+
+	// inclusion of nonce 8 drops the lower nonces
+	tracker.OnBlock(&types.BxBlock{Txs: []*types.BxBlockTransaction{types.NewBxBlockTransaction(types.SHA256Hash{8}, nil)}})
+	pending, ok = tracker.PendingNonces(from)
+	require.True(t, ok)
+	assert.Empty(t, pending.Txs)
+	assert.Empty(t, pending.Gaps)
+	assert.Equal(t, uint64(9), pending.NextNonce)
+	assert.Equal(t, 0, tracker.Size())
+}
+
+func TestPendingNonceTracker_StuckNonce(t *testing.T) {
+	clock := utils.NewMockClock()
+	clock.SetTime(time.Now())
+	tracker := NewPendingNonceTracker(clock)
+	from := common.Address{0xaa}
+
+	tracker.Track(from, 1, types.SHA256Hash{1})
+	tracker.Track(from, 4, types.SHA256Hash{4})
+	tracker.OnBlock(&types.BxBlock{Txs: []*types.BxBlockTransaction{types.NewBxBlockTransaction(types.SHA256Hash{1}, nil)}})
+
+	// nonces 2 and 3 are missing, nonce 4 can't be included
+	pending, ok := tracker.PendingNonces(from)
+	require.True(t, ok)
+	assert.Equal(t, []uint64{2, 3}, pending.Gaps)
+	assert.Equal(t, uint64(5), pending.NextNonce)
+}
+
+func TestPendingNonceTracker_clean(t *testing.T) {
+	clock := utils.NewMockClock()
+	clock.SetTime(time.Now())
+	tracker := NewPendingNonceTracker(clock)
+	from := common.Address{0xaa}
+
+	tracker.Track(from, 1, types.SHA256Hash{1})
+	clock.IncTime(pendingNonceExpiry / 2)
+	tracker.Track(from, 2, types.SHA256Hash{2})
+
+	clock.IncTime(pendingNonceExpiry/2 + time.Second)
+	tracker.clean()
+	pending, ok := tracker.PendingNonces(from)
+	require.True(t, ok)
+	require.Len(t, pending.Txs, 1)
+	assert.Equal(t, uint64(2), pending.Txs[0].Nonce)
+
+	clock.IncTime(pendingNonceExpiry)
+	tracker.clean()
+	_, ok = tracker.PendingNonces(from)
+	assert.False(t, ok)
+}
+
+func TestEthTxStore_PendingNonces(t *testing.T) {
+	// the network does not extract the senders, only the paid transactions are tracked
+	network := sdnmessage.BlockchainNetwork{}
+	store := NewEthTxStore(&utils.MockClock{}, 30*time.Second, 30*time.Second, 30*time.Second,
+		NewEmptyShortIDAssigner(), NewHashHistory("seenTxs", 30*time.Minute), nil, sdnmessage.BlockchainNetworks{testNetworkNum: &network}, newTestBloomFilter(t))
+	from := crypto.PubkeyToAddress(privateKey.PublicKey)
+
+	tx := bxmock.NewSignedEthTx(ethtypes.LegacyTxType, 3, privateKey, nil)
+	content, _ := rlp.EncodeToBytes(&tx)
+	result := store.Add(types.SHA256Hash{3}, content, types.ShortIDEmpty, testNetworkNum, true, types.TFPaidTx, time.Now(), tx.ChainId().Int64(), types.EmptySender)
+	require.False(t, result.FailedValidation)
+
+	tx = bxmock.NewSignedEthTx(ethtypes.LegacyTxType, 4, privateKey, nil)
+	content, _ = rlp.EncodeToBytes(&tx)
+	result = store.Add(types.SHA256Hash{4}, content, types.ShortIDEmpty, testNetworkNum, true, 0, time.Now(), tx.ChainId().Int64(), types.EmptySender)
+	require.False(t, result.FailedValidation)
+
+	pending, ok := store.PendingNonceTracker().PendingNonces(from)
+	require.True(t, ok)
+	require.Len(t, pending.Txs, 1)
+	assert.Equal(t, uint64(3), pending.Txs[0].Nonce)
+	assert.Equal(t, types.SHA256Hash{3}, pending.Txs[0].Hash)
+	assert.Equal(t, uint64(4), pending.NextNonce)
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
 	"github.com/bloXroute-Labs/gateway/v2/connections"
 	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/services/metrics"
 	"github.com/bloXroute-Labs/gateway/v2/types"
 	"github.com/bloXroute-Labs/gateway/v2/utils/syncmap"
 )
@@ -26,7 +27,7 @@ func (q *SerialPeerQueue) AddPeer(peerID types.NodeID, callback MessageQueueCall
 		return
 	}
 
-	newMessageQueue := NewMsgQueue(1, bxgateway.ParallelQueueChannelSize, callback)
+	newMessageQueue := NewMsgQueue(metrics.QueuePeer, 1, bxgateway.ParallelQueueChannelSize, callback)
 	q.queues.Store(peerID, newMessageQueue)
 }
 
@@ -0,0 +1,60 @@
+package statistics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// FileSink writes the STATS records to a local file as JSON lines, the file is rotated once it reaches its max size
+type FileSink struct {
+	lock   sync.Mutex
+	writer io.WriteCloser
+}
+
+type fileRecord struct {
+	LogName string `json:"log_name"`
+	LogRecord
+}
+
+// NewFileSink creates a FileSink writing to path, keeping maxBackups rotated files of maxSizeMB
+func NewFileSink(path string, maxSizeMB, maxBackups int) (*FileSink, error) {
+	if path == "" {
+		return nil, errors.New("stats file path is not set")
+	}
+	return &FileSink{
+		writer: &lumberjack.Logger{
+			Filename:   path,
+			MaxSize:    maxSizeMB,
+			MaxBackups: maxBackups,
+			LocalTime:  true,
+			Compress:   true,
+		},
+	}, nil
+}
+
+// Post appends the record to the file
+func (f *FileSink) Post(logName string, _ time.Time, record LogRecord) error {
+	line, err := json.Marshal(fileRecord{LogName: logName, LogRecord: record})
+	if err != nil {
+		return fmt.Errorf("failed to marshal %v record: %v", logName, err)
+	}
+	line = append(line, '\n')
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	_, err = f.writer.Write(line)
+	return err
+}
+
+// Close closes the file
+func (f *FileSink) Close() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.writer.Close()
+}
@@ -16,7 +16,6 @@ import (
 	log "github.com/bloXroute-Labs/gateway/v2/logger"
 	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
 	"github.com/bloXroute-Labs/gateway/v2/types"
-	"github.com/fluent/fluent-logger-golang/fluent"
 )
 
 const (
@@ -50,6 +49,7 @@ type Stats interface {
 	BundleSentToBuilderStats(timestamp time.Time, builderName string, bundleHash string, blockNumber string, uuid string, networkNum types.NetworkNum, accountID types.AccountID, accountTier sdnmessage.AccountTier, builderURL string, statusCode int)
 	AddBuilderGatewaySentBundleToMEVBuilderEvent(timestamp time.Time, builderName, bundleHash, blockNumber, uuid, builderURL string,
 		networkNum types.NetworkNum, accountID types.AccountID, accountTier sdnmessage.AccountTier, statusCode int)
+	Close() error
 }
 
 // NoStats is used to generate empty stats
@@ -102,10 +102,15 @@ func (NoStats) AddBuilderGatewaySentBundleToMEVBuilderEvent(_ time.Time, _, _, _
 	_ types.NetworkNum, _ types.AccountID, _ sdnmessage.AccountTier, _ int) {
 }
 
+// Close does nothing
+func (NoStats) Close() error {
+	return nil
+}
+
 // FluentdStats struct that represents fluentd stats info
 type FluentdStats struct {
 	NodeID            types.NodeID
-	FluentD           *fluent.Fluent
+	Sink              Sink
 	Networks          map[types.NetworkNum]sdnmessage.BlockchainNetwork
 	Lock              *sync.RWMutex
 	logNetworkContent bool
@@ -323,16 +328,26 @@ func (s FluentdStats) AddGatewayBundleEvent(name string, source connections.Conn
 	s.LogToFluentD(record, time.Now(), "stats.gateway.bundles.events.p")
 }
 
-// NewStats is used to create transaction STATS logger
-func NewStats(fluentDEnabled bool, fluentDHost string, nodeID types.NodeID, networks *sdnmessage.BlockchainNetworks, logNetworkContent bool) Stats {
-	if !fluentDEnabled {
-		return NoStats{}
+// NewStats is used to create transaction STATS logger sending the records to the configured backends
+func NewStats(sinkConfig SinkConfig, nodeID types.NodeID, networks *sdnmessage.BlockchainNetworks, logNetworkContent bool) (Stats, error) {
+	if len(sinkConfig.Backends) == 0 {
+		return NoStats{}, nil
+	}
+
+	sink, err := NewSink(sinkConfig)
+	if err != nil {
+		return nil, err
 	}
 
-	return newStats(fluentDHost, nodeID, networks, logNetworkContent)
+	return newStats(sink, nodeID, networks, logNetworkContent), nil
 }
 
-// LogToFluentD log info to the fluentd
+// Close sends the pending records and closes the stats backends
+func (s FluentdStats) Close() error {
+	return s.Sink.Close()
+}
+
+// LogToFluentD log info to the stats backends
 func (s FluentdStats) LogToFluentD(record interface{}, ts time.Time, logName string) {
 	d := LogRecord{
 		Level:     "STATS",
@@ -342,27 +357,16 @@ func (s FluentdStats) LogToFluentD(record interface{}, ts time.Time, logName str
 		Timestamp: ts.Format(DateFormat),
 	}
 
-	err := s.FluentD.EncodeAndPostData("bx.go.log", ts, d)
+	err := s.Sink.Post(logName, ts, d)
 	if err != nil {
-		log.Errorf("Failed to send STATS to fluentd - %v", err)
+		log.Errorf("Failed to send STATS to %v - %v", logName, err)
 	}
 }
 
-func newStats(fluentdHost string, nodeID types.NodeID, sdnHTTPNetworks *sdnmessage.BlockchainNetworks, logNetworkContent bool) Stats {
-	fluentlogger, err := fluent.New(fluent.Config{
-		FluentHost:    fluentdHost,
-		FluentPort:    24224,
-		MarshalAsJSON: true,
-		Async:         true,
-	})
-
-	if err != nil {
-		log.Panic()
-	}
-
+func newStats(sink Sink, nodeID types.NodeID, sdnHTTPNetworks *sdnmessage.BlockchainNetworks, logNetworkContent bool) Stats {
 	t := FluentdStats{
 		NodeID:            nodeID,
-		FluentD:           fluentlogger,
+		Sink:              sink,
 		Networks:          make(map[types.NetworkNum]sdnmessage.BlockchainNetwork),
 		Lock:              &sync.RWMutex{},
 		logNetworkContent: logNetworkContent,
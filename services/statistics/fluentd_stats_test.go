@@ -19,7 +19,7 @@ func TestShouldLog(t *testing.T) {
 		4: bxmock.MockNetwork(4, "Ethereum", "4", 0.025),
 	}
 
-	stats := newStats("localhost", "node", &networks, false)
+	stats := newStats(MultiSink{}, "node", &networks, false)
 	ft := stats.(FluentdStats)
 	assert.False(t, ft.shouldLogEvent(1, generateRandHash(0xffff)))
 	assert.False(t, ft.shouldLogEvent(1, generateRandHash(0x7d00)))
@@ -0,0 +1,65 @@
+package statistics
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	kafkaContentType    = "application/vnd.kafka.json.v2+json"
+	kafkaPublishTimeout = 5 * time.Second
+)
+
+type kafkaRecord struct {
+	Value json.RawMessage `json:"value"`
+}
+
+type kafkaProduceRequest struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+// KafkaPublisher publishes messages to the topics of a Kafka cluster through its REST proxy
+type KafkaPublisher struct {
+	restURL string
+	client  *http.Client
+}
+
+// NewKafkaPublisher creates a KafkaPublisher for the Kafka REST proxy at restURL, e.g. http://localhost:8082
+func NewKafkaPublisher(restURL string) *KafkaPublisher {
+	return &KafkaPublisher{
+		restURL: strings.TrimSuffix(restURL, "/"),
+		client:  &http.Client{Timeout: kafkaPublishTimeout},
+	}
+}
+
+// Publish produces the message to the topic, the message must be JSON
+func (k *KafkaPublisher) Publish(topic string, message []byte) error {
+	body, err := json.Marshal(kafkaProduceRequest{Records: []kafkaRecord{{Value: message}}})
+	if err != nil {
+		return err
+	}
+
+	resp, err := k.client.Post(fmt.Sprintf("%v/topics/%v", k.restURL, topic), kafkaContentType, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to produce to kafka topic %v: %v", topic, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to produce to kafka topic %v: %v %s", topic, resp.Status, respBody)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// Close closes the idle connections to the REST proxy
+func (k *KafkaPublisher) Close() error {
+	k.client.CloseIdleConnections()
+	return nil
+}
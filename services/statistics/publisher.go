@@ -0,0 +1,108 @@
+package statistics
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+)
+
+// publisherSinkBufferSize is the number of records waiting to be published before new records are dropped
+const publisherSinkBufferSize = 10000
+
+// ErrPublisherSinkFull is returned when a record is dropped because the publisher is not keeping up
+var ErrPublisherSinkFull = errors.New("stats publisher buffer is full, record dropped")
+
+// Publisher publishes messages to a topic of a message broker such as Kafka or NATS
+type Publisher interface {
+	Publish(topic string, message []byte) error
+	Close() error
+}
+
+type publishedRecord struct {
+	topic   string
+	message []byte
+}
+
+// PublisherSink publishes the STATS records as JSON to the topic named after their log name. The records are
+// published in the background so a slow broker does not delay the gateway, records are dropped when the buffer is full
+type PublisherSink struct {
+	publisher Publisher
+	records   chan publishedRecord
+	done      chan struct{}
+}
+
+// NewPublisherSink creates a PublisherSink buffering up to bufferSize records
+func NewPublisherSink(publisher Publisher, bufferSize int) *PublisherSink {
+	p := &PublisherSink{
+		publisher: publisher,
+		records:   make(chan publishedRecord, bufferSize),
+		done:      make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// Post queues the record to be published
+func (p *PublisherSink) Post(logName string, _ time.Time, record LogRecord) error {
+	message, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case p.records <- publishedRecord{topic: logName, message: message}:
+		return nil
+	default:
+		return ErrPublisherSinkFull
+	}
+}
+
+// Close publishes the queued records and closes the publisher
+func (p *PublisherSink) Close() error {
+	close(p.records)
+	<-p.done
+	return p.publisher.Close()
+}
+
+func (p *PublisherSink) run() {
+	defer close(p.done)
+	for record := range p.records {
+		if err := p.publisher.Publish(record.topic, record.message); err != nil {
+			log.Errorf("failed to publish STATS to %v: %v", record.topic, err)
+		}
+	}
+}
+
+// MemoryPublisher keeps the published messages in memory by topic
+type MemoryPublisher struct {
+	lock     sync.Mutex
+	messages map[string][][]byte
+}
+
+// NewMemoryPublisher creates an empty MemoryPublisher
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{messages: make(map[string][][]byte)}
+}
+
+// Publish stores the message
+func (m *MemoryPublisher) Publish(topic string, message []byte) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.messages[topic] = append(m.messages[topic], message)
+	return nil
+}
+
+// Messages returns the messages published to the topic
+func (m *MemoryPublisher) Messages(topic string) [][]byte {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return append([][]byte(nil), m.messages[topic]...)
+}
+
+// Close does nothing
+func (m *MemoryPublisher) Close() error {
+	return nil
+}
@@ -0,0 +1,88 @@
+package statistics
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNATSPublisher(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	lines := make(chan string, 10)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_, _ = conn.Write([]byte("INFO {\"server_id\":\"test\"}\r\n"))
+		reader := bufio.NewReader(conn)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				close(lines)
+				return
+			}
+			lines <- strings.TrimSpace(line)
+		}
+	}()
+
+	publisher := NewNATSPublisher(fmt.Sprintf("nats://user:secret@%v", listener.Addr()))
+	require.NoError(t, publisher.Publish("stats.blocks.events.p", []byte(`{"level":"STATS"}`)))
+	require.NoError(t, publisher.Close())
+
+	assert.Equal(t, `CONNECT {"verbose":false,"pedantic":false,"name":"bloxroute-gateway-stats","user":"user","pass":"secret"}`, <-lines)
+	assert.Equal(t, "PUB stats.blocks.events.p 17", <-lines)
+	assert.Equal(t, `{"level":"STATS"}`, <-lines)
+}
+
+func TestNATSPublisherConnectionFailure(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	publisher := NewNATSPublisher("nats://" + address)
+	assert.Error(t, publisher.Publish("stats", []byte("{}")))
+	assert.Nil(t, publisher.conn)
+}
+
+func TestKafkaPublisher(t *testing.T) {
+	var path, contentType string
+	var request kafkaProduceRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		contentType = r.Header.Get("Content-Type")
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &request)
+		if strings.HasSuffix(path, "missing") {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error_code":40401}`))
+		}
+	}))
+	defer server.Close()
+
+	publisher := NewKafkaPublisher(server.URL + "/")
+	require.NoError(t, publisher.Publish("stats.bundles.events.p", []byte(`{"level":"STATS"}`)))
+	assert.Equal(t, "/topics/stats.bundles.events.p", path)
+	assert.Equal(t, kafkaContentType, contentType)
+	require.Len(t, request.Records, 1)
+	assert.JSONEq(t, `{"level":"STATS"}`, string(request.Records[0].Value))
+
+	err := publisher.Publish("missing", []byte(`{}`))
+	assert.EqualError(t, err, `failed to produce to kafka topic missing: 404 Not Found {"error_code":40401}`)
+	require.NoError(t, publisher.Close())
+}
@@ -0,0 +1,159 @@
+package statistics
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fluent/fluent-logger-golang/fluent"
+)
+
+// Stats backends, the STATS records are sent to every backend listed by --stats-backend
+const (
+	FluentdBackend = "fluentd"
+	FileBackend    = "file"
+	NATSBackend    = "nats"
+	KafkaBackend   = "kafka"
+)
+
+// Backends lists the supported stats backends
+var Backends = []string{FluentdBackend, FileBackend, NATSBackend, KafkaBackend}
+
+// fluentdTag is the fluentd tag of the STATS records
+const fluentdTag = "bx.go.log"
+
+// SinkConfig configures the stats backends
+type SinkConfig struct {
+	Backends       []string
+	FluentDHost    string
+	FilePath       string
+	FileMaxSizeMB  int
+	FileMaxBackups int
+	NATSURL        string
+	KafkaURL       string
+}
+
+// Sink receives the STATS records, logName is the name of the stats the record belongs to
+type Sink interface {
+	Post(logName string, ts time.Time, record LogRecord) error
+	Close() error
+}
+
+// ParseBackends parses a comma separated list of stats backends
+func ParseBackends(backends string) ([]string, error) {
+	var parsed []string
+	for _, backend := range strings.Split(backends, ",") {
+		backend = strings.TrimSpace(backend)
+		if backend == "" {
+			continue
+		}
+		if !isBackend(backend) {
+			return nil, fmt.Errorf("invalid stats backend %v, possible backends are: %v", backend, Backends)
+		}
+		if !containsBackend(parsed, backend) {
+			parsed = append(parsed, backend)
+		}
+	}
+	return parsed, nil
+}
+
+// NewSink creates the sink of the configured backends, several backends are fanned out by a MultiSink
+func NewSink(config SinkConfig) (Sink, error) {
+	var sinks MultiSink
+	for _, backend := range config.Backends {
+		var sink Sink
+		var err error
+		switch backend {
+		case FluentdBackend:
+			sink, err = newFluentdSink(config.FluentDHost)
+		case FileBackend:
+			sink, err = NewFileSink(config.FilePath, config.FileMaxSizeMB, config.FileMaxBackups)
+		case NATSBackend:
+			sink = NewPublisherSink(NewNATSPublisher(config.NATSURL), publisherSinkBufferSize)
+		case KafkaBackend:
+			sink = NewPublisherSink(NewKafkaPublisher(config.KafkaURL), publisherSinkBufferSize)
+		default:
+			err = fmt.Errorf("invalid stats backend %v, possible backends are: %v", backend, Backends)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %v stats backend: %v", backend, err)
+		}
+		sinks = append(sinks, sink)
+	}
+
+	if len(sinks) == 1 {
+		return sinks[0], nil
+	}
+	return sinks, nil
+}
+
+func isBackend(backend string) bool {
+	return containsBackend(Backends, backend)
+}
+
+func containsBackend(backends []string, backend string) bool {
+	for _, b := range backends {
+		if b == backend {
+			return true
+		}
+	}
+	return false
+}
+
+// MultiSink fans the STATS records out to several sinks
+type MultiSink []Sink
+
+// Post sends the record to every sink, a failing sink does not prevent the others from receiving the record
+func (m MultiSink) Post(logName string, ts time.Time, record LogRecord) error {
+	var errs []string
+	for _, sink := range m {
+		if err := sink.Post(logName, ts, record); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%v", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Close closes every sink
+func (m MultiSink) Close() error {
+	var errs []string
+	for _, sink := range m {
+		if err := sink.Close(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%v", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+type fluentdSink struct {
+	fluent *fluent.Fluent
+}
+
+func newFluentdSink(host string) (*fluentdSink, error) {
+	fluentLogger, err := fluent.New(fluent.Config{
+		FluentHost:    host,
+		FluentPort:    24224,
+		MarshalAsJSON: true,
+		Async:         true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &fluentdSink{fluent: fluentLogger}, nil
+}
+
+// Post sends the record to fluentd
+func (f *fluentdSink) Post(_ string, ts time.Time, record LogRecord) error {
+	return f.fluent.EncodeAndPostData(fluentdTag, ts, record)
+}
+
+// Close sends the pending records and closes the fluentd connection
+func (f *fluentdSink) Close() error {
+	return f.fluent.Close()
+}
@@ -0,0 +1,101 @@
+package statistics
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBackends(t *testing.T) {
+	backends, err := ParseBackends("")
+	require.NoError(t, err)
+	assert.Empty(t, backends)
+
+	backends, err = ParseBackends("file, nats,file")
+	require.NoError(t, err)
+	assert.Equal(t, []string{FileBackend, NATSBackend}, backends)
+
+	_, err = ParseBackends("file,syslog")
+	assert.EqualError(t, err, "invalid stats backend syslog, possible backends are: [fluentd file nats kafka]")
+}
+
+func TestNewStatsWithoutBackends(t *testing.T) {
+	stats, err := NewStats(SinkConfig{}, "node", &sdnmessage.BlockchainNetworks{}, false)
+	require.NoError(t, err)
+	assert.Equal(t, NoStats{}, stats)
+
+	_, err = NewStats(SinkConfig{Backends: []string{FileBackend}}, "node", &sdnmessage.BlockchainNetworks{}, false)
+	assert.EqualError(t, err, "failed to create file stats backend: stats file path is not set")
+}
+
+func TestMultiSink(t *testing.T) {
+	publisher := NewMemoryPublisher()
+	publisherSink := NewPublisherSink(publisher, 10)
+	path := filepath.Join(t.TempDir(), "stats.jsonl")
+	fileSink, err := NewFileSink(path, 1, 1)
+	require.NoError(t, err)
+
+	stats := newStats(MultiSink{publisherSink, fileSink}, "node", &sdnmessage.BlockchainNetworks{}, false)
+	stats.LogSubscribeStats("sub", "account", types.NewTxsFeed, sdnmessage.ATierEnterprise, "127.0.0.1", 5, nil, "", "")
+	stats.LogUnsubscribeStats("sub", types.NewTxsFeed, 5, "account", sdnmessage.ATierEnterprise)
+
+	// closing the stats flushes the queued records of every sink
+	require.NoError(t, stats.Close())
+
+	messages := publisher.Messages("stats.subscriptions.events")
+	require.Len(t, messages, 2)
+	var record LogRecord
+	require.NoError(t, json.Unmarshal(messages[0], &record))
+	assert.Equal(t, "STATS", record.Level)
+	assert.Equal(t, types.NodeID("node"), record.Instance)
+	assert.Equal(t, "created", record.Msg.(map[string]interface{})["event"])
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var events []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var line fileRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		assert.Equal(t, "stats.subscriptions.events", line.LogName)
+		events = append(events, line.Msg.(map[string]interface{})["event"].(string))
+	}
+	assert.Equal(t, []string{"created", "closed"}, events)
+}
+
+func TestPublisherSinkFull(t *testing.T) {
+	publisher := &blockingPublisher{release: make(chan struct{})}
+	sink := NewPublisherSink(publisher, 1)
+
+	// the first record is being published, the second one fills the buffer
+	require.NoError(t, sink.Post("stats", time.Now(), LogRecord{}))
+	assert.Eventually(t, func() bool { return len(sink.records) == 0 }, time.Second, time.Millisecond)
+	require.NoError(t, sink.Post("stats", time.Now(), LogRecord{}))
+	assert.Equal(t, ErrPublisherSinkFull, sink.Post("stats", time.Now(), LogRecord{}))
+
+	close(publisher.release)
+	require.NoError(t, sink.Close())
+}
+
+type blockingPublisher struct {
+	release chan struct{}
+}
+
+func (b *blockingPublisher) Publish(string, []byte) error {
+	<-b.release
+	return nil
+}
+
+func (b *blockingPublisher) Close() error {
+	return nil
+}
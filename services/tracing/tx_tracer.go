@@ -0,0 +1,220 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/bloXroute-Labs/gateway/v2/utils/syncmap"
+	"github.com/bloXroute-Labs/gateway/v2/version"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName  = "github.com/bloXroute-Labs/gateway/v2/services/tracing"
+	serviceName = "bloxroute-gateway"
+
+	// txTraceTimeout is how long a transaction waits for a block including it before its trace is ended
+	txTraceTimeout = 10 * time.Minute
+	// expireInterval is how often the traces of transactions that were never included are ended
+	expireInterval = time.Minute
+)
+
+// span attributes of the tx lifecycle
+const (
+	TxHashKey      = attribute.Key("tx.hash")
+	AccountIDKey   = attribute.Key("account.id")
+	SourceKey      = attribute.Key("source")
+	BlockHashKey   = attribute.Key("block.hash")
+	BlockNumberKey = attribute.Key("block.number")
+	PeersKey       = attribute.Key("peers")
+)
+
+var txTracer atomic.Pointer[TxTracer]
+
+type txHashContextKey struct{}
+
+// txIDGenerator derives the trace ID of a transaction from its hash, so the trace is found by the tx hash and
+// the spans of every gateway tracing the same transaction end up in the same trace
+type txIDGenerator struct{}
+
+func (g txIDGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	var traceID trace.TraceID
+	if hash, ok := ctx.Value(txHashContextKey{}).(types.SHA256Hash); ok {
+		copy(traceID[:], hash[:])
+	} else {
+		_, _ = rand.Read(traceID[:])
+	}
+	return traceID, g.NewSpanID(ctx, traceID)
+}
+
+func (g txIDGenerator) NewSpanID(context.Context, trace.TraceID) trace.SpanID {
+	var spanID trace.SpanID
+	_, _ = rand.Read(spanID[:])
+	return spanID
+}
+
+type tracedTx struct {
+	ctx   context.Context
+	span  trace.Span
+	start time.Time
+}
+
+// TxTracer records the lifecycle of the transactions submitted to the gateway, from the submission to the block
+// including them, as a trace per transaction
+type TxTracer struct {
+	tracer trace.Tracer
+	clock  utils.Clock
+	txs    *syncmap.SyncMap[types.SHA256Hash, tracedTx]
+}
+
+// NewTxTracer creates a TxTracer creating its spans with the provider
+func NewTxTracer(provider trace.TracerProvider, clock utils.Clock) *TxTracer {
+	return &TxTracer{
+		tracer: provider.Tracer(tracerName),
+		clock:  clock,
+		txs:    syncmap.NewTypedMapOf[types.SHA256Hash, tracedTx](syncmap.SHA256HashHasher),
+	}
+}
+
+// Init exports the tx lifecycle spans to the OTLP gRPC collector at endpoint until ctx is done. The returned function
+// flushes the pending spans and stops the exporter
+func Init(ctx context.Context, endpoint string, nodeID types.NodeID) (func(context.Context) error, error) {
+	exporter, err := otlptrace.New(ctx, &otlpClient{endpoint: endpoint})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter for %v: %v", endpoint, err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithIDGenerator(txIDGenerator{}),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceName(serviceName),
+			semconv.ServiceInstanceID(string(nodeID)),
+			semconv.ServiceVersion(version.BuildVersion),
+		)),
+	)
+
+	t := NewTxTracer(provider, utils.RealClock{})
+	SetTxTracer(t)
+	go t.expireLoop(ctx)
+
+	log.Infof("exporting transaction traces to %v", endpoint)
+	return provider.Shutdown, nil
+}
+
+// SetTxTracer sets the tracer used by the package functions, nil disables the tracing
+func SetTxTracer(t *TxTracer) {
+	txTracer.Store(t)
+}
+
+// StartTx starts the trace of a transaction submitted at start
+func StartTx(hash types.SHA256Hash, start time.Time, attributes ...attribute.KeyValue) {
+	if t := txTracer.Load(); t != nil {
+		t.StartTx(hash, start, attributes...)
+	}
+}
+
+// StartSpan starts a span in the trace of a transaction, the span is a no-op if the transaction is not traced
+func StartSpan(hash types.SHA256Hash, name string, opts ...trace.SpanStartOption) trace.Span {
+	if t := txTracer.Load(); t != nil {
+		return t.StartSpan(hash, name, opts...)
+	}
+	return trace.SpanFromContext(context.Background())
+}
+
+// Included ends the traces of the transactions of a block
+func Included(block *types.BxBlock, source string) {
+	if t := txTracer.Load(); t != nil {
+		t.Included(block, source)
+	}
+}
+
+// StartTx starts the trace of a transaction submitted at start, a transaction already traced is ignored
+func (t *TxTracer) StartTx(hash types.SHA256Hash, start time.Time, attributes ...attribute.KeyValue) {
+	if t.txs.Has(hash) {
+		return
+	}
+
+	ctx := context.WithValue(context.Background(), txHashContextKey{}, hash)
+	attributes = append(attributes, TxHashKey.String(hash.String()))
+	ctx, span := t.tracer.Start(ctx, "tx", trace.WithTimestamp(start), trace.WithAttributes(attributes...),
+		trace.WithSpanKind(trace.SpanKindServer))
+	if _, loaded := t.txs.LoadOrStore(hash, tracedTx{ctx: ctx, span: span, start: start}); loaded {
+		// traced concurrently, this span is a duplicate
+		span.End()
+	}
+}
+
+// StartSpan starts a span in the trace of a transaction, the span is a no-op if the transaction is not traced
+func (t *TxTracer) StartSpan(hash types.SHA256Hash, name string, opts ...trace.SpanStartOption) trace.Span {
+	tx, ok := t.txs.Load(hash)
+	if !ok {
+		return trace.SpanFromContext(context.Background())
+	}
+	_, span := t.tracer.Start(tx.ctx, name, opts...)
+	return span
+}
+
+// Included ends the traces of the transactions of a block, the root span of a trace lasts from the submission to the inclusion
+func (t *TxTracer) Included(block *types.BxBlock, source string) {
+	if t.txs.Size() == 0 {
+		return
+	}
+
+	now := t.clock.Now()
+	for _, blockTx := range block.Txs {
+		tx, ok := t.txs.LoadAndDelete(blockTx.Hash())
+		if !ok {
+			continue
+		}
+
+		blockAttributes := []attribute.KeyValue{
+			BlockHashKey.String(block.Hash().String()),
+			SourceKey.String(source),
+		}
+		if block.Number != nil {
+			blockAttributes = append(blockAttributes, BlockNumberKey.Int64(block.Number.Int64()))
+		}
+		tx.span.AddEvent("included", trace.WithTimestamp(now))
+		tx.span.SetAttributes(blockAttributes...)
+		tx.span.End(trace.WithTimestamp(now))
+	}
+}
+
+// expire ends the traces of the transactions which were not included in a block within txTraceTimeout
+func (t *TxTracer) expire() {
+	now := t.clock.Now()
+	t.txs.Range(func(hash types.SHA256Hash, tx tracedTx) bool {
+		if now.Sub(tx.start) > txTraceTimeout {
+			t.txs.Delete(hash)
+			tx.span.SetStatus(codes.Error, "transaction was not included in a block")
+			tx.span.End(trace.WithTimestamp(now))
+		}
+		return true
+	})
+}
+
+func (t *TxTracer) expireLoop(ctx context.Context) {
+	ticker := t.clock.Ticker(expireInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.Alert():
+			t.expire()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package tracing
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func testTxTracer(t *testing.T) (*TxTracer, *tracetest.SpanRecorder, *utils.MockClock) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder), sdktrace.WithIDGenerator(txIDGenerator{}))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+	clock := utils.NewMockClock()
+	clock.SetTime(time.Now())
+	return NewTxTracer(provider, clock), recorder, clock
+}
+
+func TestTxTracerLifecycle(t *testing.T) {
+	tracer, recorder, clock := testTxTracer(t)
+	hash := types.SHA256Hash{1, 2, 3}
+
+	// transactions which weren't submitted to the gateway are not traced
+	span := tracer.StartSpan(hash, "broadcast")
+	assert.False(t, span.SpanContext().IsValid())
+	span.End()
+
+	tracer.StartTx(hash, clock.Now(), AccountIDKey.String("account"))
+	tracer.StartSpan(hash, "validate").End()
+	tracer.StartSpan(hash, "broadcast").End()
+	require.Len(t, recorder.Ended(), 2)
+
+	clock.IncTime(12 * time.Second)
+	block := types.NewRawBxBlock(types.SHA256Hash{9}, types.SHA256Hash{}, types.BxBlockTypeEth, nil,
+		[]*types.BxBlockTransaction{types.NewBxBlockTransaction(types.SHA256Hash{4}, nil), types.NewBxBlockTransaction(hash, nil)},
+		nil, nil, big.NewInt(100), 0)
+	tracer.Included(block, "node")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	root := spans[2]
+	assert.Equal(t, "tx", root.Name())
+	assert.Equal(t, 12*time.Second, root.EndTime().Sub(root.StartTime()))
+	assert.Contains(t, root.Attributes(), BlockNumberKey.Int64(100))
+	assert.Contains(t, root.Attributes(), TxHashKey.String(hash.String()))
+
+	// the trace is keyed by the tx hash, all the spans belong to it
+	var traceID trace.TraceID
+	copy(traceID[:], hash[:])
+	for _, s := range spans {
+		assert.Equal(t, traceID, s.SpanContext().TraceID())
+	}
+	assert.Equal(t, root.SpanContext().SpanID(), spans[0].Parent().SpanID())
+
+	// the trace is over
+	assert.False(t, tracer.StartSpan(hash, "broadcast").SpanContext().IsValid())
+}
+
+func TestTxTracerExpire(t *testing.T) {
+	tracer, recorder, clock := testTxTracer(t)
+
+	tracer.StartTx(types.SHA256Hash{1}, clock.Now())
+	clock.IncTime(txTraceTimeout / 2)
+	tracer.StartTx(types.SHA256Hash{2}, clock.Now())
+	clock.IncTime(txTraceTimeout/2 + time.Second)
+
+	tracer.expire()
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, 1, tracer.txs.Size())
+}
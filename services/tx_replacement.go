@@ -0,0 +1,272 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// maxTxReplacementRungs bounds the transactions of a replacement ladder
+	maxTxReplacementRungs = 32
+	// txReplacementWatchBlocks is how many blocks a ladder is watched for inclusion after its last rung is due for replacement
+	txReplacementWatchBlocks = 64
+)
+
+// ErrTxReplacementExists is returned when a ladder with the same ID is already active
+var ErrTxReplacementExists = errors.New("transaction replacement already exists")
+
+// TxReplacementRung is a signed transaction of a replacement ladder
+type TxReplacementRung struct {
+	Hash        types.SHA256Hash
+	Transaction string
+}
+
+// TxReplacementLadder is a series of transactions with the same sender and nonce and increasing fees. The first rung is
+// released when the ladder is added, each next rung is released if none of the released rungs is included in a block
+// within the number of blocks of the schedule
+type TxReplacementLadder struct {
+	ID string
+	// AccountID is the account which submitted the ladder, the status of its rungs is reported to it only
+	AccountID types.AccountID
+	Rungs     []TxReplacementRung
+	// Schedule is the number of blocks rung i waits for inclusion before rung i+1 is released, the last value applies to the following rungs
+	Schedule []uint64
+	// Release sends a rung through the transaction processing of the gateway
+	Release func(rung TxReplacementRung) error
+
+	released      int
+	releasedBlock uint64
+	// releasing is set while a rung is being released, the ladder moves to it only once it is released
+	releasing bool
+}
+
+// TxReplacementStatus is the progress of a replacement ladder, TxHash is the last released rung
+type TxReplacementStatus struct {
+	ID       string
+	Released int
+	Rungs    int
+	TxHash   types.SHA256Hash
+}
+
+// TxReplacementManager releases the rungs of the replacement ladders on schedule until one of them is included in a block
+// of the canonical chain. A rung which fails to be released is released again at the next block
+type TxReplacementManager struct {
+	lock        sync.Mutex
+	ladders     map[string]*TxReplacementLadder
+	hashes      map[types.SHA256Hash]string
+	blockNumber uint64
+	notify      func(notification types.Notification)
+}
+
+// NewTxReplacementManager creates a TxReplacementManager reporting the status of the rungs with notify
+func NewTxReplacementManager(notify func(notification types.Notification)) *TxReplacementManager {
+	return &TxReplacementManager{
+		ladders: make(map[string]*TxReplacementLadder),
+		hashes:  make(map[types.SHA256Hash]string),
+		notify:  notify,
+	}
+}
+
+// Add validates the ladder and releases its first rung
+func (m *TxReplacementManager) Add(ladder *TxReplacementLadder) error {
+	if len(ladder.Rungs) < 2 || len(ladder.Rungs) > maxTxReplacementRungs {
+		return fmt.Errorf("replacement requires between 2 and %v transactions, got %v", maxTxReplacementRungs, len(ladder.Rungs))
+	}
+	if len(ladder.Schedule) == 0 {
+		return errors.New("replacement schedule is empty")
+	}
+	for _, blocks := range ladder.Schedule {
+		if blocks == 0 {
+			return errors.New("replacement schedule must wait at least 1 block per transaction")
+		}
+	}
+
+	m.lock.Lock()
+	if _, ok := m.ladders[ladder.ID]; ok {
+		m.lock.Unlock()
+		return ErrTxReplacementExists
+	}
+	for _, rung := range ladder.Rungs {
+		if _, ok := m.hashes[rung.Hash]; ok {
+			m.lock.Unlock()
+			return fmt.Errorf("transaction %v is already part of a replacement", rung.Hash)
+		}
+	}
+	m.ladders[ladder.ID] = ladder
+	for _, rung := range ladder.Rungs {
+		m.hashes[rung.Hash] = ladder.ID
+	}
+	ladder.releasing = true
+	m.lock.Unlock()
+
+	if err := ladder.Release(ladder.Rungs[0]); err != nil {
+		m.remove(ladder)
+		return err
+	}
+	m.released(ladder, 0)
+	return nil
+}
+
+// OnBlock stops the ladders with a rung in the canonical block, and releases the next rung of the ladders which are
+// due. The included rung is mined and the other released rungs are replaced. Blocks at a height already seen only
+// stop the ladders
+func (m *TxReplacementManager) OnBlock(block *ethtypes.Block) {
+	m.lock.Lock()
+	if len(m.ladders) == 0 {
+		if block.NumberU64() > m.blockNumber {
+			m.blockNumber = block.NumberU64()
+		}
+		m.lock.Unlock()
+		return
+	}
+
+	type statusUpdate struct {
+		ladder *TxReplacementLadder
+		hash   types.SHA256Hash
+		status types.Status
+	}
+	var updates []statusUpdate
+	for _, tx := range block.Transactions() {
+		hash := types.SHA256Hash(tx.Hash())
+		id, ok := m.hashes[hash]
+		if !ok {
+			continue
+		}
+		ladder := m.ladders[id]
+		updates = append(updates, statusUpdate{ladder: ladder, hash: hash, status: types.Mined})
+		for i := 0; i < ladder.released; i++ {
+			if ladder.Rungs[i].Hash != hash {
+				updates = append(updates, statusUpdate{ladder: ladder, hash: ladder.Rungs[i].Hash, status: types.Replaced})
+			}
+		}
+		m.removeLocked(ladder)
+		log.Infof("transaction replacement %v stopped, rung %v of %v included in block %v", id, hash, len(ladder.Rungs), block.Hash())
+	}
+
+	var due []dueRung
+	if block.NumberU64() > m.blockNumber {
+		m.blockNumber = block.NumberU64()
+		for _, ladder := range m.ladders {
+			if ladder.releasing {
+				continue
+			}
+			if ladder.releasedBlock == 0 {
+				// released before the gateway saw a block, the schedule starts now
+				ladder.releasedBlock = m.blockNumber
+				continue
+			}
+			waited := m.blockNumber - ladder.releasedBlock
+			if waited < ladder.wait(ladder.released-1) {
+				continue
+			}
+			if ladder.released < len(ladder.Rungs) {
+				ladder.releasing = true
+				due = append(due, dueRung{ladder: ladder, index: ladder.released})
+			} else if waited >= ladder.wait(ladder.released-1)+txReplacementWatchBlocks {
+				m.removeLocked(ladder)
+				log.Infof("transaction replacement %v expired, none of its %v transactions was included", ladder.ID, len(ladder.Rungs))
+			}
+		}
+	}
+	m.lock.Unlock()
+
+	for _, update := range updates {
+		m.notifyStatus(update.ladder, update.hash, update.status)
+	}
+	if len(due) > 0 {
+		// the rungs go through the transaction processing, which should not hold the block processing
+		go m.release(due)
+	}
+}
+
+// dueRung is a rung of a ladder to release
+type dueRung struct {
+	ladder *TxReplacementLadder
+	index  int
+}
+
+func (m *TxReplacementManager) release(due []dueRung) {
+	for _, d := range due {
+		rung := d.ladder.Rungs[d.index]
+		if err := d.ladder.Release(rung); err != nil {
+			log.Errorf("failed to release transaction %v of replacement %v, retrying at the next block: %v", rung.Hash, d.ladder.ID, err)
+			m.lock.Lock()
+			d.ladder.releasing = false
+			m.lock.Unlock()
+			continue
+		}
+		m.released(d.ladder, d.index)
+	}
+}
+
+// released moves the ladder to its rung i once it is released, the rungs it replaces are reported as replaced only
+// when another rung is included
+func (m *TxReplacementManager) released(ladder *TxReplacementLadder, i int) {
+	m.lock.Lock()
+	ladder.releasing = false
+	ladder.released = i + 1
+	ladder.releasedBlock = m.blockNumber
+	_, active := m.ladders[ladder.ID]
+	m.lock.Unlock()
+
+	if active {
+		m.notifyStatus(ladder, ladder.Rungs[i].Hash, types.TxPool)
+	}
+}
+
+// Status returns the progress of the ladder submitted by the account, false if the account has no such active ladder
+func (m *TxReplacementManager) Status(id string, accountID types.AccountID) (TxReplacementStatus, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	ladder, ok := m.ladders[id]
+	if !ok || ladder.AccountID != accountID {
+		return TxReplacementStatus{}, false
+	}
+	status := TxReplacementStatus{ID: id, Released: ladder.released, Rungs: len(ladder.Rungs)}
+	if ladder.released > 0 {
+		status.TxHash = ladder.Rungs[ladder.released-1].Hash
+	}
+	return status, true
+}
+
+// Size returns the number of active ladders
+func (m *TxReplacementManager) Size() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return len(m.ladders)
+}
+
+func (m *TxReplacementManager) remove(ladder *TxReplacementLadder) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.removeLocked(ladder)
+}
+
+func (m *TxReplacementManager) removeLocked(ladder *TxReplacementLadder) {
+	delete(m.ladders, ladder.ID)
+	for _, rung := range ladder.Rungs {
+		delete(m.hashes, rung.Hash)
+	}
+}
+
+func (m *TxReplacementManager) notifyStatus(ladder *TxReplacementLadder, hash types.SHA256Hash, status types.Status) {
+	m.notify(&types.TransactionStatusNotification{
+		TransactionHash:      hash.Format(true),
+		Status:               status,
+		NotificationAccounts: types.NotificationAccounts{AccountIDs: []types.AccountID{ladder.AccountID}},
+	})
+}
+
+// wait returns the number of blocks rung i waits for inclusion
+func (l *TxReplacementLadder) wait(i int) uint64 {
+	if i < len(l.Schedule) {
+		return l.Schedule[i]
+	}
+	return l.Schedule[len(l.Schedule)-1]
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testTxReplacementNotifications struct {
+	lock     sync.Mutex
+	statuses map[string]types.Status
+	accounts map[string][]types.AccountID
+}
+
+func (n *testTxReplacementNotifications) notify(notification types.Notification) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	status := notification.(*types.TransactionStatusNotification)
+	n.statuses[status.TransactionHash] = status.Status
+	if n.accounts != nil {
+		n.accounts[status.TransactionHash] = status.AccountIDs
+	}
+}
+
+func (n *testTxReplacementNotifications) status(hash types.SHA256Hash) types.Status {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return n.statuses[hash.Format(true)]
+}
+
+type testTxReplacementReleases struct {
+	lock     sync.Mutex
+	released []types.SHA256Hash
+	failures int
+}
+
+func (r *testTxReplacementReleases) release(rung TxReplacementRung) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.failures > 0 {
+		r.failures--
+		return errors.New("failed to send")
+	}
+	r.released = append(r.released, rung.Hash)
+	return nil
+}
+
+func (r *testTxReplacementReleases) count() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return len(r.released)
+}
+
+var testReplacementTxs = []*ethtypes.Transaction{
+	ethtypes.NewTx(&ethtypes.LegacyTx{GasPrice: big.NewInt(1)}),
+	ethtypes.NewTx(&ethtypes.LegacyTx{GasPrice: big.NewInt(2)}),
+	ethtypes.NewTx(&ethtypes.LegacyTx{GasPrice: big.NewInt(3)}),
+	ethtypes.NewTx(&ethtypes.LegacyTx{Nonce: 1}),
+}
+
+func testRungHash(i int) types.SHA256Hash {
+	return types.SHA256Hash(testReplacementTxs[i].Hash())
+}
+
+func newTestLadder(releases *testTxReplacementReleases, schedule ...uint64) *TxReplacementLadder {
+	return &TxReplacementLadder{
+		ID:        "ladder",
+		AccountID: "a",
+		Rungs:     []TxReplacementRung{{Hash: testRungHash(0)}, {Hash: testRungHash(1)}, {Hash: testRungHash(2)}},
+		Schedule:  schedule,
+		Release:   releases.release,
+	}
+}
+
+func testReplacementBlock(number int64, txs ...*ethtypes.Transaction) *ethtypes.Block {
+	return ethtypes.NewBlockWithHeader(&ethtypes.Header{Number: big.NewInt(number)}).WithBody(txs, nil)
+}
+
+func TestTxReplacementManager_Schedule(t *testing.T) {
+	notifications := &testTxReplacementNotifications{statuses: make(map[string]types.Status), accounts: make(map[string][]types.AccountID)}
+	releases := &testTxReplacementReleases{}
+	manager := NewTxReplacementManager(notifications.notify)
+	manager.OnBlock(testReplacementBlock(100))
+
+	require.NoError(t, manager.Add(newTestLadder(releases, 2, 1)))
+	assert.Equal(t, 1, releases.count())
+	assert.Equal(t, types.TxPool, notifications.status(testRungHash(0)))
+	assert.ErrorIs(t, manager.Add(newTestLadder(releases, 1)), ErrTxReplacementExists)
+
+	// the first rung waits 2 blocks, a block seen again does not count
+	manager.OnBlock(testReplacementBlock(101))
+	manager.OnBlock(testReplacementBlock(101))
+	assert.Equal(t, 1, releases.count())
+
+	// the replaced rung is only reported once another rung is included
+	manager.OnBlock(testReplacementBlock(102))
+	assert.Eventually(t, func() bool { return notifications.status(testRungHash(1)) == types.TxPool }, time.Second, time.Millisecond)
+	assert.Equal(t, 2, releases.count())
+	assert.Equal(t, types.TxPool, notifications.status(testRungHash(0)))
+
+	status, ok := manager.Status("ladder", "a")
+	require.True(t, ok)
+	assert.Equal(t, 2, status.Released)
+	assert.Equal(t, testRungHash(1), status.TxHash)
+	// the ladder is reported to the submitting account only
+	_, ok = manager.Status("ladder", "b")
+	assert.False(t, ok)
+
+	// the first rung is included, the ladder stops
+	manager.OnBlock(testReplacementBlock(103, testReplacementTxs[3], testReplacementTxs[0]))
+	assert.Equal(t, types.Mined, notifications.status(testRungHash(0)))
+	assert.Equal(t, types.Replaced, notifications.status(testRungHash(1)))
+	assert.Equal(t, []types.AccountID{"a"}, notifications.accounts[testRungHash(1).Format(true)])
+	assert.Equal(t, 0, manager.Size())
+
+	manager.OnBlock(testReplacementBlock(110))
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, 2, releases.count())
+}
+
+func TestTxReplacementManager_Retry(t *testing.T) {
+	releases := &testTxReplacementReleases{}
+	manager := NewTxReplacementManager(func(types.Notification) {})
+	manager.OnBlock(testReplacementBlock(1))
+	require.NoError(t, manager.Add(newTestLadder(releases, 1)))
+
+	// the second rung fails to be released, the ladder stays on the first rung and releases it at the next block
+	releases.lock.Lock()
+	releases.failures = 1
+	releases.lock.Unlock()
+	manager.OnBlock(testReplacementBlock(2))
+	assert.Eventually(t, func() bool {
+		releases.lock.Lock()
+		defer releases.lock.Unlock()
+		return releases.failures == 0
+	}, time.Second, time.Millisecond)
+	assert.Eventually(t, func() bool {
+		manager.lock.Lock()
+		defer manager.lock.Unlock()
+		return !manager.ladders["ladder"].releasing
+	}, time.Second, time.Millisecond)
+	status, _ := manager.Status("ladder", "a")
+	assert.Equal(t, 1, status.Released)
+
+	manager.OnBlock(testReplacementBlock(3))
+	assert.Eventually(t, func() bool { return releases.count() == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, testRungHash(1), releases.released[1])
+}
+
+func TestTxReplacementManager_Expiry(t *testing.T) {
+	releases := &testTxReplacementReleases{}
+	manager := NewTxReplacementManager(func(types.Notification) {})
+	manager.OnBlock(testReplacementBlock(1))
+	require.NoError(t, manager.Add(newTestLadder(releases, 1)))
+
+	manager.OnBlock(testReplacementBlock(2))
+	assert.Eventually(t, func() bool { return releases.count() == 2 }, time.Second, time.Millisecond)
+	manager.OnBlock(testReplacementBlock(3))
+	assert.Eventually(t, func() bool { return releases.count() == 3 }, time.Second, time.Millisecond)
+
+	manager.OnBlock(testReplacementBlock(3 + txReplacementWatchBlocks))
+	assert.Equal(t, 1, manager.Size())
+	manager.OnBlock(testReplacementBlock(4 + txReplacementWatchBlocks))
+	assert.Equal(t, 0, manager.Size())
+}
+
+func TestTxReplacementManager_Add(t *testing.T) {
+	manager := NewTxReplacementManager(func(types.Notification) {})
+	releases := &testTxReplacementReleases{}
+
+	assert.Error(t, manager.Add(newTestLadder(releases)))
+	assert.Error(t, manager.Add(newTestLadder(releases, 1, 0)))
+
+	ladder := newTestLadder(releases, 1)
+	ladder.Rungs = ladder.Rungs[:1]
+	assert.Error(t, manager.Add(ladder))
+
+	ladder = newTestLadder(releases, 1)
+	ladder.Release = func(TxReplacementRung) error { return errors.New("invalid transaction") }
+	assert.Error(t, manager.Add(ladder))
+	assert.Equal(t, 0, manager.Size())
+	assert.Equal(t, 0, releases.count())
+}
@@ -0,0 +1,368 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// maxMonitoredTxs bounds the transactions monitored by the gateway for an account
+	maxMonitoredTxs = 1000
+	// monitoredTxDropTimeout is how long a monitored transaction waits for a block including it before it is reported dropped
+	monitoredTxDropTimeout = time.Hour
+)
+
+// ErrTooManyMonitoredTxs is returned when the gateway would monitor more than maxMonitoredTxs transactions for an account
+var ErrTooManyMonitoredTxs = fmt.Errorf("too many monitored transactions, the gateway monitors up to %v transactions for an account", maxMonitoredTxs)
+
+// TxStatusUpdate is a transaction processed by the gateway, reported to the TxStatusTracker
+type TxStatusUpdate struct {
+	Hash types.SHA256Hash
+	// Sender is empty if the gateway did not extract the sender of the transaction
+	Sender types.Sender
+	Nonce  uint64
+	// FromNode is set if the transaction was received from a blockchain node, i.e. it is in the mempool of the node
+	FromNode   bool
+	SentToBDN  bool
+	SentToNode bool
+}
+
+// TxStatus is the current status of a monitored transaction
+type TxStatus struct {
+	Hash        types.SHA256Hash
+	Status      types.Status
+	BlockNumber uint64
+	ReplacedBy  types.SHA256Hash
+}
+
+type monitoredTx struct {
+	status       types.Status
+	accounts     map[types.AccountID]struct{}
+	from         common.Address
+	hasFrom      bool
+	nonce        uint64
+	received     bool
+	sentToBDN    bool
+	sentToNode   bool
+	seenFromNode bool
+	replacedBy   types.SHA256Hash
+	blockNumber  uint64
+	addedTime    time.Time
+}
+
+// TxStatusTracker follows the monitored transactions through the processing of the gateway and the blocks of the
+// canonical chain, and notifies the accounts monitoring them of each change of their status: received, sent to the
+// BDN, sent to the node, seen in the mempool of the node, included in a block, replaced by a transaction with the same
+// sender and nonce, or dropped
+type TxStatusTracker struct {
+	clock      utils.Clock
+	lock       sync.RWMutex
+	txs        map[types.SHA256Hash]*monitoredTx
+	accountTxs map[types.AccountID]int
+	// nonces indexes the monitored transactions whose sender is known by sender and nonce
+	nonces map[common.Address]map[uint64]types.SHA256Hash
+	lookup func(hash types.SHA256Hash) (TxStatusUpdate, bool)
+	notify func(notification types.Notification)
+}
+
+// NewTxStatusTracker creates a TxStatusTracker reporting the status of the monitored transactions with notify. The
+// lookup describes the transactions already processed by the gateway when their monitoring starts
+func NewTxStatusTracker(clock utils.Clock, lookup func(hash types.SHA256Hash) (TxStatusUpdate, bool), notify func(notification types.Notification)) *TxStatusTracker {
+	return &TxStatusTracker{
+		clock:      clock,
+		txs:        make(map[types.SHA256Hash]*monitoredTx),
+		accountTxs: make(map[types.AccountID]int),
+		nonces:     make(map[common.Address]map[uint64]types.SHA256Hash),
+		lookup:     lookup,
+		notify:     notify,
+	}
+}
+
+// Monitor starts following the transactions for the account, and returns their current status. None of them is
+// monitored if the account would monitor more than maxMonitoredTxs transactions
+func (t *TxStatusTracker) Monitor(hashes []types.SHA256Hash, accountID types.AccountID) ([]TxStatus, error) {
+	known := make(map[types.SHA256Hash]TxStatusUpdate)
+	for _, hash := range hashes {
+		if update, ok := t.lookup(hash); ok {
+			known[hash] = update
+		}
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	added := make(map[types.SHA256Hash]struct{}, len(hashes))
+	for _, hash := range hashes {
+		if tx, ok := t.txs[hash]; ok {
+			if _, ok = tx.accounts[accountID]; ok {
+				continue
+			}
+		}
+		added[hash] = struct{}{}
+	}
+	if t.accountTxs[accountID]+len(added) > maxMonitoredTxs {
+		return nil, ErrTooManyMonitoredTxs
+	}
+
+	statuses := make([]TxStatus, 0, len(hashes))
+	for _, hash := range hashes {
+		tx, ok := t.txs[hash]
+		if !ok {
+			tx = &monitoredTx{
+				status:    types.Unknown,
+				accounts:  make(map[types.AccountID]struct{}),
+				addedTime: t.clock.Now(),
+			}
+			t.txs[hash] = tx
+		}
+		if _, ok = tx.accounts[accountID]; !ok {
+			tx.accounts[accountID] = struct{}{}
+			t.accountTxs[accountID]++
+		}
+		if update, isKnown := known[hash]; isKnown && !tx.received {
+			update.Hash = hash
+			t.update(tx, &update)
+		}
+		statuses = append(statuses, tx.txStatus(hash))
+	}
+	return statuses, nil
+}
+
+// Unmonitor stops following the transaction for the account, false if the account did not monitor it
+func (t *TxStatusTracker) Unmonitor(hash types.SHA256Hash, accountID types.AccountID) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	tx, ok := t.txs[hash]
+	if !ok {
+		return false
+	}
+	if _, ok = tx.accounts[accountID]; !ok {
+		return false
+	}
+	delete(tx.accounts, accountID)
+	t.removeAccountTx(accountID)
+	if len(tx.accounts) == 0 {
+		t.remove(hash, tx)
+	}
+	return true
+}
+
+// Status returns the current status of the transaction, false if it is not monitored
+func (t *TxStatusTracker) Status(hash types.SHA256Hash) (TxStatus, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	tx, ok := t.txs[hash]
+	if !ok {
+		return TxStatus{}, false
+	}
+	return tx.txStatus(hash), true
+}
+
+// Size returns the number of monitored transactions
+func (t *TxStatusTracker) Size() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return len(t.txs)
+}
+
+// OnTransaction updates the status of the monitored transaction, or reports the monitored transaction with the same
+// sender and nonce replaced by it
+func (t *TxStatusTracker) OnTransaction(update TxStatusUpdate) {
+	// most transactions are not monitored, the write lock is taken only for the monitored ones and their replacements
+	t.lock.RLock()
+	_, monitored := t.txs[update.Hash]
+	_, replacing := t.senderNonce(common.Address(update.Sender), update.Nonce)
+	t.lock.RUnlock()
+	if !monitored && (!replacing || update.Sender == types.EmptySender) {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if tx, ok := t.txs[update.Hash]; ok {
+		t.update(tx, &update)
+		return
+	}
+	if update.Sender == types.EmptySender {
+		return
+	}
+	replaced, ok := t.senderNonce(common.Address(update.Sender), update.Nonce)
+	if !ok {
+		return
+	}
+	tx := t.txs[replaced]
+	if tx.replacedBy == update.Hash {
+		return
+	}
+	// the replacement may be rejected by the nodes, the transaction is followed until a block settles its nonce
+	tx.replacedBy = update.Hash
+	tx.status = types.Replaced
+	t.notifyStatus(replaced, tx)
+}
+
+// OnBlock reports the monitored transactions included in the canonical block, or whose nonce is used by another
+// transaction of the block, and drops the transactions not included within monitoredTxDropTimeout
+func (t *TxStatusTracker) OnBlock(block *ethtypes.Block) {
+	t.lock.RLock()
+	empty := len(t.txs) == 0
+	hasNonces := len(t.nonces) > 0
+	t.lock.RUnlock()
+	if empty {
+		return
+	}
+
+	// the senders are recovered before locking, the processing of the transactions does not wait for it
+	var senders []common.Address
+	if hasNonces {
+		senders = make([]common.Address, len(block.Transactions()))
+		for i, blockTx := range block.Transactions() {
+			if from, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(blockTx.ChainId()), blockTx); err == nil {
+				senders[i] = from
+			}
+		}
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for _, blockTx := range block.Transactions() {
+		if tx, ok := t.txs[types.SHA256Hash(blockTx.Hash())]; ok {
+			tx.status = types.Mined
+			tx.blockNumber = block.NumberU64()
+			t.notifyStatus(types.SHA256Hash(blockTx.Hash()), tx)
+			t.remove(types.SHA256Hash(blockTx.Hash()), tx)
+		}
+	}
+	for i, from := range senders {
+		if _, ok := t.nonces[from]; ok {
+			blockTx := block.Transactions()[i]
+			t.onSenderNonce(from, blockTx.Nonce(), types.SHA256Hash(blockTx.Hash()), block.NumberU64())
+		}
+	}
+
+	now := t.clock.Now()
+	for hash, tx := range t.txs {
+		if now.Sub(tx.addedTime) > monitoredTxDropTimeout {
+			tx.status = types.Dropped
+			t.notifyStatus(hash, tx)
+			t.remove(hash, tx)
+		}
+	}
+}
+
+// onSenderNonce settles the monitored transactions of the sender whose nonce is used by a transaction of a block
+func (t *TxStatusTracker) onSenderNonce(from common.Address, nonce uint64, blockTxHash types.SHA256Hash, blockNumber uint64) {
+	for txNonce, hash := range t.nonces[from] {
+		if txNonce > nonce {
+			continue
+		}
+		tx := t.txs[hash]
+		tx.blockNumber = blockNumber
+		if txNonce == nonce {
+			tx.status = types.Replaced
+			tx.replacedBy = blockTxHash
+		} else {
+			// the nonce was used by a transaction the gateway did not see
+			tx.status = types.Dropped
+		}
+		t.notifyStatus(hash, tx)
+		t.remove(hash, tx)
+	}
+}
+
+func (t *TxStatusTracker) senderNonce(from common.Address, nonce uint64) (types.SHA256Hash, bool) {
+	hash, ok := t.nonces[from][nonce]
+	return hash, ok
+}
+
+func (t *TxStatusTracker) update(tx *monitoredTx, update *TxStatusUpdate) {
+	if !tx.hasFrom && update.Sender != types.EmptySender {
+		tx.from = common.Address(update.Sender)
+		tx.nonce = update.Nonce
+		tx.hasFrom = true
+		if _, ok := t.nonces[tx.from]; !ok {
+			t.nonces[tx.from] = make(map[uint64]types.SHA256Hash)
+		}
+		t.nonces[tx.from][tx.nonce] = update.Hash
+	}
+	if !tx.received {
+		tx.received = true
+		t.setStatus(update.Hash, tx, types.Received)
+	}
+	if update.SentToBDN && !tx.sentToBDN {
+		tx.sentToBDN = true
+		t.setStatus(update.Hash, tx, types.SentToBDN)
+	}
+	if update.SentToNode && !tx.sentToNode {
+		tx.sentToNode = true
+		t.setStatus(update.Hash, tx, types.SentToNode)
+	}
+	if update.FromNode && !tx.seenFromNode {
+		tx.seenFromNode = true
+		t.setStatus(update.Hash, tx, types.SeenInMempool)
+	}
+}
+
+func (t *TxStatusTracker) setStatus(hash types.SHA256Hash, tx *monitoredTx, status types.Status) {
+	tx.status = status
+	t.notifyStatus(hash, tx)
+}
+
+func (t *TxStatusTracker) remove(hash types.SHA256Hash, tx *monitoredTx) {
+	delete(t.txs, hash)
+	for accountID := range tx.accounts {
+		t.removeAccountTx(accountID)
+	}
+	if !tx.hasFrom {
+		return
+	}
+	if t.nonces[tx.from][tx.nonce] == hash {
+		delete(t.nonces[tx.from], tx.nonce)
+		if len(t.nonces[tx.from]) == 0 {
+			delete(t.nonces, tx.from)
+		}
+	}
+}
+
+func (t *TxStatusTracker) removeAccountTx(accountID types.AccountID) {
+	if t.accountTxs[accountID] <= 1 {
+		delete(t.accountTxs, accountID)
+	} else {
+		t.accountTxs[accountID]--
+	}
+}
+
+// notifyStatus reports the status of the transaction to the accounts monitoring it only
+func (t *TxStatusTracker) notifyStatus(hash types.SHA256Hash, tx *monitoredTx) {
+	notification := tx.txStatus(hash).notification()
+	notification.AccountIDs = make([]types.AccountID, 0, len(tx.accounts))
+	for accountID := range tx.accounts {
+		notification.AccountIDs = append(notification.AccountIDs, accountID)
+	}
+	t.notify(notification)
+}
+
+func (tx *monitoredTx) txStatus(hash types.SHA256Hash) TxStatus {
+	return TxStatus{Hash: hash, Status: tx.status, BlockNumber: tx.blockNumber, ReplacedBy: tx.replacedBy}
+}
+
+func (s TxStatus) notification() *types.TransactionStatusNotification {
+	notification := &types.TransactionStatusNotification{
+		TransactionHash: s.Hash.Format(true),
+		Status:          s.Status,
+		BlockNumber:     s.BlockNumber,
+	}
+	if s.ReplacedBy != (types.SHA256Hash{}) {
+		notification.ReplacedBy = s.ReplacedBy.Format(true)
+	}
+	return notification
+}
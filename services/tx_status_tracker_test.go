@@ -0,0 +1,191 @@
+package services
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/test/bxmock"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testTxStatusNotifications struct {
+	lock          sync.Mutex
+	notifications []*types.TransactionStatusNotification
+}
+
+func (n *testTxStatusNotifications) notify(notification types.Notification) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.notifications = append(n.notifications, notification.(*types.TransactionStatusNotification))
+}
+
+func (n *testTxStatusNotifications) statuses(hash types.SHA256Hash) []types.Status {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	var statuses []types.Status
+	for _, notification := range n.notifications {
+		if notification.TransactionHash == hash.Format(true) {
+			statuses = append(statuses, notification.Status)
+		}
+	}
+	return statuses
+}
+
+func (n *testTxStatusNotifications) last() *types.TransactionStatusNotification {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return n.notifications[len(n.notifications)-1]
+}
+
+func newTestTxStatusTracker(known map[types.SHA256Hash]TxStatusUpdate) (*TxStatusTracker, *utils.MockClock, *testTxStatusNotifications) {
+	clock := &utils.MockClock{}
+	clock.SetTime(time.Now())
+	notifications := &testTxStatusNotifications{}
+	lookup := func(hash types.SHA256Hash) (TxStatusUpdate, bool) {
+		update, ok := known[hash]
+		return update, ok
+	}
+	return NewTxStatusTracker(clock, lookup, notifications.notify), clock, notifications
+}
+
+func testTxStatusUpdate(t *testing.T, tx *ethtypes.Transaction) TxStatusUpdate {
+	from, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(tx.ChainId()), tx)
+	require.NoError(t, err)
+	return TxStatusUpdate{Hash: types.SHA256Hash(tx.Hash()), Sender: types.Sender(from), Nonce: tx.Nonce()}
+}
+
+func testTxStatusBlock(number int64, txs ...*ethtypes.Transaction) *ethtypes.Block {
+	return ethtypes.NewBlockWithHeader(&ethtypes.Header{Number: big.NewInt(number)}).WithBody(txs, nil)
+}
+
+func TestTxStatusTracker_Included(t *testing.T) {
+	tracker, _, notifications := newTestTxStatusTracker(nil)
+	tx := bxmock.NewSignedEthTx(ethtypes.DynamicFeeTxType, 1, privateKey, big.NewInt(1))
+	hash := types.SHA256Hash(tx.Hash())
+
+	statuses, err := tracker.Monitor([]types.SHA256Hash{hash}, "account")
+	require.NoError(t, err)
+	assert.Equal(t, types.Unknown, statuses[0].Status)
+
+	update := testTxStatusUpdate(t, tx)
+	update.SentToBDN = true
+	update.SentToNode = true
+	tracker.OnTransaction(update)
+	// the node announces the transaction back, the gateway already sent it everywhere
+	update.FromNode = true
+	tracker.OnTransaction(update)
+	tracker.OnTransaction(update)
+
+	tracker.OnBlock(testTxStatusBlock(100, tx))
+	assert.Equal(t, []types.Status{types.Received, types.SentToBDN, types.SentToNode, types.SeenInMempool, types.Mined}, notifications.statuses(hash))
+	assert.Equal(t, uint64(100), notifications.last().BlockNumber)
+	assert.Equal(t, 0, tracker.Size())
+}
+
+func TestTxStatusTracker_Known(t *testing.T) {
+	tx := bxmock.NewSignedEthTx(ethtypes.DynamicFeeTxType, 1, privateKey, big.NewInt(1))
+	hash := types.SHA256Hash(tx.Hash())
+	tracker, _, notifications := newTestTxStatusTracker(map[types.SHA256Hash]TxStatusUpdate{hash: testTxStatusUpdate(t, tx)})
+
+	statuses, err := tracker.Monitor([]types.SHA256Hash{hash}, "account")
+	require.NoError(t, err)
+	assert.Equal(t, types.Received, statuses[0].Status)
+	assert.Equal(t, []types.Status{types.Received}, notifications.statuses(hash))
+
+	// a second account shares the monitoring
+	_, err = tracker.Monitor([]types.SHA256Hash{hash}, "other")
+	require.NoError(t, err)
+	assert.False(t, tracker.Unmonitor(hash, "unknown"))
+	assert.True(t, tracker.Unmonitor(hash, "account"))
+	assert.Equal(t, 1, tracker.Size())
+	assert.True(t, tracker.Unmonitor(hash, "other"))
+	assert.Equal(t, 0, tracker.Size())
+}
+
+func TestTxStatusTracker_Replaced(t *testing.T) {
+	tracker, _, notifications := newTestTxStatusTracker(nil)
+	tx := bxmock.NewSignedEthTx(ethtypes.DynamicFeeTxType, 1, privateKey, big.NewInt(1))
+	replacement := bxmock.NewSignedEthTx(ethtypes.LegacyTxType, 1, privateKey, big.NewInt(1))
+	hash := types.SHA256Hash(tx.Hash())
+
+	_, err := tracker.Monitor([]types.SHA256Hash{hash}, "account")
+	require.NoError(t, err)
+	tracker.OnTransaction(testTxStatusUpdate(t, tx))
+
+	// the replacement is seen in the mempool, then included in a block
+	tracker.OnTransaction(testTxStatusUpdate(t, replacement))
+	status, ok := tracker.Status(hash)
+	require.True(t, ok)
+	assert.Equal(t, types.Replaced, status.Status)
+	assert.Equal(t, types.SHA256Hash(replacement.Hash()), status.ReplacedBy)
+
+	tracker.OnBlock(testTxStatusBlock(100, replacement))
+	assert.Equal(t, []types.Status{types.Received, types.Replaced, types.Replaced}, notifications.statuses(hash))
+	assert.Equal(t, types.SHA256Hash(replacement.Hash()).Format(true), notifications.last().ReplacedBy)
+	assert.Equal(t, 0, tracker.Size())
+}
+
+func TestTxStatusTracker_Dropped(t *testing.T) {
+	tracker, clock, notifications := newTestTxStatusTracker(nil)
+	tx := bxmock.NewSignedEthTx(ethtypes.DynamicFeeTxType, 1, privateKey, big.NewInt(1))
+	later := bxmock.NewSignedEthTx(ethtypes.DynamicFeeTxType, 2, privateKey, big.NewInt(1))
+	hash := types.SHA256Hash(tx.Hash())
+
+	// the nonce of the transaction is used by a transaction the gateway did not see
+	_, err := tracker.Monitor([]types.SHA256Hash{hash}, "account")
+	require.NoError(t, err)
+	tracker.OnTransaction(testTxStatusUpdate(t, tx))
+	tracker.OnBlock(testTxStatusBlock(100, later))
+	assert.Equal(t, []types.Status{types.Received, types.Dropped}, notifications.statuses(hash))
+	assert.Equal(t, 0, tracker.Size())
+
+	// the transaction is never seen
+	otherKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	unseen := types.SHA256Hash(bxmock.NewSignedEthTx(ethtypes.DynamicFeeTxType, 1, otherKey, big.NewInt(1)).Hash())
+	_, err = tracker.Monitor([]types.SHA256Hash{unseen}, "account")
+	require.NoError(t, err)
+	tracker.OnBlock(testTxStatusBlock(101))
+	assert.Equal(t, 1, tracker.Size())
+
+	clock.IncTime(monitoredTxDropTimeout + time.Second)
+	tracker.OnBlock(testTxStatusBlock(102))
+	assert.Equal(t, []types.Status{types.Dropped}, notifications.statuses(unseen))
+	assert.Equal(t, 0, tracker.Size())
+}
+
+func TestTxStatusTracker_Limit(t *testing.T) {
+	tracker, _, notifications := newTestTxStatusTracker(nil)
+
+	hashes := make([]types.SHA256Hash, 0, maxMonitoredTxs)
+	for i := 0; i < maxMonitoredTxs; i++ {
+		hashes = append(hashes, types.SHA256Hash{byte(i), byte(i >> 8), 1})
+	}
+	_, err := tracker.Monitor(hashes[:maxMonitoredTxs-1], "account")
+	require.NoError(t, err)
+
+	// none of the transactions is monitored if the account would go over the limit
+	_, err = tracker.Monitor([]types.SHA256Hash{hashes[maxMonitoredTxs-1], {2}}, "account")
+	assert.ErrorIs(t, err, ErrTooManyMonitoredTxs)
+	assert.Equal(t, maxMonitoredTxs-1, tracker.Size())
+
+	// the transactions already monitored by the account do not count again, the limit is per account
+	_, err = tracker.Monitor(hashes[maxMonitoredTxs-2:], "account")
+	require.NoError(t, err)
+	_, err = tracker.Monitor([]types.SHA256Hash{hashes[0], {2}}, "other")
+	require.NoError(t, err)
+	assert.True(t, tracker.Unmonitor(hashes[1], "account"))
+	_, err = tracker.Monitor([]types.SHA256Hash{{3}}, "account")
+	require.NoError(t, err)
+
+	// the status is notified to the accounts monitoring the transaction only
+	tracker.OnTransaction(TxStatusUpdate{Hash: types.SHA256Hash{2}})
+	assert.Equal(t, []types.AccountID{"other"}, notifications.last().AccountIDs)
+}
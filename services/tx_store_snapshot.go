@@ -0,0 +1,232 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+const (
+	txStoreDirName          = "txstore"
+	txStoreSnapshotFileName = "txstore.snapshot"
+	txStoreSnapshotVersion  = 1
+
+	// bounds of a snapshot transaction, a larger length means the snapshot is corrupted
+	maxSnapshotShortIDs    = 1 << 10
+	maxSnapshotContentSize = 1 << 24
+)
+
+var txStoreSnapshotMagic = [4]byte{'b', 'x', 't', 's'}
+
+// EnablePersistence makes the BxTxStore reload its transactions from datadir on Start, and store them every interval
+// and on Stop. It must be called before Start
+func (t *BxTxStore) EnablePersistence(datadir string, interval time.Duration) {
+	t.snapshotPath = path.Join(datadir, txStoreDirName, txStoreSnapshotFileName)
+	t.snapshotInterval = interval
+}
+
+// storeOnDisk writes a snapshot of the transactions to a temporary file which then replaces the previous snapshot,
+// so a crash while storing leaves the previous snapshot intact
+func (t *BxTxStore) storeOnDisk() error {
+	startTime := time.Now()
+
+	if err := os.MkdirAll(path.Dir(t.snapshotPath), os.ModePerm); err != nil {
+		return err
+	}
+
+	tmpPath := t.snapshotPath + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("create file %s: %w", tmpPath, err)
+	}
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	w := bufio.NewWriter(f)
+	count, err := t.writeSnapshot(w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("write tx store snapshot %s: %w", tmpPath, err)
+	}
+
+	if err = os.Rename(tmpPath, t.snapshotPath); err != nil {
+		return fmt.Errorf("replace tx store snapshot %s: %w", t.snapshotPath, err)
+	}
+
+	log.Infof("TxStore stored %v transactions in %v, duration %v ms", count, t.snapshotPath, time.Since(startTime).Milliseconds())
+	return nil
+}
+
+func (t *BxTxStore) writeSnapshot(w io.Writer) (int, error) {
+	if _, err := w.Write(append(txStoreSnapshotMagic[:], txStoreSnapshotVersion)); err != nil {
+		return 0, err
+	}
+
+	var count int
+	var err error
+	t.hashToContent.Range(func(_ string, bxTransaction *types.BxTransaction) bool {
+		if err = writeSnapshotTx(w, bxTransaction); err != nil {
+			return false
+		}
+		count++
+		return true
+	})
+	return count, err
+}
+
+// loadFromDisk adds the transactions of the snapshot to the BxTxStore, except the ones the cleanup would remove
+func (t *BxTxStore) loadFromDisk() error {
+	startTime := time.Now()
+
+	f, err := os.Open(t.snapshotPath)
+	if os.IsNotExist(err) {
+		log.Infof("TxStore snapshot %s does not exist", t.snapshotPath)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	header := make([]byte, len(txStoreSnapshotMagic)+1)
+	if _, err = io.ReadFull(r, header); err != nil {
+		return fmt.Errorf("read tx store snapshot %s: %w", t.snapshotPath, err)
+	}
+	if !bytes.Equal(header[:len(txStoreSnapshotMagic)], txStoreSnapshotMagic[:]) || header[len(txStoreSnapshotMagic)] != txStoreSnapshotVersion {
+		return fmt.Errorf("tx store snapshot %s has an unknown format", t.snapshotPath)
+	}
+
+	now := t.clock.Now()
+	var loaded, pruned int
+	for {
+		bxTransaction, err := readSnapshotTx(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Warnf("TxStore snapshot %s is truncated after %v transactions: %v", t.snapshotPath, loaded+pruned, err)
+			break
+		}
+
+		txAge := now.Sub(bxTransaction.AddTime())
+		if txAge > t.maxTxAge || (txAge > t.noSIDAge && len(bxTransaction.ShortIDs()) == 0) {
+			pruned++
+			continue
+		}
+
+		// transactions received since the start are more recent than the snapshot
+		hash := bxTransaction.Hash()
+		if _, exists := t.hashToContent.LoadOrStore(string(hash[:]), bxTransaction); exists {
+			continue
+		}
+		for _, shortID := range bxTransaction.ShortIDs() {
+			t.shortIDToHash.Store(shortID, hash)
+		}
+		loaded++
+	}
+
+	log.Infof("TxStore loaded %v transactions from %v, pruned %v, duration %v ms", loaded, t.snapshotPath, pruned, time.Since(startTime).Milliseconds())
+	return nil
+}
+
+// writeSnapshotTx writes hash, network, flags, add time, sender, short IDs and content of a transaction
+func writeSnapshotTx(w io.Writer, bxTransaction *types.BxTransaction) error {
+	hash := bxTransaction.Hash()
+	sender := bxTransaction.Sender()
+	shortIDs := bxTransaction.ShortIDs()
+	content := bxTransaction.Content()
+
+	buf := make([]byte, 0, types.SHA256HashLen+4+2+8+len(sender)+4+len(shortIDs)*types.UInt32Len+4+len(content))
+	buf = append(buf, hash[:]...)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(bxTransaction.NetworkNum()))
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(bxTransaction.Flags()))
+	buf = binary.LittleEndian.AppendUint64(buf, uint64(bxTransaction.AddTime().UnixNano()))
+	buf = append(buf, sender[:]...)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(shortIDs)))
+	for _, shortID := range shortIDs {
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(shortID))
+	}
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(content)))
+	buf = append(buf, content...)
+
+	_, err := w.Write(buf)
+	return err
+}
+
+// readSnapshotTx reads a transaction written by writeSnapshotTx, it returns io.EOF at the end of the snapshot
+func readSnapshotTx(r io.Reader) (*types.BxTransaction, error) {
+	var hash types.SHA256Hash
+	if _, err := io.ReadFull(r, hash[:]); err != nil {
+		return nil, err
+	}
+
+	var fixed [4 + 2 + 8]byte
+	var sender types.Sender
+	var count [4]byte
+	if _, err := io.ReadFull(r, fixed[:]); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	if _, err := io.ReadFull(r, sender[:]); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	if _, err := io.ReadFull(r, count[:]); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+
+	networkNum := types.NetworkNum(binary.LittleEndian.Uint32(fixed[0:]))
+	flags := types.TxFlags(binary.LittleEndian.Uint16(fixed[4:]))
+	addTime := time.Unix(0, int64(binary.LittleEndian.Uint64(fixed[6:])))
+	bxTransaction := types.NewBxTransaction(hash, networkNum, flags, addTime)
+	bxTransaction.SetSender(sender)
+
+	shortIDsCount := binary.LittleEndian.Uint32(count[:])
+	if shortIDsCount > maxSnapshotShortIDs {
+		return nil, fmt.Errorf("transaction %v has %v short IDs", hash, shortIDsCount)
+	}
+	shortIDs := make([]byte, shortIDsCount*types.UInt32Len)
+	if _, err := io.ReadFull(r, shortIDs); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	for i := 0; i < len(shortIDs); i += types.UInt32Len {
+		bxTransaction.AddShortID(types.ShortID(binary.LittleEndian.Uint32(shortIDs[i:])))
+	}
+
+	if _, err := io.ReadFull(r, count[:]); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	contentSize := binary.LittleEndian.Uint32(count[:])
+	if contentSize > maxSnapshotContentSize {
+		return nil, fmt.Errorf("transaction %v has %v bytes of content", hash, contentSize)
+	}
+	content := make(types.TxContent, contentSize)
+	if _, err := io.ReadFull(r, content); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	bxTransaction.SetContent(content)
+
+	return bxTransaction, nil
+}
+
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPersistentTxStore(clock utils.Clock, datadir string) *BxTxStore {
+	store := newBxTxStore(clock, time.Hour, 30*time.Minute, 10*time.Minute,
+		NewEmptyShortIDAssigner(), NewHashHistory("seenTxs", 30*time.Minute), nil, 30*time.Minute, NoOpBloomFilter{})
+	store.EnablePersistence(datadir, time.Minute)
+	return &store
+}
+
+func TestBxTxStore_Persistence(t *testing.T) {
+	datadir := t.TempDir()
+	clock := utils.NewMockClock()
+	clock.SetTime(time.Unix(1700000000, 0))
+	now := clock.Now()
+
+	store := newTestPersistentTxStore(clock, datadir)
+	sender := types.Sender{0xaa}
+	store.Add(types.SHA256Hash{1}, types.TxContent{1, 2, 3}, 1, testNetworkNum, false, types.TFPaidTx, now.Add(-20*time.Minute), testChainID, sender)
+	store.Add(types.SHA256Hash{1}, types.TxContent{1, 2, 3}, 2, testNetworkNum, false, types.TFPaidTx, now.Add(-20*time.Minute), testChainID, sender)
+	store.Add(types.SHA256Hash{2}, types.TxContent{4}, types.ShortIDEmpty, testNetworkNum, false, 0, now.Add(-time.Minute), testChainID, types.EmptySender)
+	// pruned on load, no short ID for longer than the noSIDAge
+	store.Add(types.SHA256Hash{3}, types.TxContent{5}, types.ShortIDEmpty, testNetworkNum, false, 0, now.Add(-15*time.Minute), testChainID, types.EmptySender)
+	// pruned on load, older than the maxTxAge once reloaded
+	store.Add(types.SHA256Hash{4}, nil, 4, testNetworkNum, false, 0, now.Add(-29*time.Minute), testChainID, types.EmptySender)
+	require.NoError(t, store.storeOnDisk())
+
+	clock.IncTime(2 * time.Minute)
+	reloaded := newTestPersistentTxStore(clock, datadir)
+	require.NoError(t, reloaded.loadFromDisk())
+	assert.Equal(t, 2, reloaded.Count())
+
+	tx, err := reloaded.GetTxByShortID(2)
+	require.NoError(t, err)
+	assert.Equal(t, types.SHA256Hash{1}, tx.Hash())
+	assert.Equal(t, types.TxContent{1, 2, 3}, tx.Content())
+	assert.Equal(t, types.ShortIDList{1, 2}, tx.ShortIDs())
+	assert.Equal(t, types.TFPaidTx, tx.Flags())
+	assert.Equal(t, testNetworkNum, tx.NetworkNum())
+	assert.Equal(t, sender, tx.Sender())
+	assert.True(t, now.Add(-20*time.Minute).Equal(tx.AddTime()))
+
+	tx, ok := reloaded.Get(types.SHA256Hash{2})
+	require.True(t, ok)
+	assert.Empty(t, tx.ShortIDs())
+	assert.False(t, reloaded.HasContent(types.SHA256Hash{3}))
+	_, err = reloaded.GetTxByShortID(4)
+	assert.Error(t, err)
+}
+
+func TestBxTxStore_PersistenceOnStop(t *testing.T) {
+	datadir := t.TempDir()
+	clock := utils.NewMockClock()
+	clock.SetTime(time.Now())
+
+	store := newTestPersistentTxStore(clock, datadir)
+	store.Add(types.SHA256Hash{1}, types.TxContent{1}, 1, testNetworkNum, false, 0, clock.Now(), testChainID, types.EmptySender)
+	go func() { _ = store.Start() }()
+	store.Stop()
+
+	reloaded := newTestPersistentTxStore(clock, datadir)
+	go func() { _ = reloaded.Start() }()
+	assert.Eventually(t, func() bool { return reloaded.HasContent(types.SHA256Hash{1}) }, time.Second, time.Millisecond)
+	reloaded.Stop()
+}
+
+func TestBxTxStore_PersistenceTruncatedSnapshot(t *testing.T) {
+	datadir := t.TempDir()
+	clock := utils.NewMockClock()
+	clock.SetTime(time.Now())
+
+	store := newTestPersistentTxStore(clock, datadir)
+	store.Add(types.SHA256Hash{1}, types.TxContent{1}, 1, testNetworkNum, false, 0, clock.Now(), testChainID, types.EmptySender)
+	store.Add(types.SHA256Hash{2}, types.TxContent{2}, 2, testNetworkNum, false, 0, clock.Now(), testChainID, types.EmptySender)
+	require.NoError(t, store.storeOnDisk())
+
+	info, err := os.Stat(store.snapshotPath)
+	require.NoError(t, err)
+	require.NoError(t, os.Truncate(store.snapshotPath, info.Size()-1))
+
+	// the transactions before the truncated one are loaded
+	reloaded := newTestPersistentTxStore(clock, datadir)
+	require.NoError(t, reloaded.loadFromDisk())
+	assert.Equal(t, 1, reloaded.Count())
+
+	require.NoError(t, os.WriteFile(store.snapshotPath, []byte("garbage"), 0644))
+	assert.Error(t, newTestPersistentTxStore(clock, datadir).loadFromDisk())
+}
@@ -258,12 +258,13 @@ func (beaconBlockNotification *BellatrixBlockNotification) Clone() BlockNotifica
 
 // EthBlockNotification - represents a single block
 type EthBlockNotification struct {
-	BlockHash        *ethcommon.Hash          `json:"hash,omitempty"`
-	Header           *Header                  `json:"header,omitempty"`
-	Transactions     []map[string]interface{} `json:"transactions,omitempty"`
-	Uncles           []Header                 `json:"uncles,omitempty"`
-	ValidatorInfo    []*FutureValidatorInfo   `json:"future_validator_info,omitempty"`
-	Withdrawals      ethtypes.Withdrawals     `json:"withdrawals,omitempty"`
+	BlockHash     *ethcommon.Hash          `json:"hash,omitempty"`
+	Header        *Header                  `json:"header,omitempty"`
+	Transactions  []map[string]interface{} `json:"transactions,omitempty"`
+	Uncles        []Header                 `json:"uncles,omitempty"`
+	ValidatorInfo []*FutureValidatorInfo   `json:"future_validator_info,omitempty"`
+	Withdrawals   ethtypes.Withdrawals     `json:"withdrawals,omitempty"`
+	FeedSeq
 	rawTransactions  [][]byte
 	notificationType FeedType
 	source           *NodeEndpoint
@@ -0,0 +1,95 @@
+package types
+
+import (
+	pbbase "github.com/bloXroute-Labs/gateway/v2/protobuf"
+)
+
+// BundleStatus types of bundle state
+type BundleStatus string
+
+// BundlePending is bundle status for a bundle whose target block was not reached yet
+const BundlePending BundleStatus = "pending"
+
+// BundleIncluded is bundle status for a bundle whose transactions were all included
+const BundleIncluded BundleStatus = "included"
+
+// BundlePartiallyIncluded is bundle status for a bundle of which only some transactions were included by its target block
+const BundlePartiallyIncluded BundleStatus = "partially_included"
+
+// BundleExpired is bundle status for a bundle of which no transaction was included by its target block
+const BundleExpired BundleStatus = "expired"
+
+// BundleStatusNotification - represents the status of a bundle submitted to the gateway. The block fields describe the
+// latest block which included transactions of the bundle, the notification is delivered to the accounts which
+// submitted the bundle
+type BundleStatusNotification struct {
+	BundleHash        string       `json:"bundle_hash,omitempty"`
+	Status            BundleStatus `json:"status,omitempty"`
+	TargetBlockNumber uint64       `json:"target_block_number,omitempty"`
+	BlockNumber       uint64       `json:"block_number,omitempty"`
+	BlockHash         string       `json:"block_hash,omitempty"`
+	Builder           string       `json:"builder,omitempty"`
+	FeeRecipient      string       `json:"fee_recipient,omitempty"`
+	IncludedTxs       []string     `json:"included_txs,omitempty"`
+	NotificationAccounts
+}
+
+// WithFields -
+func (bn *BundleStatusNotification) WithFields(fields []string) Notification {
+	bundleStatusNotification := BundleStatusNotification{}
+	for _, param := range fields {
+		switch param {
+		case "bundle_hash":
+			bundleStatusNotification.BundleHash = bn.BundleHash
+		case "status":
+			bundleStatusNotification.Status = bn.Status
+		case "target_block_number":
+			bundleStatusNotification.TargetBlockNumber = bn.TargetBlockNumber
+		case "block_number":
+			bundleStatusNotification.BlockNumber = bn.BlockNumber
+		case "block_hash":
+			bundleStatusNotification.BlockHash = bn.BlockHash
+		case "builder":
+			bundleStatusNotification.Builder = bn.Builder
+		case "fee_recipient":
+			bundleStatusNotification.FeeRecipient = bn.FeeRecipient
+		case "included_txs":
+			bundleStatusNotification.IncludedTxs = bn.IncludedTxs
+		}
+	}
+	return &bundleStatusNotification
+}
+
+// Filters -
+func (bn *BundleStatusNotification) Filters(_ []string) map[string]interface{} {
+	return nil
+}
+
+// LocalRegion -
+func (bn *BundleStatusNotification) LocalRegion() bool {
+	return false
+}
+
+// GetHash -
+func (bn *BundleStatusNotification) GetHash() string {
+	return bn.BundleHash
+}
+
+// NotificationType - feed name
+func (bn *BundleStatusNotification) NotificationType() FeedType {
+	return BundleStatusFeed
+}
+
+// Protobuf formats the bundle status as a protobuf response struct
+func (bn *BundleStatusNotification) Protobuf() *pbbase.BundleStatusReply {
+	return &pbbase.BundleStatusReply{
+		BundleHash:        bn.BundleHash,
+		Status:            string(bn.Status),
+		TargetBlockNumber: bn.TargetBlockNumber,
+		BlockNumber:       bn.BlockNumber,
+		BlockHash:         bn.BlockHash,
+		Builder:           bn.Builder,
+		FeeRecipient:      bn.FeeRecipient,
+		IncludedTxs:       bn.IncludedTxs,
+	}
+}
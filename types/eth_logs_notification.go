@@ -0,0 +1,40 @@
+package types
+
+// EthLogsNotification - represents the receipts of a block which became canonical, or with Removed set, of a block
+// which is no longer canonical. It feeds the eth_subscribe logs subscriptions
+type EthLogsNotification struct {
+	BlockHash   string
+	BlockNumber uint64
+	Removed     bool
+	Receipts    []*TxReceipt
+}
+
+// NewEthLogsNotification returns a new eth logs notification
+func NewEthLogsNotification(blockHash string, blockNumber uint64, removed bool, receipts []*TxReceipt) *EthLogsNotification {
+	return &EthLogsNotification{BlockHash: blockHash, BlockNumber: blockNumber, Removed: removed, Receipts: receipts}
+}
+
+// WithFields -
+func (n *EthLogsNotification) WithFields(_ []string) Notification {
+	return n
+}
+
+// Filters -
+func (n *EthLogsNotification) Filters(_ []string) map[string]interface{} {
+	return nil
+}
+
+// LocalRegion -
+func (n *EthLogsNotification) LocalRegion() bool {
+	return false
+}
+
+// GetHash - hash of the block
+func (n *EthLogsNotification) GetHash() string {
+	return n.BlockHash
+}
+
+// NotificationType - feed name
+func (n *EthLogsNotification) NotificationType() FeedType {
+	return EthLogsFeed
+}
@@ -5,7 +5,6 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/big"
-	"strconv"
 	"sync"
 
 	log "github.com/bloXroute-Labs/gateway/v2/logger"
@@ -63,22 +62,6 @@ var AllFields = []string{
 // AllFieldsWithFrom is used with transactions feeds
 var AllFieldsWithFrom = append(AllFields, "tx_contents.from")
 
-// EmptyFilteredTransactionMap is a map of key value used to check the filters provided by the websocket client
-var EmptyFilteredTransactionMap = map[string]interface{}{
-	"from":                     "0x0",
-	"gas_price":                float64(0),
-	"gas":                      float64(0),
-	"tx_hash":                  "0x0",
-	"input":                    "0x0",
-	"method_id":                "0x0",
-	"value":                    float64(0),
-	"to":                       "0x0",
-	"type":                     "0",
-	"chain_id":                 float64(0),
-	"max_fee_per_gas":          float64(0),
-	"max_priority_fee_per_gas": float64(0),
-}
-
 // NewEthTransaction converts a canonic Ethereum transaction to EthTransaction
 func NewEthTransaction(h SHA256Hash, rawEthTx *ethtypes.Transaction, sender Sender) (*EthTransaction, error) {
 	ethTx := &EthTransaction{
@@ -158,42 +141,22 @@ func (et *EthTransaction) createFilters() {
 	}
 
 	tx := et.tx
-	et.filters["chain_id"] = int(tx.ChainId().Int64())
+	et.filters["chain_id"] = tx.ChainId()
+	// fee fields are only set for the tx types that define them, a missing field only matches != and not in filters
 	if tx.Type() == ethtypes.DynamicFeeTxType {
-		et.filters["max_fee_per_gas"] = int(tx.GasFeeCap().Int64())
-		et.filters["max_priority_fee_per_gas"] = int(tx.GasTipCap().Int64())
-		et.filters["gas_price"] = 0
-		// Because the short circuit behavior is not working in the current evaluation library, and when it tries to evaluate
-		// all parts of the expressions fail on the undefined values.
-		// For example, before this change, the following filter always failed:
-		// (type == '0' && gas_price > 21000000000) || (type == '2' && max_priority_fee_per_gas > 21000000000).
-		//
-		// That's why was decided to set default defined values, just to be able to evaluate the expressions.
-		// Anyway, the evaluation will be correct regardless of the default values.
-		//
-		// Also, to handle the 1-filter setup, when the user defines only the gas_price filter, for example,
-		// we provide the preprocessing check of the tx type and configured filters.
-		// check out isFiltersSupportedByTxType function of bxgateway/servers/client_handler.go
-		//
-		// It means, that when it comes to the evaluation of the filters, we will have only complex expressions,
-		// where we don't actually care about these default values we configured here.
-		//
-		// TODO: to get rid of this stuff, we need to move to another expression evaluation library (or forking existing one)
-		// to support short circuit behavior for the logical operators.
+		et.filters["max_fee_per_gas"] = tx.GasFeeCap()
+		et.filters["max_priority_fee_per_gas"] = tx.GasTipCap()
 	} else {
-		et.filters["gas_price"] = BigIntAsFloat64(tx.GasPrice())
-		et.filters["max_fee_per_gas"] = 0
-		et.filters["max_priority_fee_per_gas"] = 0
+		et.filters["gas_price"] = tx.GasPrice()
 	}
 
-	et.filters["type"] = strconv.Itoa(int(tx.Type()))
-	et.filters["value"] = BigIntAsFloat64(tx.Value())
-	et.filters["gas"] = float64(tx.Gas())
+	et.filters["type"] = new(big.Int).SetUint64(uint64(tx.Type()))
+	et.filters["value"] = tx.Value()
+	et.filters["gas"] = new(big.Int).SetUint64(tx.Gas())
 
+	// a contract creation has no to field
 	if tx.To() != nil {
 		et.filters["to"] = AddressAsString(tx.To())
-	} else {
-		et.filters["to"] = "0x0"
 	}
 
 	// note: from some reason method_id is only a filter field
@@ -290,6 +253,16 @@ func (et *EthTransaction) Nonce() uint64 {
 	return et.tx.Nonce()
 }
 
+// Gas returns the gas limit of the transaction
+func (et *EthTransaction) Gas() uint64 {
+	return et.tx.Gas()
+}
+
+// Data returns the input data of the transaction
+func (et *EthTransaction) Data() []byte {
+	return et.tx.Data()
+}
+
 // Filters returns a map of key,value that can be used to filter transactions
 func (et *EthTransaction) Filters(filters []string) map[string]interface{} {
 	et.createFilters()
@@ -106,9 +106,9 @@ func TestLegacyTransaction(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, test.Contains(filteredTx, "type"))
 	assert.Equal(t, fixtures.LegacyFromAddress, filteredTx["from"])
-	assert.Equal(t, fixtures.LegacyGasPrice, filteredTx["gas_price"])
+	assert.Equal(t, big.NewInt(fixtures.LegacyGasPrice).String(), filteredTx["gas_price"].(*big.Int).String())
 	// when chain ID is explicitly asked for it's included
-	assert.Equal(t, 1, filteredTx["chain_id"])
+	assert.Equal(t, big.NewInt(fixtures.LegacyChainID).String(), filteredTx["chain_id"].(*big.Int).String())
 }
 
 func TestAccessListTransaction(t *testing.T) {
@@ -160,10 +160,10 @@ func TestAccessListTransaction(t *testing.T) {
 		"type",
 	})
 	assert.NoError(t, err)
-	assert.Equal(t, "1", filteredTx["type"])
+	assert.Equal(t, "1", filteredTx["type"].(*big.Int).String())
 	assert.Equal(t, fixtures.AccessListFromAddress, filteredTx["from"])
-	assert.Equal(t, fixtures.AccessListGasPrice, filteredTx["gas_price"])
-	assert.Equal(t, fixtures.AccessListChainID, filteredTx["chain_id"])
+	assert.Equal(t, big.NewInt(fixtures.AccessListGasPrice).String(), filteredTx["gas_price"].(*big.Int).String())
+	assert.Equal(t, big.NewInt(fixtures.AccessListChainID).String(), filteredTx["chain_id"].(*big.Int).String())
 }
 
 func TestDynamicFeeTransaction(t *testing.T) {
@@ -231,9 +231,11 @@ func TestDynamicFeeTransaction(t *testing.T) {
 	})
 	assert.NoError(t, err)
 	assert.Equal(t, fixtures.DynamicFeeFromAddress, filteredTx["from"])
-	assert.Equal(t, fixtures.DynamicFeeChainID, filteredTx["chain_id"])
-	assert.Equal(t, fixtures.DynamicFeeFeePerGas, filteredTx["max_fee_per_gas"])
-	assert.Equal(t, fixtures.DynamicFeeTipPerGas, filteredTx["max_priority_fee_per_gas"])
+	assert.Equal(t, big.NewInt(fixtures.DynamicFeeChainID).String(), filteredTx["chain_id"].(*big.Int).String())
+	assert.Equal(t, big.NewInt(fixtures.DynamicFeeFeePerGas).String(), filteredTx["max_fee_per_gas"].(*big.Int).String())
+	assert.Equal(t, big.NewInt(fixtures.DynamicFeeTipPerGas).String(), filteredTx["max_priority_fee_per_gas"].(*big.Int).String())
+	// gas_price is not defined for dynamic fee txs
+	assert.False(t, test.Contains(filteredTx, "gas_price"))
 }
 
 func TestContractCreationTx(t *testing.T) {
@@ -241,14 +243,15 @@ func TestContractCreationTx(t *testing.T) {
 	assert.NoError(t, err)
 	ethTx.Fields([]string{})
 	filters := ethTx.Filters([]string{"to"})
-	assert.Equal(t, "0x0", filters["to"])
+	_, ok := filters["to"]
+	assert.False(t, ok)
 
 	assert.Equal(t, "0x"+hash.String(), ethTx.fields["hash"])
 
 	ethJSON := ethTx.Fields([]string{"tx_contents.to", "tx_contents.from"})
 
 	to, ok := ethJSON["to"]
-	assert.Equal(t, false, ok)
+	assert.False(t, ok)
 	assert.Equal(t, nil, to)
 	assert.Equal(t, "0x09e9ff67d9d5a25fa465db6f0bede5560581f8cb", ethJSON["from"])
 }
@@ -5,13 +5,18 @@ type FeedType string
 
 // FeedType enumeration
 const (
-	NewTxsFeed              FeedType = "newTxs"
-	PendingTxsFeed          FeedType = "pendingTxs"
-	BDNBlocksFeed           FeedType = "bdnBlocks"
-	NewBlocksFeed           FeedType = "newBlocks"
-	OnBlockFeed             FeedType = "ethOnBlock"
-	TxReceiptsFeed          FeedType = "txReceipts"
+	NewTxsFeed     FeedType = "newTxs"
+	PendingTxsFeed FeedType = "pendingTxs"
+	BDNBlocksFeed  FeedType = "bdnBlocks"
+	NewBlocksFeed  FeedType = "newBlocks"
+	OnBlockFeed    FeedType = "ethOnBlock"
+	TxReceiptsFeed FeedType = "txReceipts"
+	ReorgFeed      FeedType = "reorg"
+	// EthLogsFeed is the internal feed of the eth_subscribe logs subscriptions
+	EthLogsFeed             FeedType = "ethLogs"
 	TransactionStatusFeed   FeedType = "transactionStatus"
+	GasEstimateFeed         FeedType = "gasEstimate"
+	BundleStatusFeed        FeedType = "bundleStatus"
 	UserIntentsFeed         FeedType = "userIntentFeed"
 	UserIntentSolutionsFeed FeedType = "userIntentSolutionsFeed"
 )
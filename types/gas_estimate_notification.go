@@ -0,0 +1,102 @@
+package types
+
+import (
+	pbbase "github.com/bloXroute-Labs/gateway/v2/protobuf"
+)
+
+// GasEstimateTier - recommended fees for a transaction to be included in the next block with the given probability
+type GasEstimateTier struct {
+	Probability          float64 `json:"probability"`
+	MaxPriorityFeePerGas string  `json:"max_priority_fee_per_gas"`
+	MaxFeePerGas         string  `json:"max_fee_per_gas"`
+}
+
+// GasTipBucket - pending transactions whose effective tip is at least MinTip and below MaxTip, MaxTip is empty for
+// the highest bucket
+type GasTipBucket struct {
+	MinTip string `json:"min_tip"`
+	MaxTip string `json:"max_tip,omitempty"`
+	Count  int    `json:"count"`
+	Gas    uint64 `json:"gas"`
+}
+
+// GasEstimateNotification - represents the fees recommended by the gas oracle of the gateway after a block. Fees are
+// decimal strings in wei
+type GasEstimateNotification struct {
+	BlockNumber uint64            `json:"block_number,omitempty"`
+	BlockHash   string            `json:"block_hash,omitempty"`
+	BaseFee     string            `json:"base_fee,omitempty"`
+	NextBaseFee string            `json:"next_base_fee,omitempty"`
+	Tiers       []GasEstimateTier `json:"tiers,omitempty"`
+	MempoolTips []GasTipBucket    `json:"mempool_tips,omitempty"`
+}
+
+// WithFields -
+func (g *GasEstimateNotification) WithFields(fields []string) Notification {
+	gasEstimateNotification := GasEstimateNotification{}
+	for _, param := range fields {
+		switch param {
+		case "block_number":
+			gasEstimateNotification.BlockNumber = g.BlockNumber
+		case "block_hash":
+			gasEstimateNotification.BlockHash = g.BlockHash
+		case "base_fee":
+			gasEstimateNotification.BaseFee = g.BaseFee
+		case "next_base_fee":
+			gasEstimateNotification.NextBaseFee = g.NextBaseFee
+		case "tiers":
+			gasEstimateNotification.Tiers = g.Tiers
+		case "mempool_tips":
+			gasEstimateNotification.MempoolTips = g.MempoolTips
+		}
+	}
+	return &gasEstimateNotification
+}
+
+// Filters -
+func (g *GasEstimateNotification) Filters(_ []string) map[string]interface{} {
+	return nil
+}
+
+// LocalRegion -
+func (g *GasEstimateNotification) LocalRegion() bool {
+	return false
+}
+
+// GetHash - hash of the block the estimate follows
+func (g *GasEstimateNotification) GetHash() string {
+	return g.BlockHash
+}
+
+// NotificationType - feed name
+func (g *GasEstimateNotification) NotificationType() FeedType {
+	return GasEstimateFeed
+}
+
+// Protobuf formats the estimate as a protobuf response struct
+func (g *GasEstimateNotification) Protobuf() *pbbase.GasEstimateReply {
+	reply := &pbbase.GasEstimateReply{
+		BlockNumber: g.BlockNumber,
+		BlockHash:   g.BlockHash,
+		BaseFee:     g.BaseFee,
+		NextBaseFee: g.NextBaseFee,
+		Tiers:       make([]*pbbase.GasEstimateTier, 0, len(g.Tiers)),
+		MempoolTips: make([]*pbbase.GasTipBucket, 0, len(g.MempoolTips)),
+	}
+	for _, tier := range g.Tiers {
+		reply.Tiers = append(reply.Tiers, &pbbase.GasEstimateTier{
+			Probability:          tier.Probability,
+			MaxPriorityFeePerGas: tier.MaxPriorityFeePerGas,
+			MaxFeePerGas:         tier.MaxFeePerGas,
+		})
+	}
+	for _, bucket := range g.MempoolTips {
+		reply.MempoolTips = append(reply.MempoolTips, &pbbase.GasTipBucket{
+			MinTip: bucket.MinTip,
+			MaxTip: bucket.MaxTip,
+			Count:  uint32(bucket.Count),
+			Gas:    bucket.Gas,
+		})
+	}
+	return reply
+}
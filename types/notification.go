@@ -18,3 +18,52 @@ type BlockNotification interface {
 	IsNil() bool
 	Clone() BlockNotification
 }
+
+// SequencedNotification represents a notification of a feed that keeps a replayable history
+type SequencedNotification interface {
+	Notification
+
+	Seq() uint64
+	SetSeq(uint64)
+}
+
+// FeedSeq - position of a notification in the history of its feed
+type FeedSeq struct {
+	seq uint64
+}
+
+// Seq returns the sequence number of the notification, 0 if the feed has no history
+func (s *FeedSeq) Seq() uint64 {
+	return s.seq
+}
+
+// SetSeq sets the sequence number of the notification
+func (s *FeedSeq) SetSeq(seq uint64) {
+	s.seq = seq
+}
+
+// AccountNotification represents a notification delivered only to the accounts it concerns
+type AccountNotification interface {
+	Notification
+
+	ForAccount(accountID AccountID) bool
+}
+
+// NotificationAccounts - accounts a notification is delivered to, stored with the notification in the feed history
+type NotificationAccounts struct {
+	AccountIDs []AccountID `json:"account_ids,omitempty"`
+}
+
+// ForAccount checks if the notification is delivered to the account, a notification without accounts is delivered
+// to all of them
+func (a *NotificationAccounts) ForAccount(accountID AccountID) bool {
+	if len(a.AccountIDs) == 0 {
+		return true
+	}
+	for _, id := range a.AccountIDs {
+		if id == accountID {
+			return true
+		}
+	}
+	return false
+}
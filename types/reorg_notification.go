@@ -0,0 +1,56 @@
+package types
+
+// ReorgBlock - height and hash of a block dropped from or added to the canonical chain
+type ReorgBlock struct {
+	Hash   string `json:"hash"`
+	Height uint64 `json:"height"`
+}
+
+// ReorgNotification - represents a reorganization of the canonical chain seen by the gateway. Blocks are listed in
+// ascending height order, dropped blocks were previously sent in the block feeds and are no longer canonical
+type ReorgNotification struct {
+	Dropped []ReorgBlock `json:"dropped,omitempty"`
+	Added   []ReorgBlock `json:"added,omitempty"`
+}
+
+// NewReorgNotification returns a new reorg notification
+func NewReorgNotification(dropped, added []ReorgBlock) *ReorgNotification {
+	return &ReorgNotification{Dropped: dropped, Added: added}
+}
+
+// WithFields -
+func (r *ReorgNotification) WithFields(fields []string) Notification {
+	reorgNotification := ReorgNotification{}
+	for _, param := range fields {
+		switch param {
+		case "dropped":
+			reorgNotification.Dropped = r.Dropped
+		case "added":
+			reorgNotification.Added = r.Added
+		}
+	}
+	return &reorgNotification
+}
+
+// Filters -
+func (r *ReorgNotification) Filters(_ []string) map[string]interface{} {
+	return nil
+}
+
+// LocalRegion -
+func (r *ReorgNotification) LocalRegion() bool {
+	return false
+}
+
+// GetHash - hash of the new head
+func (r *ReorgNotification) GetHash() string {
+	if len(r.Added) == 0 {
+		return ""
+	}
+	return r.Added[len(r.Added)-1].Hash
+}
+
+// NotificationType - feed name
+func (r *ReorgNotification) NotificationType() FeedType {
+	return ReorgFeed
+}
@@ -22,7 +22,22 @@ type ClientInfo struct {
 
 // ReqOptions contains options for REQUEST
 type ReqOptions struct {
-	Filters  string
-	Includes string
-	Project  string
+	Filters      string
+	Includes     string
+	Project      string
+	Backpressure BackpressurePolicy
 }
+
+// BackpressurePolicy is what a subscription does with a new notification when its client does not keep up with the feed
+type BackpressurePolicy string
+
+const (
+	// BackpressureDisconnect closes the subscription, it is the default policy
+	BackpressureDisconnect BackpressurePolicy = "disconnect"
+	// BackpressureDropOldest drops the oldest queued notification to queue the new one
+	BackpressureDropOldest BackpressurePolicy = "drop-oldest"
+	// BackpressureDropNewest drops the new notification
+	BackpressureDropNewest BackpressurePolicy = "drop-newest"
+	// BackpressureCoalesce drops all the queued notifications, so only the latest block is sent
+	BackpressureCoalesce BackpressurePolicy = "coalesce"
+)
@@ -5,6 +5,10 @@ type TransactionStatusNotification struct {
 	SubscriptionIDs []string `json:"subscription_ids,omitempty"`
 	TransactionHash string   `json:"transaction_hash,omitempty"`
 	Status          Status   `json:"status,omitempty"`
+	BlockNumber     uint64   `json:"block_number,omitempty"`
+	ReplacedBy      string   `json:"replaced_by,omitempty"`
+	FeedSeq
+	NotificationAccounts
 }
 
 // Status types of transaction state
@@ -25,6 +29,30 @@ const Canceled Status = "canceled"
 // Replaced is transaction status for replaced status
 const Replaced Status = "replaced"
 
+// Held is transaction status for a transaction held by the gateway until its condition is met
+const Held Status = "held"
+
+// Expired is transaction status for a held transaction dropped because its condition was not met in time
+const Expired Status = "expired"
+
+// Failed is transaction status for a held transaction which could not be sent once its condition was met
+const Failed Status = "failed"
+
+// Received is transaction status for a monitored transaction received by the gateway
+const Received Status = "received"
+
+// SentToBDN is transaction status for a monitored transaction broadcast to the BDN
+const SentToBDN Status = "sent_to_bdn"
+
+// SentToNode is transaction status for a monitored transaction sent to the blockchain nodes of the gateway
+const SentToNode Status = "sent_to_node"
+
+// SeenInMempool is transaction status for a monitored transaction received from a blockchain node
+const SeenInMempool Status = "seen_in_mempool"
+
+// Dropped is transaction status for a monitored transaction which can no longer be included
+const Dropped Status = "dropped"
+
 // UpdateSource is the source for updating transaction status
 type UpdateSource string
 
@@ -43,6 +71,10 @@ func (tn *TransactionStatusNotification) WithFields(fields []string) Notificatio
 			txStatusNotification.TransactionHash = tn.TransactionHash
 		case "status":
 			txStatusNotification.Status = tn.Status
+		case "block_number":
+			txStatusNotification.BlockNumber = tn.BlockNumber
+		case "replaced_by":
+			txStatusNotification.ReplacedBy = tn.ReplacedBy
 		case "subscription_id":
 			txStatusNotification.SubscriptionIDs = tn.SubscriptionIDs
 		}
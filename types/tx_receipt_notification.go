@@ -11,6 +11,7 @@ const nullAddressStr = "0x"
 // to avoid deserializing/reserializing the message from Ethereum RPC, no conversion work is done
 type TxReceiptsNotification struct {
 	Receipts []*TxReceipt
+	FeedSeq
 }
 
 // NewTxReceiptsNotification returns a new tx receipts notification object
@@ -0,0 +1,144 @@
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+// Builder represents a MEV builder
+type Builder struct {
+	Name              string   `json:"name"`
+	Endpoints         []string `json:"endpoints"`
+	SignatureRequired bool     `json:"signature_required"`
+
+	// Headers are added to the requests sent to the builder
+	Headers map[string]string `json:"headers,omitempty"`
+	// Method is the JSON-RPC method of the bundles sent to the builder, --mev-bundle-method-name if empty
+	Method string `json:"method,omitempty"`
+	// RateLimit is the number of bundles per second sent to the builder, unlimited if zero
+	RateLimit uint64 `json:"rate_limit,omitempty"`
+	// Networks are the networks the builder builds blocks for, all the networks if empty
+	Networks []types.NetworkNum `json:"networks,omitempty"`
+	// ReplacementUUID sends the UUID of the bundles as the replacementUuid field rather than the uuid field
+	ReplacementUUID bool `json:"replacement_uuid,omitempty"`
+	// RefundPercent is sent as the refundPercent field of the bundles, the field is left out if nil
+	RefundPercent *int `json:"refund_percent,omitempty"`
+}
+
+// supportsNetwork returns true if the builder builds blocks for the network
+func (b *Builder) supportsNetwork(networkNum types.NetworkNum) bool {
+	if len(b.Networks) == 0 {
+		return true
+	}
+	for _, network := range b.Networks {
+		if network == networkNum {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Builder) validate() error {
+	if b.RefundPercent != nil && (*b.RefundPercent < 0 || *b.RefundPercent > 100) {
+		return fmt.Errorf("builder %v has an invalid refund percent %v, it must be between 0 and 100", b.Name, *b.RefundPercent)
+	}
+	return nil
+}
+
+// LoadBuilders reads the MEV builders file, a JSON object of the builders by name
+func LoadBuilders(path string) (map[string]*Builder, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mev builders file: %s", err)
+	}
+	return parseBuilders(contents)
+}
+
+func parseBuilders(contents []byte) (map[string]*Builder, error) {
+	var builders map[string]*Builder
+	if err := json.Unmarshal(contents, &builders); err != nil {
+		return nil, fmt.Errorf("failed to decode mev builders file: %s", err)
+	}
+	for name, builder := range builders {
+		if builder == nil {
+			return nil, fmt.Errorf("builder %v is empty", name)
+		}
+		builder.Name = name
+		if err := builder.validate(); err != nil {
+			return nil, err
+		}
+	}
+	return builders, nil
+}
+
+// WatchBuilders checks the MEV builders file every interval until ctx is done, and calls onChange with the builders
+// whenever it changes. A file which fails to load, or whose builders onChange rejects, keeps the previous builders.
+// The file is not watched if interval is not positive
+func WatchBuilders(ctx context.Context, path string, interval time.Duration, onChange func(map[string]*Builder) error) error {
+	watcher := &buildersFile{path: path}
+	if _, err := watcher.changed(); err != nil {
+		return fmt.Errorf("failed to open mev builders file: %s", err)
+	}
+	if interval <= 0 {
+		return nil
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := watcher.reload(onChange); err != nil {
+					log.Errorf("failed to reload the mev builders from %v: %v", path, err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+type buildersFile struct {
+	path    string
+	modTime time.Time
+	size    int64
+}
+
+// changed returns true if the file was modified since the previous call
+func (f *buildersFile) changed() (bool, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return false, err
+	}
+	if info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return false, nil
+	}
+	f.modTime = info.ModTime()
+	f.size = info.Size()
+	return true, nil
+}
+
+func (f *buildersFile) reload(onChange func(map[string]*Builder) error) error {
+	changed, err := f.changed()
+	if err != nil || !changed {
+		return err
+	}
+
+	builders, err := LoadBuilders(f.path)
+	if err != nil {
+		return err
+	}
+	if err = onChange(builders); err != nil {
+		return err
+	}
+	log.Infof("reloaded %v mev builders from %v", len(builders), f.path)
+	return nil
+}
@@ -0,0 +1,80 @@
+package bundle
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadBuilders(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "builders.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"builder1": {"endpoints": ["http://builder1"], "signature_required": true},
+		"builder2": {"endpoints": ["http://builder2"], "headers": {"X-Api-Key": "key"}, "method": "eth_sendBundleV2",
+			"rate_limit": 10, "networks": [5], "replacement_uuid": true, "refund_percent": 90}
+	}`), 0644))
+
+	builders, err := LoadBuilders(path)
+	require.NoError(t, err)
+	require.Len(t, builders, 2)
+	assert.Equal(t, "builder1", builders["builder1"].Name)
+	assert.True(t, builders["builder1"].SignatureRequired)
+	assert.True(t, builders["builder1"].supportsNetwork(types.NetworkNum(36)))
+
+	builder2 := builders["builder2"]
+	assert.Equal(t, map[string]string{"X-Api-Key": "key"}, builder2.Headers)
+	assert.Equal(t, "eth_sendBundleV2", builder2.Method)
+	assert.Equal(t, uint64(10), builder2.RateLimit)
+	assert.True(t, builder2.supportsNetwork(types.NetworkNum(5)))
+	assert.False(t, builder2.supportsNetwork(types.NetworkNum(36)))
+	assert.True(t, builder2.ReplacementUUID)
+	require.NotNil(t, builder2.RefundPercent)
+	assert.Equal(t, 90, *builder2.RefundPercent)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"builder1": {"endpoints": ["http://builder1"], "refund_percent": 101}}`), 0644))
+	_, err = LoadBuilders(path)
+	assert.Error(t, err)
+	require.NoError(t, os.WriteFile(path, []byte(`{"builder1": null}`), 0644))
+	_, err = LoadBuilders(path)
+	assert.Error(t, err)
+}
+
+func TestBuildersFileReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "builders.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"builder1": {"endpoints": ["http://builder1"]}}`), 0644))
+	file := &buildersFile{path: path}
+	_, err := file.changed()
+	require.NoError(t, err)
+
+	var reloaded map[string]*Builder
+	onChange := func(builders map[string]*Builder) error {
+		reloaded = builders
+		return nil
+	}
+
+	// an unchanged file is not reloaded
+	require.NoError(t, file.reload(onChange))
+	assert.Nil(t, reloaded)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"builder1": {"endpoints": ["http://builder1"]}, "builder2": {"endpoints": ["http://builder2"]}}`), 0644))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Second)))
+	require.NoError(t, file.reload(onChange))
+	assert.Len(t, reloaded, 2)
+
+	// a broken file keeps the previous builders
+	reloaded = nil
+	require.NoError(t, os.WriteFile(path, []byte(`{"builder1": `), 0644))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(2*time.Second)))
+	assert.Error(t, file.reload(onChange))
+	assert.Nil(t, reloaded)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"builder3": {"endpoints": ["http://builder3"]}}`), 0644))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(3*time.Second)))
+	assert.Error(t, file.reload(func(map[string]*Builder) error { return errors.New("not allowed") }))
+}
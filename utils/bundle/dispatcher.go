@@ -2,9 +2,11 @@ package bundle
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
 	"sync"
 	"time"
@@ -15,6 +17,7 @@ import (
 	log "github.com/bloXroute-Labs/gateway/v2/logger"
 	"github.com/bloXroute-Labs/gateway/v2/services/statistics"
 	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -23,32 +26,49 @@ import (
 
 const (
 	flashbotAuthHeader = "X-Flashbots-Signature"
-)
 
-// Builder represents a MEV builder
-type Builder struct {
-	Name              string   `json:"name"`
-	Endpoints         []string `json:"endpoints"`
-	SignatureRequired bool     `json:"signature_required"`
-}
+	// maxBundleAttempts bounds the requests sending a bundle to an endpoint
+	maxBundleAttempts = 3
+	// bundleAttemptTimeout bounds a single request to an endpoint
+	bundleAttemptTimeout = 5 * time.Second
+	// bundleRetryBackoff is the delay before the first retry, doubled for each following retry, plus a random jitter of
+	// up to the delay
+	bundleRetryBackoff = 100 * time.Millisecond
+	// defaultBlockInterval is the time between blocks used to compute the deadline of a bundle until two blocks are seen
+	defaultBlockInterval = 12 * time.Second
+)
 
-// Dispatcher is responsible for dispatching MEV bundles to MEV builders
+// Dispatcher is responsible for dispatching MEV bundles to MEV builders. A failed request is retried while the
+// target block of the bundle is not built, and the endpoints which have been failing are skipped for a while
 type Dispatcher struct {
 	stats               statistics.Stats
+	clock               utils.Clock
 	client              *http.Client
-	builders            map[string]*Builder
+	bundleMethod        string
 	mevMaxProfitBuilder bool
 	processMegaBundle   bool
+	health              *builderHealth
+
+	buildersLock sync.RWMutex
+	builders     map[string]*Builder
+	rateLimiters map[string]utils.RateLimiter
+
+	headLock      sync.Mutex
+	headNumber    uint64
+	headTime      time.Time
+	blockInterval time.Duration
 }
 
-// NewDispatcher creates a new NewDispatcher
-func NewDispatcher(stats statistics.Stats, builders map[string]*Builder, mevMaxProfitBuilder bool, processMegaBundle bool) *Dispatcher {
-	for name, builder := range builders {
-		builder.Name = name
+// NewDispatcher creates a new NewDispatcher, the bundles are sent with bundleMethod to the builders which do not set
+// their own method
+func NewDispatcher(stats statistics.Stats, builders map[string]*Builder, bundleMethod string, mevMaxProfitBuilder bool, processMegaBundle bool) *Dispatcher {
+	if bundleMethod == "" {
+		bundleMethod = string(jsonrpc.RPCEthSendBundle)
 	}
 
-	return &Dispatcher{
+	d := &Dispatcher{
 		stats: stats,
+		clock: utils.RealClock{},
 		client: &http.Client{
 			Transport: &http.Transport{
 				MaxConnsPerHost:     100,
@@ -58,15 +78,84 @@ func NewDispatcher(stats statistics.Stats, builders map[string]*Builder, mevMaxP
 			},
 			Timeout: 60 * time.Second,
 		},
-		builders:            builders,
+		bundleMethod:        bundleMethod,
 		mevMaxProfitBuilder: mevMaxProfitBuilder,
 		processMegaBundle:   processMegaBundle,
+		health:              newBuilderHealth(),
+		blockInterval:       defaultBlockInterval,
+	}
+	d.SetBuilders(builders)
+	return d
+}
+
+// SetBuilders replaces the MEV builders the bundles are sent to, the bundles being sent keep the previous builders. A
+// builder whose rate limit is unchanged keeps its rate limiter, and the bundles it already took
+func (d *Dispatcher) SetBuilders(builders map[string]*Builder) {
+	d.buildersLock.Lock()
+	defer d.buildersLock.Unlock()
+
+	rateLimiters := make(map[string]utils.RateLimiter)
+	for name, builder := range builders {
+		builder.Name = name
+		if builder.RateLimit == 0 {
+			continue
+		}
+		if previous, ok := d.rateLimiters[name]; ok && previous.Limit() == builder.RateLimit {
+			rateLimiters[name] = previous
+			continue
+		}
+		rateLimiters[name] = utils.NewLeakyBucketRateLimiter(d.clock, builder.RateLimit, time.Second)
+	}
+
+	d.builders = builders
+	d.rateLimiters = rateLimiters
+}
+
+// HasBuilders returns true if MEV builders are configured
+func (d *Dispatcher) HasBuilders() bool {
+	d.buildersLock.RLock()
+	defer d.buildersLock.RUnlock()
+	return len(d.builders) > 0
+}
+
+// OnBlock records the head of the chain, the deadlines of the bundles are computed from it
+func (d *Dispatcher) OnBlock(number uint64, timestamp uint64) {
+	d.headLock.Lock()
+	defer d.headLock.Unlock()
+
+	blockTime := time.Unix(int64(timestamp), 0)
+	if d.headNumber != 0 && number > d.headNumber && blockTime.After(d.headTime) {
+		d.blockInterval = blockTime.Sub(d.headTime) / time.Duration(number-d.headNumber)
+	}
+	d.headNumber = number
+	d.headTime = blockTime
+}
+
+// Health returns the health of the MEV builder endpoints the bundles were sent to
+func (d *Dispatcher) Health() []EndpointHealth {
+	return d.health.snapshot()
+}
+
+// deadline returns the time the target block of the bundle is expected to be built, retries are not sent after it
+func (d *Dispatcher) deadline(bundle *bxmessage.MEVBundle) time.Time {
+	now := d.clock.Now()
+	target, err := hexutil.DecodeUint64(bundle.BlockNumber)
+
+	d.headLock.Lock()
+	defer d.headLock.Unlock()
+
+	if err != nil || d.headNumber == 0 {
+		return now.Add(d.blockInterval)
+	}
+	if target <= d.headNumber {
+		return now
 	}
+	return d.headTime.Add(time.Duration(target-d.headNumber) * d.blockInterval)
 }
 
 // Dispatch dispatches the MEV bundle to the MEV builders
 func (d *Dispatcher) Dispatch(bundle *bxmessage.MEVBundle) error {
-	if len(d.builders) == 0 {
+	if !d.HasBuilders() {
 		log.Warnf("received mevBundle message, but mev-builders-file-path is empty. Message %v from %v in network %v", bundle.BundleHash, bundle.SourceID(), bundle.GetNetworkNum())
 		return nil
 	}
@@ -81,17 +170,13 @@ func (d *Dispatcher) Dispatch(bundle *bxmessage.MEVBundle) error {
 		return nil
 	}
 
-	json, err := d.bundleJSON(bundle)
-	if err != nil {
-		return fmt.Errorf("failed to create new mevBundle http request for bundleHash: %v, err: %v", bundle.BundleHash, err)
-	}
-
-	d.makeRequests(bundle, json)
+	d.makeRequests(bundle)
 
 	return nil
 }
 
-func (d *Dispatcher) bundleJSON(bundle *bxmessage.MEVBundle) ([]byte, error) {
+// bundleJSON returns the request sending the bundle to the builder, with the fields and the method the builder accepts
+func (d *Dispatcher) bundleJSON(bundle *bxmessage.MEVBundle, builder *Builder) ([]byte, error) {
 	params := []jsonrpc.RPCSendBundle{
 		{
 			Txs:               bundle.Transactions,
@@ -102,8 +187,13 @@ func (d *Dispatcher) bundleJSON(bundle *bxmessage.MEVBundle) ([]byte, error) {
 			RevertingTxHashes: bundle.RevertingHashes,
 			BundlePrice:       bundle.BundlePrice,
 			EnforcePayout:     bundle.EnforcePayout,
+			RefundPercent:     builder.RefundPercent,
 		},
 	}
+	if builder.ReplacementUUID {
+		params[0].ReplacementUUID = params[0].UUID
+		params[0].UUID = ""
+	}
 
 	paramsBytes, err := json.Marshal(params)
 	if err != nil {
@@ -112,7 +202,7 @@ func (d *Dispatcher) bundleJSON(bundle *bxmessage.MEVBundle) ([]byte, error) {
 
 	mevBundle := jsonrpc2.Request{
 		Params: (*json.RawMessage)(&paramsBytes),
-		Method: string(jsonrpc.RPCEthSendBundle),
+		Method: d.builderMethod(builder),
 	}
 
 	json, err := json.Marshal(mevBundle)
@@ -123,25 +213,45 @@ func (d *Dispatcher) bundleJSON(bundle *bxmessage.MEVBundle) ([]byte, error) {
 	return json, nil
 }
 
+func (d *Dispatcher) builderMethod(builder *Builder) string {
+	if builder.Method != "" {
+		return builder.Method
+	}
+	return d.bundleMethod
+}
+
 // makeRequests concurrently sends provided bundle to MEVBuilders
-func (d *Dispatcher) makeRequests(bundle *bxmessage.MEVBundle, json []byte) {
+func (d *Dispatcher) makeRequests(bundle *bxmessage.MEVBundle) {
 	mevBuilders := bundle.MEVBuilders
 	_, ok := bundle.MEVBuilders[bxgateway.AllBuilderName]
 	if ok {
 		mevBuilders = d.getAllBuilders()
 	}
 
+	deadline := d.deadline(bundle)
 	var wg = new(sync.WaitGroup)
 	for builderName := range mevBuilders {
-		builder := d.getBuilder(builderName)
-		if builder == nil {
+		builder, rateLimiter := d.getBuilder(builderName)
+		if builder == nil || !builder.supportsNetwork(bundle.GetNetworkNum()) {
+			continue
+		}
+		if rateLimiter != nil {
+			if allowed, _ := rateLimiter.Take(); !allowed {
+				log.Warnf("MEV bundle %v is not sent to %v, the rate limit of the builder is %v bundles per second", bundle.BundleHash, builder.Name, builder.RateLimit)
+				continue
+			}
+		}
+
+		json, err := d.bundleJSON(bundle, builder)
+		if err != nil {
+			log.Errorf("failed to create new mevBundle http request for bundleHash: %v, builder: %v, err: %v", bundle.BundleHash, builder.Name, err)
 			continue
 		}
 
 		for _, endpoint := range builder.Endpoints {
 			wg.Add(1)
 			go func(endpoint string) {
-				d.sendBundleToBuilder(endpoint, builder, bundle, json)
+				d.sendBundleToBuilder(endpoint, builder, bundle, json, deadline)
 				wg.Done()
 			}(endpoint)
 		}
@@ -155,6 +265,8 @@ type bundleRequest struct {
 	blockNumber string
 	method      string
 	request     *http.Request
+	// body is sent again by the retries of the request
+	body []byte
 }
 
 // String returns a string representation of the request
@@ -163,7 +275,7 @@ func (r *bundleRequest) String() string {
 }
 
 // sendBundleToBuilder sends MEVBundle or CancelBundle request to MEVBuilder
-func (d *Dispatcher) sendBundleToBuilder(endpoint string, builder *Builder, bundle *bxmessage.MEVBundle, json []byte) {
+func (d *Dispatcher) sendBundleToBuilder(endpoint string, builder *Builder, bundle *bxmessage.MEVBundle, json []byte, deadline time.Time) {
 	lg := log.WithFields(log.Fields{
 		"builder":         builder.Name,
 		"builderEndpoint": endpoint,
@@ -176,7 +288,7 @@ func (d *Dispatcher) sendBundleToBuilder(endpoint string, builder *Builder, bund
 
 	if builder.Name == bxgateway.FlashbotsBuilderName && len(bundle.Transactions) == 0 {
 		cancelBundle = true
-		bundleRq, err = d.cancelBundleRequest(endpoint, bundle)
+		bundleRq, err = d.cancelBundleRequest(endpoint, builder, bundle)
 		if err != nil {
 			lg.Errorf("failed to create cancelBundleRequest for MEVBuilder: %s", err)
 			return
@@ -189,11 +301,15 @@ func (d *Dispatcher) sendBundleToBuilder(endpoint string, builder *Builder, bund
 		}
 	}
 
-	rsp, err := d.do(bundleRq, json)
+	rsp, err := d.doWithRetries(lg, builder, endpoint, bundleRq, json, deadline)
 	if err != nil {
 		lg.Errorf("do HTTP request to MEVBuilder: %s", err)
 		return
 	}
+	if rsp == nil {
+		lg.Tracef("skipped MEVBuilder endpoint (request: %s), its circuit is open", bundleRq)
+		return
+	}
 
 	if cancelBundle {
 		lg.Tracef("sent CancelMEVBundle to MEVBuilder (request: %s, json: '%s') got response: %v, status code: %v", bundleRq, json, string(rsp.body), rsp.code)
@@ -220,10 +336,51 @@ type doResult struct {
 	estimatedBundleReceivedTime time.Time
 }
 
+// doWithRetries performs the HTTP request to the MEVBuilder endpoint, and retries it with a backoff while it fails and
+// the deadline is not reached. It returns a nil result if the circuit of the endpoint is open
+func (d *Dispatcher) doWithRetries(lg *log.Entry, builder *Builder, endpoint string, bundleRq *bundleRequest, json []byte, deadline time.Time) (*doResult, error) {
+	backoff := bundleRetryBackoff
+	for attempt := 1; ; attempt++ {
+		if !d.health.allow(builder.Name, endpoint, d.clock.Now()) {
+			return nil, nil
+		}
+
+		start := time.Now()
+		rsp, err := d.do(bundleRq, json)
+		var failure string
+		switch {
+		case err != nil:
+			failure = err.Error()
+		case rsp.code >= http.StatusInternalServerError || rsp.code == http.StatusTooManyRequests:
+			failure = fmt.Sprintf("status code %v: %s", rsp.code, rsp.body)
+		}
+		if d.health.record(builder.Name, endpoint, d.clock.Now(), time.Since(start), failure, attempt > 1) {
+			lg.Warnf("MEVBuilder endpoint failed %v times in a row, skipping it for %v: %v", circuitBreakerFailures, circuitBreakerCooldown, failure)
+		}
+		if failure == "" || attempt == maxBundleAttempts {
+			return rsp, err
+		}
+
+		delay := backoff + time.Duration(rand.Int63n(int64(backoff)))
+		if d.clock.Now().Add(delay).After(deadline) {
+			return rsp, err
+		}
+		lg.Debugf("retrying request to MEVBuilder in %v (request: %s): %v", delay, bundleRq, failure)
+		time.Sleep(delay)
+		backoff *= 2
+	}
+}
+
 // do performs HTTP request to provided MEVBuilder and records BuilderGatewaySentBundleToBuilder event
 func (d *Dispatcher) do(bundleRq *bundleRequest, json []byte) (rsp *doResult, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), bundleAttemptTimeout)
+	defer cancel()
+
+	request := bundleRq.request.Clone(ctx)
+	request.Body = io.NopCloser(bytes.NewReader(bundleRq.body))
+
 	timeBeforeSendingBundle := time.Now()
-	resp, err := d.client.Do(bundleRq.request)
+	resp, err := d.client.Do(request)
 	if err != nil {
 		return nil, fmt.Errorf("forward MEVBundle (request: %s, json: '%s'): %v", bundleRq, json, err)
 	}
@@ -244,16 +401,22 @@ func (d *Dispatcher) do(bundleRq *bundleRequest, json []byte) (rsp *doResult, er
 	}, nil
 }
 
-func (d *Dispatcher) getBuilder(builder string) *Builder {
+func (d *Dispatcher) getBuilder(builder string) (*Builder, utils.RateLimiter) {
+	d.buildersLock.RLock()
+	defer d.buildersLock.RUnlock()
+
 	if len(d.builders) > 0 {
-		return d.builders[builder]
+		return d.builders[builder], d.rateLimiters[builder]
 
 	}
 
-	return nil
+	return nil, nil
 }
 
 func (d *Dispatcher) getAllBuilders() map[string]string {
+	d.buildersLock.RLock()
+	defer d.buildersLock.RUnlock()
+
 	builders := make(map[string]string)
 	for name, builder := range d.builders {
 		builders[name] = builder.Name
@@ -268,6 +431,9 @@ func (d *Dispatcher) bundleRequest(endpoint string, builder *Builder, bundle *bx
 		return nil, fmt.Errorf("failed to create http request: %v", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	for name, value := range builder.Headers {
+		req.Header.Set(name, value)
+	}
 
 	if builder.SignatureRequired {
 		if bundle.MEVBuilders[builder.Name] == "" {
@@ -285,12 +451,13 @@ func (d *Dispatcher) bundleRequest(endpoint string, builder *Builder, bundle *bx
 	return &bundleRequest{
 		bundleHash:  bundle.BundleHash,
 		blockNumber: bundle.BlockNumber,
-		method:      string(jsonrpc.RPCEthSendBundle),
+		method:      d.builderMethod(builder),
 		request:     req,
+		body:        json,
 	}, nil
 }
 
-func (d *Dispatcher) cancelBundleRequest(endpoint string, bundle *bxmessage.MEVBundle) (*bundleRequest, error) {
+func (d *Dispatcher) cancelBundleRequest(endpoint string, builder *Builder, bundle *bxmessage.MEVBundle) (*bundleRequest, error) {
 	params := []jsonrpc.RPCCancelBundlePayload{
 		{
 			ReplacementUUID: bundle.UUID,
@@ -316,6 +483,9 @@ func (d *Dispatcher) cancelBundleRequest(endpoint string, bundle *bxmessage.MEVB
 		return nil, fmt.Errorf("failed to create http request: err: %v", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	for name, value := range builder.Headers {
+		req.Header.Set(name, value)
+	}
 
 	flashbotsSignature, err := generateRandomFlashbotsSignature(buf.Bytes())
 	if err != nil {
@@ -328,6 +498,7 @@ func (d *Dispatcher) cancelBundleRequest(endpoint string, bundle *bxmessage.MEVB
 		blockNumber: bundle.BlockNumber,
 		method:      string(jsonrpc.RPCEthCancelBundle),
 		request:     req,
+		body:        buf.Bytes(),
 	}, nil
 }
 
@@ -10,11 +10,15 @@ import (
 	"testing"
 	"time"
 
+	"github.com/bloXroute-Labs/gateway/v2"
 	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
 	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
 	"github.com/bloXroute-Labs/gateway/v2/services/statistics"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
 	"github.com/sourcegraph/jsonrpc2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -331,7 +335,7 @@ func TestDispatcher(t *testing.T) {
 			}))
 			defer server.Close()
 
-			d := NewDispatcher(statistics.NoStats{}, makeBuildersMap(fmt.Sprintf("%s/", server.URL), tc.builders), tc.mevMaxProfitBuilder, true)
+			d := NewDispatcher(statistics.NoStats{}, makeBuildersMap(fmt.Sprintf("%s/", server.URL), tc.builders), "", tc.mevMaxProfitBuilder, true)
 			err := d.Dispatch(&bundle)
 			assert.NoError(t, err)
 
@@ -341,3 +345,158 @@ func TestDispatcher(t *testing.T) {
 		})
 	}
 }
+
+func TestDispatcherRetriesAndCircuitBreaker(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests int
+		failing  = 2
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		if failing != 0 {
+			failing--
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	sent := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return requests
+	}
+
+	clock := &utils.MockClock{}
+	clock.SetTime(time.Now())
+	d := NewDispatcher(statistics.NoStats{}, makeBuildersMap(fmt.Sprintf("%s/", server.URL), []string{"builder1"}), "", false, false)
+	d.clock = clock
+	bundle := &bxmessage.MEVBundle{
+		Method:       string(jsonrpc.RPCEthSendBundle),
+		Transactions: []string{testTx1},
+		BlockNumber:  fmt.Sprintf("0x%x", 123),
+		MEVBuilders:  bxmessage.MEVBundleBuilders{"builder1": ""},
+	}
+
+	// the failed requests are retried before the target block
+	d.OnBlock(121, uint64(clock.Now().Unix()))
+	assert.NoError(t, d.Dispatch(bundle))
+	health := d.Health()
+	require.Len(t, health, 1)
+	assert.Equal(t, 3, sent())
+	assert.Equal(t, uint64(3), health[0].Requests)
+	assert.Equal(t, uint64(2), health[0].Failures)
+	assert.Equal(t, uint64(2), health[0].Retries)
+	assert.Equal(t, 0, health[0].ConsecutiveFailures)
+	assert.Equal(t, CircuitClosed, health[0].State)
+
+	// the target block is built, so the failed requests are not retried and open the circuit
+	d.OnBlock(123, uint64(clock.Now().Unix()))
+	mu.Lock()
+	failing = circuitBreakerFailures
+	mu.Unlock()
+	for i := 0; i < circuitBreakerFailures+1; i++ {
+		assert.NoError(t, d.Dispatch(bundle))
+	}
+	health = d.Health()
+	assert.Equal(t, 3+circuitBreakerFailures, sent())
+	assert.Equal(t, CircuitOpen, health[0].State)
+	assert.Equal(t, uint64(1), health[0].Skipped)
+	assert.Contains(t, health[0].LastError, "503")
+
+	// a single request probes the endpoint after the cooldown
+	clock.IncTime(circuitBreakerCooldown)
+	assert.NoError(t, d.Dispatch(bundle))
+	health = d.Health()
+	assert.Equal(t, 4+circuitBreakerFailures, sent())
+	assert.Equal(t, CircuitClosed, health[0].State)
+}
+
+func TestDispatcherBuilderSettings(t *testing.T) {
+	type builderRequest struct {
+		method  string
+		apiKey  string
+		payload map[string]interface{}
+	}
+	var (
+		mu       sync.Mutex
+		received = make(map[string][]builderRequest)
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req jsonrpc2.Request
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		var payload []map[string]interface{}
+		assert.NoError(t, json.Unmarshal(*req.Params, &payload))
+
+		mu.Lock()
+		defer mu.Unlock()
+		path := strings.TrimPrefix(r.URL.Path, "/")
+		received[path] = append(received[path], builderRequest{method: req.Method, apiKey: r.Header.Get("X-Api-Key"), payload: payload[0]})
+	}))
+	defer server.Close()
+
+	refundPercent := 90
+	builders := map[string]*Builder{
+		"builder1": {Endpoints: []string{server.URL + "/builder1"}},
+		"builder2": {
+			Endpoints:       []string{server.URL + "/builder2"},
+			Headers:         map[string]string{"X-Api-Key": "key"},
+			Method:          "eth_sendBundleV2",
+			RateLimit:       1,
+			ReplacementUUID: true,
+			RefundPercent:   &refundPercent,
+		},
+		"builder3": {Endpoints: []string{server.URL + "/builder3"}, Networks: []types.NetworkNum{36}},
+	}
+	d := NewDispatcher(statistics.NoStats{}, builders, "eth_sendCustomBundle", false, false)
+	bundle := &bxmessage.MEVBundle{
+		Method:       string(jsonrpc.RPCEthSendBundle),
+		Transactions: []string{testTx1},
+		UUID:         "e2a1c984-b31c-4bc6-a2eb-d2d903aab6d8",
+		BlockNumber:  fmt.Sprintf("0x%x", 123),
+		MEVBuilders:  bxmessage.MEVBundleBuilders{bxgateway.AllBuilderName: ""},
+	}
+	bundle.SetNetworkNum(bxgateway.MainnetNum)
+
+	requests := func(builder string) []builderRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return received[builder]
+	}
+
+	// the second bundle exceeds the rate limit of builder2
+	assert.NoError(t, d.Dispatch(bundle))
+	assert.NoError(t, d.Dispatch(bundle))
+
+	builder1 := requests("builder1")
+	require.Len(t, builder1, 2)
+	assert.Equal(t, "eth_sendCustomBundle", builder1[0].method)
+	assert.Equal(t, bundle.UUID, builder1[0].payload["uuid"])
+	assert.NotContains(t, builder1[0].payload, "refundPercent")
+
+	builder2 := requests("builder2")
+	require.Len(t, builder2, 1)
+	assert.Equal(t, "eth_sendBundleV2", builder2[0].method)
+	assert.Equal(t, "key", builder2[0].apiKey)
+	assert.Equal(t, bundle.UUID, builder2[0].payload["replacementUuid"])
+	assert.NotContains(t, builder2[0].payload, "uuid")
+	assert.Equal(t, float64(90), builder2[0].payload["refundPercent"])
+
+	// builder3 only builds the blocks of another network
+	assert.Empty(t, requests("builder3"))
+
+	// builder2 keeps its rate limiter while its rate limit is unchanged
+	d.SetBuilders(map[string]*Builder{"builder2": {Endpoints: []string{server.URL + "/builder2"}, RateLimit: 1}})
+	assert.NoError(t, d.Dispatch(bundle))
+	assert.Len(t, requests("builder2"), 1)
+	d.SetBuilders(map[string]*Builder{"builder2": {Endpoints: []string{server.URL + "/builder2"}, RateLimit: 2}})
+	assert.NoError(t, d.Dispatch(bundle))
+	assert.Len(t, requests("builder2"), 2)
+
+	// the builders are replaced without restarting the dispatcher
+	d.SetBuilders(map[string]*Builder{"builder3": {Endpoints: []string{server.URL + "/builder3"}}})
+	assert.NoError(t, d.Dispatch(bundle))
+	assert.Len(t, requests("builder1"), 2)
+	assert.Len(t, requests("builder3"), 1)
+}
@@ -0,0 +1,154 @@
+package bundle
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// circuitBreakerFailures is the number of consecutive failed requests which opens the circuit of an endpoint
+	circuitBreakerFailures = 5
+	// circuitBreakerCooldown is how long an open circuit skips the endpoint before a single request probes it again
+	circuitBreakerCooldown = 30 * time.Second
+	// latencySmoothing is the weight of the latest request in the average latency of an endpoint
+	latencySmoothing = 0.2
+)
+
+// CircuitState is the state of the circuit breaker of a MEV builder endpoint
+type CircuitState string
+
+// CircuitState enumeration
+const (
+	// CircuitClosed sends the bundles to the endpoint
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen skips the endpoint, it has been failing
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen sends a single request to the endpoint to probe whether it recovered
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+// EndpointHealth is the health of a MEV builder endpoint observed by the dispatcher
+type EndpointHealth struct {
+	Builder  string
+	Endpoint string
+	State    CircuitState
+	// Requests and Failures count the HTTP requests, a bundle retried is counted once per attempt
+	Requests            uint64
+	Failures            uint64
+	Retries             uint64
+	Skipped             uint64
+	ConsecutiveFailures int
+	AverageLatency      time.Duration
+	LastError           string
+	LastErrorTime       time.Time
+	LastSuccessTime     time.Time
+}
+
+type endpointHealth struct {
+	EndpointHealth
+	openedAt time.Time
+	probing  bool
+}
+
+type builderHealth struct {
+	lock      sync.Mutex
+	endpoints map[string]*endpointHealth
+}
+
+func newBuilderHealth() *builderHealth {
+	return &builderHealth{endpoints: make(map[string]*endpointHealth)}
+}
+
+func (b *builderHealth) endpoint(builder, endpoint string) *endpointHealth {
+	key := builder + " " + endpoint
+	h, ok := b.endpoints[key]
+	if !ok {
+		h = &endpointHealth{EndpointHealth: EndpointHealth{Builder: builder, Endpoint: endpoint, State: CircuitClosed}}
+		b.endpoints[key] = h
+	}
+	return h
+}
+
+// allow returns true if a request can be sent to the endpoint, an open circuit lets a single request probe the
+// endpoint once its cooldown passed
+func (b *builderHealth) allow(builder, endpoint string, now time.Time) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	h := b.endpoint(builder, endpoint)
+	switch h.State {
+	case CircuitOpen:
+		if now.Sub(h.openedAt) < circuitBreakerCooldown {
+			h.Skipped++
+			return false
+		}
+		h.State = CircuitHalfOpen
+		h.probing = true
+		return true
+	case CircuitHalfOpen:
+		if h.probing {
+			h.Skipped++
+			return false
+		}
+		h.probing = true
+		return true
+	}
+	return true
+}
+
+// record adds the outcome of a request to the endpoint, failure is empty if the request succeeded. It returns true if
+// the failure opened the circuit of an endpoint which was healthy
+func (b *builderHealth) record(builder, endpoint string, now time.Time, latency time.Duration, failure string, retry bool) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	h := b.endpoint(builder, endpoint)
+	h.Requests++
+	if retry {
+		h.Retries++
+	}
+	if h.AverageLatency == 0 {
+		h.AverageLatency = latency
+	} else {
+		h.AverageLatency = time.Duration(latencySmoothing*float64(latency) + (1-latencySmoothing)*float64(h.AverageLatency))
+	}
+
+	probe := h.State == CircuitHalfOpen
+	h.probing = false
+	if failure == "" {
+		h.ConsecutiveFailures = 0
+		h.LastSuccessTime = now
+		h.State = CircuitClosed
+		return false
+	}
+
+	h.Failures++
+	h.ConsecutiveFailures++
+	h.LastError = failure
+	h.LastErrorTime = now
+	if probe || (h.State == CircuitClosed && h.ConsecutiveFailures >= circuitBreakerFailures) {
+		h.State = CircuitOpen
+		h.openedAt = now
+		return !probe
+	}
+	return false
+}
+
+// snapshot returns the health of the endpoints sorted by builder and endpoint
+func (b *builderHealth) snapshot() []EndpointHealth {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	health := make([]EndpointHealth, 0, len(b.endpoints))
+	for _, h := range b.endpoints {
+		health = append(health, h.EndpointHealth)
+	}
+	sort.Slice(health, func(i, j int) bool {
+		if health[i].Builder != health[j].Builder {
+			return health[i].Builder < health[j].Builder
+		}
+		return health[i].Endpoint < health[j].Endpoint
+	})
+	return health
+}
@@ -106,6 +106,11 @@ var (
 		Usage: "port for HTTP server to run on",
 		Value: 28335,
 	}
+	MetricsPortFlag = &cli.IntFlag{
+		Name:  "metrics-port",
+		Usage: "port for the prometheus metrics server (0 disables the metrics server)",
+		Value: 0,
+	}
 	CACertURLFlag = &cli.StringFlag{
 		Name:  "ca-cert-url",
 		Usage: "URL for retrieving CA certificates",
@@ -123,6 +128,35 @@ var (
 		Value:  false,
 		Hidden: true,
 	}
+	StatsBackendFlag = &cli.StringFlag{
+		Name:  "stats-backend",
+		Usage: "comma separated list of backends receiving the stats records: fluentd, file, nats, kafka (--fluentd implies fluentd)",
+	}
+	StatsFileFlag = &cli.StringFlag{
+		Name:  "stats-file",
+		Usage: "path of the JSON lines file written by the file stats backend",
+		Value: "logs/stats.jsonl",
+	}
+	StatsFileMaxSizeFlag = &cli.IntFlag{
+		Name:  "stats-file-max-size",
+		Usage: "size in megabytes at which the stats file is rotated",
+		Value: 100,
+	}
+	StatsFileMaxBackupsFlag = &cli.IntFlag{
+		Name:  "stats-file-max-backups",
+		Usage: "number of rotated stats files to keep",
+		Value: 10,
+	}
+	StatsNATSURLFlag = &cli.StringFlag{
+		Name:  "stats-nats-url",
+		Usage: "URL of the NATS server the nats stats backend publishes to, the subject of a record is its stats name",
+		Value: "nats://localhost:4222",
+	}
+	StatsKafkaURLFlag = &cli.StringFlag{
+		Name:  "stats-kafka-url",
+		Usage: "URL of the Kafka REST proxy the kafka stats backend produces to, the topic of a record is its stats name",
+		Value: "http://localhost:8082",
+	}
 	LogNetworkContentFlag = &cli.BoolFlag{
 		Name:   "log-network-content",
 		Usage:  "sends blockchain content to fluentD",
@@ -292,6 +326,10 @@ var (
 		Usage: "for gateways only, sets max number of backup tx trace log files retained (0 enables unlimited backups)",
 		Value: 3,
 	}
+	TxTraceOTLPEndpointFlag = &cli.StringFlag{
+		Name:  "txtrace-otlp-endpoint",
+		Usage: "for gateways only, with --txtrace exports a trace of the lifecycle of the transactions sent to the gateway to the OTLP gRPC collector at this address (host:port)",
+	}
 	TxCheckerPoolCapacity = &cli.IntFlag{
 		Name:  "tx-checker-pool-capacity",
 		Usage: "for relays only, sets max number of workers that check transaction if it is valid (0 disable checks)",
@@ -322,6 +360,12 @@ var (
 		Usage:  "set mev builders file path for gateway",
 		Hidden: true,
 	}
+	MEVBuildersReloadIntervalFlag = &cli.DurationFlag{
+		Name:   "mev-builders-reload-interval",
+		Usage:  "interval between checks of the mev builders file for changes, 0 disables the reload",
+		Value:  10 * time.Second,
+		Hidden: true,
+	}
 	MEVBundleMethodNameFlag = &cli.StringFlag{
 		Name:  "mev-bundle-method-name",
 		Usage: "set custom method for mevBundle request",
@@ -497,4 +541,38 @@ var (
 		Hidden: true,
 		Value:  false,
 	}
+	ReorgReemitFeeds = &cli.BoolFlag{
+		Name:  "reorg-reemit-feeds",
+		Usage: "(for gateways only) send the txReceipts and ethOnBlock notifications again for the blocks that become canonical after a chain reorganization",
+		Value: false,
+	}
+	FeedHistorySize = &cli.IntFlag{
+		Name:  "feed-history-size",
+		Usage: "(for gateways only) number of notifications of each of the feed-history-feeds kept in the data dir for subscriptions resuming with resume_from (0 disables the feed history)",
+		Value: 0,
+	}
+	FeedHistoryFeeds = &cli.StringFlag{
+		Name:  "feed-history-feeds",
+		Usage: "(for gateways only) comma separated feeds keeping a history, among newBlocks, bdnBlocks, txReceipts and transactionStatus. The blocks are processed even without subscriptions while a block feed keeps a history",
+		Value: "transactionStatus",
+	}
+	SanctionsListFlag = &cli.StringFlag{
+		Name:  "sanctions-list",
+		Usage: "JSON or CSV file, or HTTP(S) URL, of the sanctioned addresses replacing the built-in OFAC list, reloaded when it changes",
+	}
+	SanctionsListReloadIntervalFlag = &cli.DurationFlag{
+		Name:  "sanctions-list-reload-interval",
+		Usage: "interval between checks of the sanctions list for changes, 0 disables the reload",
+		Value: time.Minute,
+	}
+	BlockSanctionedTxsFlag = &cli.BoolFlag{
+		Name:  "block-sanctioned-txs",
+		Usage: "(for gateways only) reject the transactions sent with blxr_tx and eth_sendRawTransaction which involve sanctioned addresses, following the sanctions policy of the account",
+		Value: false,
+	}
+	PersistTxStore = &cli.BoolFlag{
+		Name:  "persist-tx-store",
+		Usage: "(for gateways only) store the transactions and their short IDs in the data dir periodically and on shutdown, and reload them on startup",
+		Value: false,
+	}
 )
@@ -0,0 +1,98 @@
+package ofac
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+// Policy is how the transactions of an account involving sanctioned addresses are handled
+type Policy string
+
+// Policy types
+const (
+	// PolicyBlock rejects the transaction
+	PolicyBlock Policy = "block"
+	// PolicyReport lets the transaction through, only reporting the sanctioned addresses
+	PolicyReport Policy = "report"
+)
+
+// List is an immutable set of sanctioned addresses with the policies of the accounts
+type List struct {
+	addresses     map[string]struct{}
+	defaultPolicy Policy
+	policies      map[types.AccountID]Policy
+}
+
+var current atomic.Pointer[List]
+
+func init() {
+	addresses := make([]string, 0, len(sanctionList))
+	for address := range sanctionList {
+		addresses = append(addresses, address)
+	}
+	list, _ := NewList(addresses, PolicyBlock, nil)
+	SetList(list)
+}
+
+// NewList creates a list of the sanctioned addresses, accounts without a policy use the default policy
+func NewList(addresses []string, defaultPolicy Policy, policies map[types.AccountID]Policy) (*List, error) {
+	if err := defaultPolicy.validate(); err != nil {
+		return nil, err
+	}
+	for accountID, policy := range policies {
+		if err := policy.validate(); err != nil {
+			return nil, fmt.Errorf("account %v: %v", accountID, err)
+		}
+	}
+
+	l := &List{
+		addresses:     make(map[string]struct{}, len(addresses)),
+		defaultPolicy: defaultPolicy,
+		policies:      policies,
+	}
+	for _, address := range addresses {
+		l.addresses[strings.ToLower(address)] = struct{}{}
+	}
+	return l, nil
+}
+
+// CurrentList returns the list the transactions are checked against
+func CurrentList() *List {
+	return current.Load()
+}
+
+// SetList replaces the list the transactions are checked against
+func SetList(l *List) {
+	current.Store(l)
+}
+
+// Contains returns whether the address is sanctioned
+func (l *List) Contains(address string) bool {
+	_, ok := l.addresses[strings.ToLower(address)]
+	return ok
+}
+
+// Len returns the number of sanctioned addresses
+func (l *List) Len() int {
+	return len(l.addresses)
+}
+
+// Policy returns the policy of the account
+func (l *List) Policy(accountID types.AccountID) Policy {
+	if policy, ok := l.policies[accountID]; ok {
+		return policy
+	}
+	return l.defaultPolicy
+}
+
+func (p Policy) validate() error {
+	switch p {
+	case PolicyBlock, PolicyReport:
+		return nil
+	default:
+		return fmt.Errorf("invalid sanctions policy %v, possible policies are: [%v %v]", p, PolicyBlock, PolicyReport)
+	}
+}
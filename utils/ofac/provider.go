@@ -0,0 +1,222 @@
+package ofac
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const urlProviderTimeout = 30 * time.Second
+
+// Provider loads the sanctions list from its source
+type Provider interface {
+	// Load returns the list if the source changed since the previous load, nil otherwise
+	Load(ctx context.Context) (*List, error)
+	Source() string
+}
+
+// listFile is the JSON format of a sanctions list, a plain JSON array of addresses is also accepted
+type listFile struct {
+	Addresses       []string                   `json:"addresses"`
+	DefaultPolicy   Policy                     `json:"default_policy"`
+	AccountPolicies map[types.AccountID]Policy `json:"account_policies"`
+}
+
+// NewProvider creates the provider of a sanctions list loaded from an HTTP(S) URL or from a local file. A list whose
+// source ends with .csv has an address in the first column of each row, otherwise it is JSON
+func NewProvider(source string) Provider {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		return &urlProvider{url: source, client: &http.Client{Timeout: urlProviderTimeout}}
+	}
+	return &fileProvider{path: source}
+}
+
+// Watch loads the sanctions list of the provider, then reloads it every interval until ctx is done and
+// replaces the current list whenever it changes. A list which fails to load leaves the current list in place. The list
+// is loaded once if interval is not positive
+func Watch(ctx context.Context, provider Provider, interval time.Duration) error {
+	if err := reload(ctx, provider); err != nil {
+		return err
+	}
+	if interval <= 0 {
+		return nil
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := reload(ctx, provider); err != nil {
+					log.Errorf("failed to reload the sanctions list: %v", err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func reload(ctx context.Context, provider Provider) error {
+	list, err := provider.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load sanctions list from %v: %v", provider.Source(), err)
+	}
+	if list != nil {
+		SetList(list)
+		log.Infof("loaded %v sanctioned addresses from %v", list.Len(), provider.Source())
+	}
+	return nil
+}
+
+type fileProvider struct {
+	path    string
+	modTime time.Time
+	size    int64
+}
+
+func (f *fileProvider) Source() string {
+	return f.path
+}
+
+func (f *fileProvider) Load(context.Context) (*List, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return nil, err
+	}
+	if info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, err
+	}
+	list, err := parseList(data, strings.HasSuffix(strings.ToLower(f.path), ".csv"))
+	if err != nil {
+		return nil, err
+	}
+
+	f.modTime = info.ModTime()
+	f.size = info.Size()
+	return list, nil
+}
+
+type urlProvider struct {
+	url    string
+	client *http.Client
+	etag   string
+}
+
+func (u *urlProvider) Source() string {
+	return u.url
+}
+
+func (u *urlProvider) Load(ctx context.Context) (*List, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if u.etag != "" {
+		req.Header.Set("If-None-Match", u.etag)
+	}
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return nil, nil
+	case http.StatusOK:
+	default:
+		return nil, fmt.Errorf("unexpected status %v", resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	isCSV := strings.Contains(resp.Header.Get("Content-Type"), "csv") || strings.HasSuffix(strings.ToLower(req.URL.Path), ".csv")
+	list, err := parseList(data, isCSV)
+	if err != nil {
+		return nil, err
+	}
+
+	u.etag = resp.Header.Get("ETag")
+	return list, nil
+}
+
+func parseList(data []byte, isCSV bool) (*List, error) {
+	var file listFile
+	var err error
+	if isCSV {
+		file.Addresses, err = parseCSVAddresses(data)
+	} else {
+		err = parseJSONList(data, &file)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// an empty list is more likely a broken source than the end of the sanctions
+	if len(file.Addresses) == 0 {
+		return nil, errors.New("sanctions list is empty")
+	}
+	for _, address := range file.Addresses {
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("invalid sanctioned address %v", address)
+		}
+	}
+
+	if file.DefaultPolicy == "" {
+		file.DefaultPolicy = PolicyBlock
+	}
+	return NewList(file.Addresses, file.DefaultPolicy, file.AccountPolicies)
+}
+
+func parseJSONList(data []byte, file *listFile) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		return json.Unmarshal(trimmed, &file.Addresses)
+	}
+	return json.Unmarshal(data, file)
+}
+
+// parseCSVAddresses returns the first column of the rows, skipping a header row
+func parseCSVAddresses(data []byte) ([]string, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make([]string, 0, len(records))
+	for i, record := range records {
+		address := strings.TrimSpace(record[0])
+		if i == 0 && !strings.HasPrefix(address, "0x") {
+			continue
+		}
+		if address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses, nil
+}
@@ -0,0 +1,121 @@
+package ofac
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSanctioned1 = "0x1111111111111111111111111111111111111111"
+	testSanctioned2 = "0x2222222222222222222222222222222222222222"
+)
+
+func TestFileProvider(t *testing.T) {
+	dir := t.TempDir()
+
+	jsonPath := filepath.Join(dir, "sanctions.json")
+	require.NoError(t, os.WriteFile(jsonPath, []byte(`{"addresses": ["`+testSanctioned1+`"], "account_policies": {"a": "report"}}`), 0644))
+	provider := NewProvider(jsonPath)
+	list, err := provider.Load(context.Background())
+	require.NoError(t, err)
+	assert.True(t, list.Contains(testSanctioned1))
+	assert.False(t, list.Contains(testSanctioned2))
+	assert.Equal(t, PolicyReport, list.Policy("a"))
+	assert.Equal(t, PolicyBlock, list.Policy("b"))
+
+	// unchanged file is not reloaded
+	list, err = provider.Load(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, list)
+
+	arrayPath := filepath.Join(dir, "array.json")
+	require.NoError(t, os.WriteFile(arrayPath, []byte(`["`+testSanctioned1+`", "`+testSanctioned2+`"]`), 0644))
+	list, err = NewProvider(arrayPath).Load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, list.Len())
+
+	csvPath := filepath.Join(dir, "sanctions.csv")
+	require.NoError(t, os.WriteFile(csvPath, []byte("address,program\n"+testSanctioned1+",SDN\n"+testSanctioned2+",SDN\n"), 0644))
+	list, err = NewProvider(csvPath).Load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, list.Len())
+	assert.True(t, list.Contains(testSanctioned2))
+}
+
+func TestFileProviderInvalid(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string]string{
+		"empty.json":   `[]`,
+		"address.json": `["0x1234"]`,
+		"policy.json":  `{"addresses": ["` + testSanctioned1 + `"], "default_policy": "ignore"}`,
+		"empty.csv":    "address\n",
+	}
+	for name, content := range tests {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		_, err := NewProvider(path).Load(context.Background())
+		assert.Error(t, err, name)
+	}
+}
+
+func TestURLProvider(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "text/csv")
+		_, _ = w.Write([]byte(testSanctioned1 + "\n"))
+	}))
+	defer server.Close()
+
+	provider := NewProvider(server.URL + "/sanctions")
+	list, err := provider.Load(context.Background())
+	require.NoError(t, err)
+	assert.True(t, list.Contains(testSanctioned1))
+
+	list, err = provider.Load(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, list)
+	assert.Equal(t, 2, requests)
+}
+
+func TestWatch(t *testing.T) {
+	defer SetList(CurrentList())
+
+	path := filepath.Join(t.TempDir(), "sanctions.json")
+	require.NoError(t, os.WriteFile(path, []byte(`["`+testSanctioned1+`"]`), 0644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, Watch(ctx, NewProvider(path), 10*time.Millisecond))
+	assert.True(t, CurrentList().Contains(testSanctioned1))
+
+	// a broken list keeps the current one
+	require.NoError(t, os.WriteFile(path, []byte(`[`), 0644))
+	time.Sleep(50 * time.Millisecond)
+	assert.True(t, CurrentList().Contains(testSanctioned1))
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"addresses": ["`+testSanctioned2+`"], "default_policy": "report"}`), 0644))
+	assert.Eventually(t, func() bool { return CurrentList().Contains(testSanctioned2) }, time.Second, 5*time.Millisecond)
+	assert.False(t, CurrentList().Contains(testSanctioned1))
+	assert.Equal(t, PolicyReport, CurrentList().Policy(types.AccountID("a")))
+
+	assert.Error(t, Watch(ctx, NewProvider(filepath.Join(t.TempDir(), "missing.json")), time.Minute))
+
+	// the list is loaded once without an interval
+	require.NoError(t, Watch(ctx, NewProvider(path), 0))
+	assert.True(t, CurrentList().Contains(testSanctioned2))
+}
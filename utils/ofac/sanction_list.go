@@ -1,12 +1,18 @@
 package ofac
 
 import (
+	"encoding/hex"
+	"fmt"
 	"strings"
 
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/ethereum/go-ethereum/common"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
 )
 
-// sanctionList is a map of OFAC sanctioned addresses
+// sanctionList is a map of OFAC sanctioned addresses, the list used until a sanctions provider is configured
 var sanctionList = map[string]bool{
 	"0x8576acc5c05d6ce88f4e49bf65bdf0c62f91353c": true,
 	"0x901bb9583b24d97e995513c6778dc6888ab6870e": true,
@@ -152,30 +158,108 @@ var sanctionList = map[string]bool{
 	"0x6be0ae71e6c41f2f9d0d1a3b8d0f75e6f6a0b46e": true,
 }
 
-// ShouldBlockTransaction checks the sanction list to see if 'from' or 'to' are on block list and returns any blocked addresses for stats
-func ShouldBlockTransaction(transaction *ethtypes.Transaction) ([]string, bool) {
+// Reason is why a transaction is blocked
+type Reason string
+
+// Reason types, from the most to the least direct involvement of the sanctioned address
+const (
+	ReasonSender        Reason = "sanctioned_sender"
+	ReasonRecipient     Reason = "sanctioned_recipient"
+	ReasonTokenTransfer Reason = "sanctioned_token_transfer"
+	ReasonAccessList    Reason = "sanctioned_access_list"
+)
+
+// token transfer and approval methods whose address arguments are checked
+var tokenTransferMethods = map[string]int{
+	"a9059cbb": 1, // transfer(address,uint256)
+	"095ea7b3": 1, // approve(address,uint256)
+	"23b872dd": 2, // transferFrom(address,address,uint256)
+	"42842e0e": 2, // safeTransferFrom(address,address,uint256)
+	"b88d4fde": 2, // safeTransferFrom(address,address,uint256,bytes)
+}
+
+// Result is the sanctioned addresses a blocked transaction involves
+type Result struct {
+	Addresses []string
+	// Reason is the most direct involvement of a sanctioned address
+	Reason Reason
+}
+
+// BlockedError is returned for a transaction rejected because it involves sanctioned addresses
+type BlockedError struct {
+	Result
+}
+
+func (e *BlockedError) Error() string {
+	return fmt.Sprintf("transaction is blocked (%v), sanctioned addresses %v", e.Reason, strings.Join(e.Addresses, ", "))
+}
+
+// ShouldBlockTransaction checks the sanction list to see if 'from', 'to', the token transfer calldata or the access list
+// involve addresses on block list and returns the blocked addresses for stats with the reason
+func ShouldBlockTransaction(transaction *ethtypes.Transaction) (Result, bool) {
 	sender, err := ethtypes.NewLondonSigner(transaction.ChainId()).Sender(transaction)
 	if err != nil {
-		return nil, false
+		return Result{}, false
 	}
-	fromAddress := strings.ToLower(sender.Hex())
 
-	toAddress := ""
+	list := CurrentList()
+	var result Result
+	check := func(address common.Address, reason Reason) {
+		hex := strings.ToLower(address.Hex())
+		if !list.Contains(hex) || utils.Exists(hex, result.Addresses) {
+			return
+		}
+		result.Addresses = append(result.Addresses, hex)
+		if result.Reason == "" {
+			result.Reason = reason
+		}
+	}
+
+	check(sender, ReasonSender)
 	if transaction.To() != nil {
-		toAddress = strings.ToLower(transaction.To().Hex())
+		check(*transaction.To(), ReasonRecipient)
+	}
+	for _, address := range tokenTransferAddresses(transaction.Data()) {
+		check(address, ReasonTokenTransfer)
+	}
+	for _, tuple := range transaction.AccessList() {
+		check(tuple.Address, ReasonAccessList)
+	}
+
+	if len(result.Addresses) == 0 {
+		return Result{}, false
 	}
+	return result, true
+}
 
-	blockedAddresses := make([]string, 0, 2)
+// CheckTransaction returns a BlockedError if the transaction involves sanctioned addresses and the policy of the
+// account is to block them
+func CheckTransaction(transaction *ethtypes.Transaction, accountID types.AccountID) error {
+	result, shouldBlock := ShouldBlockTransaction(transaction)
+	if !shouldBlock {
+		return nil
+	}
+	if CurrentList().Policy(accountID) == PolicyReport {
+		log.Infof("transaction %v of account %v involves sanctioned addresses %v (%v)", transaction.Hash(), accountID, result.Addresses, result.Reason)
+		return nil
+	}
+	return &BlockedError{Result: result}
+}
 
-	if _, found := sanctionList[fromAddress]; found {
-		blockedAddresses = append(blockedAddresses, fromAddress)
+// tokenTransferAddresses returns the address arguments of the token transfer and approval calldata
+func tokenTransferAddresses(data []byte) []common.Address {
+	if len(data) < 4 {
+		return nil
 	}
-	if _, found := sanctionList[toAddress]; found && fromAddress != toAddress {
-		blockedAddresses = append(blockedAddresses, toAddress)
+	count, ok := tokenTransferMethods[hex.EncodeToString(data[:4])]
+	if !ok || len(data) < 4+count*common.HashLength {
+		return nil
 	}
 
-	if len(blockedAddresses) == 0 {
-		return nil, false
+	addresses := make([]common.Address, 0, count)
+	for i := 0; i < count; i++ {
+		word := data[4+i*common.HashLength : 4+(i+1)*common.HashLength]
+		addresses = append(addresses, common.BytesToAddress(word))
 	}
-	return blockedAddresses, true
+	return addresses
 }
@@ -1,12 +1,17 @@
 package ofac
 
 import (
+	"math/big"
+	"strings"
 	"testing"
 
 	"github.com/bloXroute-Labs/gateway/v2/test/fixtures"
 	types "github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/ethereum/go-ethereum/common"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func createTransaction(rawTx string) (*ethtypes.Transaction, error) {
@@ -30,8 +35,9 @@ func TestSanctionList_ShouldBlockTransaction_True(t *testing.T) {
 	tx, err := createTransaction(sanctionedTx)
 	assert.NoError(t, err)
 
-	addresses, shouldBlock := ShouldBlockTransaction(tx)
-	assert.Contains(t, addresses, blockedAddress)
+	result, shouldBlock := ShouldBlockTransaction(tx)
+	assert.Contains(t, result.Addresses, blockedAddress)
+	assert.Equal(t, ReasonRecipient, result.Reason)
 	assert.True(t, shouldBlock)
 }
 
@@ -39,7 +45,57 @@ func TestSanctionList_ShouldBlockTransaction_False(t *testing.T) {
 	tx, err := createTransaction(fixtures.LegacyTransaction)
 	assert.NoError(t, err)
 
-	addresses, shouldBlock := ShouldBlockTransaction(tx)
-	assert.Empty(t, addresses)
+	result, shouldBlock := ShouldBlockTransaction(tx)
+	assert.Empty(t, result.Addresses)
 	assert.False(t, shouldBlock)
 }
+
+func signTestTransaction(t *testing.T, txData ethtypes.TxData) *ethtypes.Transaction {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	tx, err := ethtypes.SignNewTx(key, ethtypes.NewLondonSigner(big.NewInt(1)), txData)
+	require.NoError(t, err)
+	return tx
+}
+
+func TestSanctionList_ShouldBlockTransaction_TokenTransferAndAccessList(t *testing.T) {
+	blocked := common.HexToAddress("0x8576acc5c05d6ce88f4e49bf65bdf0c62f91353c")
+	token := common.HexToAddress("0xdac17f958d2ee523a2206206994597c13d831ec7")
+
+	transfer := append(common.FromHex("a9059cbb"), common.LeftPadBytes(blocked.Bytes(), 32)...)
+	transfer = append(transfer, common.LeftPadBytes([]byte{1}, 32)...)
+	tx := signTestTransaction(t, &ethtypes.DynamicFeeTx{ChainID: big.NewInt(1), To: &token, Data: transfer, Gas: 100000})
+	result, shouldBlock := ShouldBlockTransaction(tx)
+	assert.True(t, shouldBlock)
+	assert.Equal(t, Result{Addresses: []string{strings.ToLower(blocked.Hex())}, Reason: ReasonTokenTransfer}, result)
+
+	// truncated calldata is not decoded
+	tx = signTestTransaction(t, &ethtypes.DynamicFeeTx{ChainID: big.NewInt(1), To: &token, Data: transfer[:20], Gas: 100000})
+	_, shouldBlock = ShouldBlockTransaction(tx)
+	assert.False(t, shouldBlock)
+
+	tx = signTestTransaction(t, &ethtypes.DynamicFeeTx{ChainID: big.NewInt(1), To: &token, Gas: 100000,
+		AccessList: ethtypes.AccessList{{Address: blocked}}})
+	result, shouldBlock = ShouldBlockTransaction(tx)
+	assert.True(t, shouldBlock)
+	assert.Equal(t, ReasonAccessList, result.Reason)
+}
+
+func TestSanctionList_CheckTransaction(t *testing.T) {
+	defer SetList(CurrentList())
+
+	blocked := common.HexToAddress("0x8576acc5c05d6ce88f4e49bf65bdf0c62f91353c")
+	tx := signTestTransaction(t, &ethtypes.DynamicFeeTx{ChainID: big.NewInt(1), To: &blocked, Gas: 21000})
+
+	err := CheckTransaction(tx, "account")
+	var blockedErr *BlockedError
+	require.ErrorAs(t, err, &blockedErr)
+	assert.Equal(t, ReasonRecipient, blockedErr.Reason)
+	assert.EqualError(t, err, "transaction is blocked (sanctioned_recipient), sanctioned addresses 0x8576acc5c05d6ce88f4e49bf65bdf0c62f91353c")
+
+	list, err := NewList([]string{blocked.Hex()}, PolicyBlock, map[types.AccountID]Policy{"reporter": PolicyReport})
+	require.NoError(t, err)
+	SetList(list)
+	assert.NoError(t, CheckTransaction(tx, "reporter"))
+	assert.Error(t, CheckTransaction(tx, "account"))
+}